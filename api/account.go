@@ -1,90 +1,112 @@
 package api
 
 import (
-	"database/sql"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/singhJasvinder101/go_bank/bank"
 	db "github.com/singhJasvinder101/go_bank/db/sqlc"
 )
 
 type createAccountParams struct {
-	Owner string `json:"owner" binding:"required"`
 	Currency string `json:"currency" binding:"required"`
 }
 
-func (server *Server) createAccount(ctx *gin.Context){
-	var req createAccountParams;
+func (server *Server) createAccount(ctx *gin.Context) {
+	var req createAccountParams
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	arg := db.CreateAccountParams{
-		Owner: req.Owner,
+		Owner:    authUser(ctx),
 		Currency: req.Currency,
-		Balance: 0,
+		Balance:  0,
 	}
 
-	account, err := server.store.CreateAccount(ctx, arg)
+	result, err := server.bank.CreateAccount(ctx, arg)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
-	
-	ctx.JSON(http.StatusOK, account)
+
+	ctx.JSON(http.StatusOK, newAccountResponse(result.Account, nil))
 }
 
 type getAccountRequestParams struct {
 	ID int64 `uri:"id" binding:"required,min=1"`
 }
 
-func (server *Server) getAccount(ctx *gin.Context){
+func (server *Server) getAccount(ctx *gin.Context) {
 	var req getAccountRequestParams
 
-	if err := ctx.ShouldBindUri(&req); err != nil{
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
+	if _, ok := server.authorizeAccount(ctx, req.ID, bank.AnyMember); !ok {
+		return
+	}
 
 	account, err := server.store.GetAccountById(ctx, req.ID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, account)
+	pots := []db.Account{}
+	if !account.ParentID.Valid {
+		pots, err = server.store.ListAccountPots(ctx, pgtype.Int8{Int64: account.ID, Valid: true})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+	}
+
+	numbers, ok := server.lookupAccountNumbers(ctx, []db.Account{account}, db.ParentIDs(account)...)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAccountDetailResponse(account, pots, numbers))
 }
 
 type listAccountRequestParams struct {
-	PageID int32 `form:"page_id" binding:"required,min=1"`
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
 	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
 }
 
-func (server *Server) listAccounts(ctx *gin.Context){
+func (server *Server) listAccounts(ctx *gin.Context) {
 	var req listAccountRequestParams
 
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
-	accounts, err := server.store.ListAccounts(ctx, db.ListAccountsParams{
-		Limit: req.PageSize,
-		Offset: (req.PageID - 1) * req.PageSize,
+	accounts, err := server.store.ListMemberAccounts(ctx, db.ListMemberAccountsParams{
+		Username: authUser(ctx),
+		Limit:    req.PageSize,
+		Offset:   (req.PageID - 1) * req.PageSize,
 	})
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, accounts)
-}
-
+	numbers, ok := server.lookupAccountNumbers(ctx, accounts, db.ParentIDs(accounts...)...)
+	if !ok {
+		return
+	}
 
+	ctx.JSON(http.StatusOK, newAccountResponses(accounts, numbers))
+}
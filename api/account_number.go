@@ -0,0 +1,69 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/utils"
+)
+
+// accountIDByNumber validates an account number and returns the id of its account.
+func (server *Server) accountIDByNumber(ctx *gin.Context, number string) (int64, bool) {
+	number = utils.NormalizeAccountNumber(number)
+	if err := utils.ValidateAccountNumber(number); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return 0, false
+	}
+
+	account, err := server.store.GetAccountByNumber(ctx, number)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return 0, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return 0, false
+	}
+
+	return account.ID, true
+}
+
+// resolveAccountNumbers lets the named path parameters hold an account number
+// instead of an account id. Account numbers are replaced by the id of their
+// account before the handler binds the uri.
+func (server *Server) resolveAccountNumbers(params ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		for i, param := range ctx.Params {
+			if !slices.Contains(params, param.Key) {
+				continue
+			}
+			if _, err := strconv.ParseInt(param.Value, 10, 64); err == nil {
+				continue
+			}
+
+			id, ok := server.accountIDByNumber(ctx, param.Value)
+			if !ok {
+				ctx.Abort()
+				return
+			}
+			ctx.Params[i].Value = strconv.FormatInt(id, 10)
+		}
+
+		ctx.Next()
+	}
+}
+
+// accountRef resolves an account given in a request body either by its id or
+// by its account number. Exactly one of the two must be set.
+func (server *Server) accountRef(ctx *gin.Context, name string, id int64, number string) (int64, bool) {
+	accountID, err := server.bank.AccountRef(ctx, name, id, number)
+	if err != nil {
+		server.bankError(ctx, err)
+		return 0, false
+	}
+	return accountID, true
+}
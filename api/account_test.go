@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
 	mockdb "github.com/singhJasvinder101/go_bank/db/mock"
 	db "github.com/singhJasvinder101/go_bank/db/sqlc"
 	"github.com/singhJasvinder101/go_bank/utils"
@@ -26,18 +27,27 @@ func TestGETAccountAPI(t *testing.T) {
 	defer ctrl.Finish()
 	store := mockdb.NewMockStore(ctrl)
 
+	store.EXPECT().
+		GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: user})).
+		Times(1).
+		Return(db.AccountMember{AccountID: account.ID, Username: user, Role: db.RoleOwner}, nil)
 	store.EXPECT().
 		GetAccountById(gomock.Any(), gomock.Eq(account.ID)).
 		Times(1).
 		Return(account, nil)
+	store.EXPECT().
+		ListAccountPots(gomock.Any(), gomock.Eq(pgtype.Int8{Int64: account.ID, Valid: true})).
+		Times(1).
+		Return([]db.Account{}, nil)
 
 	// start test server
-	server := NewServer(store)
+	server := newTestServer(t, store)
 	// response recorder
 	recorder := httptest.NewRecorder()
 
 	url := fmt.Sprintf("/accounts/%d", account.ID)
 	request := httptest.NewRequest("GET", url, nil)
+	addAuthorization(request, user)
 
 	server.router.ServeHTTP(recorder, request)
 
@@ -50,35 +60,91 @@ func TestGETAccountWithtestCasessAPI(t *testing.T) {
 	user := utils.RandomString()
 
 	account := randomAccount(user)
+	member := db.GetAccountMemberParams{AccountID: account.ID, Username: user}
+	pots := []db.Account{randomPot(account), randomPot(account)}
 
 	testCases := []struct {
 		name          string
 		accountID     int64
+		setupAuth     func(request *http.Request)
 		buildStubs    func(store *mockdb.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
 			name:      "OK",
 			accountID: account.ID,
+			setupAuth: func(request *http.Request) {
+				addAuthorization(request, user)
+			},
 			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountMember(gomock.Any(), gomock.Eq(member)).
+					Times(1).
+					Return(db.AccountMember{AccountID: account.ID, Username: user, Role: db.RoleViewer}, nil)
 				store.EXPECT().
 					GetAccountById(gomock.Any(), gomock.Eq(account.ID)).
 					Times(1).
 					Return(account, nil)
+				store.EXPECT().
+					ListAccountPots(gomock.Any(), gomock.Eq(pgtype.Int8{Int64: account.ID, Valid: true})).
+					Times(1).
+					Return([]db.Account{}, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 				requireBodyMatchAccount(t, recorder.Body, account)
 			},
 		},
+		{
+			name:      "WithPots",
+			accountID: account.ID,
+			setupAuth: func(request *http.Request) {
+				addAuthorization(request, user)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountMember(gomock.Any(), gomock.Eq(member)).
+					Times(1).
+					Return(db.AccountMember{AccountID: account.ID, Username: user, Role: db.RoleViewer}, nil)
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					ListAccountPots(gomock.Any(), gomock.Eq(pgtype.Int8{Int64: account.ID, Valid: true})).
+					Times(1).
+					Return(pots, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp accountDetailResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, newAccountResponse(account, nil), rsp.accountResponse)
+				require.Len(t, rsp.Pots, 2)
+				for i, pot := range pots {
+					require.Equal(t, pot.AccountNumber, rsp.Pots[i].AccountNumber)
+					require.Equal(t, account.AccountNumber, rsp.Pots[i].ParentAccountNumber)
+				}
+				require.Equal(t, account.Balance+pots[0].Balance+pots[1].Balance, rsp.TotalBalance)
+				requireNoAccountIDs(t, recorder.Body.Bytes())
+			},
+		},
 		{
 			name:      "NotFound",
 			accountID: account.ID,
+			setupAuth: func(request *http.Request) {
+				addAuthorization(request, user)
+			},
 			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountMember(gomock.Any(), gomock.Eq(member)).
+					Times(1).
+					Return(db.AccountMember{AccountID: account.ID, Username: user, Role: db.RoleViewer}, nil)
 				store.EXPECT().
 					GetAccountById(gomock.Any(), gomock.Eq(account.ID)).
 					Times(1).
-					Return(db.Account{}, sql.ErrNoRows)
+					Return(db.Account{}, db.ErrRecordNotFound)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusNotFound, recorder.Code)
@@ -87,7 +153,14 @@ func TestGETAccountWithtestCasessAPI(t *testing.T) {
 		{
 			name:      "InternalError",
 			accountID: account.ID,
+			setupAuth: func(request *http.Request) {
+				addAuthorization(request, user)
+			},
 			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountMember(gomock.Any(), gomock.Eq(member)).
+					Times(1).
+					Return(db.AccountMember{AccountID: account.ID, Username: user, Role: db.RoleViewer}, nil)
 				store.EXPECT().
 					GetAccountById(gomock.Any(), gomock.Eq(account.ID)).
 					Times(1).
@@ -100,6 +173,9 @@ func TestGETAccountWithtestCasessAPI(t *testing.T) {
 		{
 			name:      "InvalidId",
 			accountID: -1,
+			setupAuth: func(request *http.Request) {
+				addAuthorization(request, user)
+			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
 					GetAccountById(gomock.Any(), gomock.Any()).
@@ -109,6 +185,38 @@ func TestGETAccountWithtestCasessAPI(t *testing.T) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
+		{
+			name:      "NotMember",
+			accountID: account.ID,
+			setupAuth: func(request *http.Request) {
+				addAuthorization(request, "unauthorized_user")
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountMember(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AccountMember{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "NoAuthorization",
+			accountID: account.ID,
+			setupAuth: func(request *http.Request) {},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountById(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
 	}
 
 	for i := range testCases {
@@ -122,13 +230,14 @@ func TestGETAccountWithtestCasessAPI(t *testing.T) {
 			testCase.buildStubs(store)
 
 			// Start test server and server request
-			server := NewServer(store)
+			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 
 			url := fmt.Sprintf("/accounts/%d", testCase.accountID)
 			request, err := http.NewRequest(http.MethodGet, url, nil)
 			require.NoError(t, err)
 
+			testCase.setupAuth(request)
 			server.router.ServeHTTP(recorder, request)
 
 			testCase.checkResponse(t, recorder)
@@ -138,19 +247,35 @@ func TestGETAccountWithtestCasessAPI(t *testing.T) {
 
 func randomAccount(owner string) db.Account {
 	return db.Account{
-		ID:       int64(utils.RandomInt(1, 10000)),
-		Owner:    owner,
-		Balance:  utils.RandomMoney(),
-		Currency: utils.RandomCurrency(),
+		ID:            int64(utils.RandomInt(1, 10000)),
+		Owner:         owner,
+		Balance:       utils.RandomMoney(),
+		Currency:      utils.RandomCurrency(),
+		AccountNumber: utils.RandomAccountNumber(),
 	}
 }
 
+func randomPot(parent db.Account) db.Account {
+	pot := randomAccount(parent.Owner)
+	pot.Currency = parent.Currency
+	pot.ParentID = pgtype.Int8{Int64: parent.ID, Valid: true}
+	return pot
+}
+
 func requireBodyMatchAccount(t *testing.T, boddy *bytes.Buffer, account db.Account) {
 	data, err := io.ReadAll(boddy)
 	require.NoError(t, err)
 
-	var gotAccount db.Account
+	var gotAccount accountResponse
 	err = json.Unmarshal(data, &gotAccount)
 	require.NoError(t, err)
-	require.Equal(t, account, gotAccount)
+	require.Equal(t, newAccountResponse(account, nil), gotAccount)
+	requireNoAccountIDs(t, data)
+}
+
+// requireNoAccountIDs checks that a response does not name account ids.
+func requireNoAccountIDs(t *testing.T, data []byte) {
+	for _, field := range []string{`"account_id"`, `"from_account_id"`, `"to_account_id"`, `"parent_id"`, `"pot_id"`} {
+		require.NotContains(t, string(data), field)
+	}
 }
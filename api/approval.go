@@ -0,0 +1,204 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/singhJasvinder101/go_bank/bank"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+)
+
+type setApprovalPolicyUri struct {
+	AccountID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type setApprovalPolicyRequest struct {
+	Threshold         int64    `json:"threshold" binding:"min=0"`
+	RequiredApprovals int32    `json:"required_approvals" binding:"required,min=1"`
+	Approvers         []string `json:"approvers" binding:"required,min=1,dive,required"`
+}
+
+func (server *Server) setApprovalPolicy(ctx *gin.Context) {
+	var uri setApprovalPolicyUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req setApprovalPolicyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if int(req.RequiredApprovals) > len(req.Approvers) {
+		err := fmt.Errorf("required approvals %d exceed the %d eligible approvers", req.RequiredApprovals, len(req.Approvers))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if _, ok := server.authorizeAccount(ctx, uri.AccountID, accountManager); !ok {
+		return
+	}
+
+	policy, err := server.store.UpsertApprovalPolicy(ctx, db.UpsertApprovalPolicyParams{
+		AccountID:         uri.AccountID,
+		Threshold:         req.Threshold,
+		RequiredApprovals: req.RequiredApprovals,
+		Approvers:         req.Approvers,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newApprovalPolicyResponse(policy))
+}
+
+type approvalRequestUri struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) getApprovalRequest(ctx *gin.Context) {
+	var uri approvalRequestUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	request, err := server.store.GetApprovalRequest(ctx, uri.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	// approvers do not have to be members of the account to review its requests
+	policy, err := server.store.GetApprovalPolicy(ctx, request.FromAccountID)
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	if !slices.Contains(policy.Approvers, authUser(ctx)) {
+		if _, ok := server.authorizeAccount(ctx, request.FromAccountID, bank.AnyMember); !ok {
+			return
+		}
+	}
+
+	numbers, ok := server.lookupAccountNumbers(ctx, nil, request.FromAccountID, request.ToAccountID)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newApprovalRequestResponse(request, numbers))
+}
+
+func (server *Server) approveTransfer(ctx *gin.Context) {
+	server.decideApproval(ctx, true)
+}
+
+func (server *Server) rejectTransfer(ctx *gin.Context) {
+	server.decideApproval(ctx, false)
+}
+
+// cancelApproval withdraws a pending approval request. The maker of the
+// transfer and the managers of its account may cancel it.
+func (server *Server) cancelApproval(ctx *gin.Context) {
+	var uri approvalRequestUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	request, err := server.store.GetApprovalRequest(ctx, uri.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	if request.RequestedBy != authUser(ctx) {
+		if _, ok := server.authorizeAccount(ctx, request.FromAccountID, accountManager); !ok {
+			return
+		}
+	}
+
+	// only a request that is still pending is cancelled, even if it is being
+	// decided right now
+	request, err = server.store.CancelApprovalRequest(ctx, uri.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusConflict, errorResponse(ctx, db.ErrApprovalNotPending))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	numbers, ok := server.lookupAccountNumbers(ctx, nil, request.FromAccountID, request.ToAccountID)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newApprovalRequestResponse(request, numbers))
+}
+
+func (server *Server) decideApproval(ctx *gin.Context, approved bool) {
+	var uri approvalRequestUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	result, err := server.store.ApprovalDecisionTx(ctx, db.ApprovalDecisionTxParams{
+		RequestID: uri.ID,
+		Approver:  authUser(ctx),
+		Approved:  approved,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+		case errors.Is(err, db.ErrNotEligibleApprover):
+			ctx.JSON(http.StatusForbidden, errorResponse(ctx, err))
+		case errors.Is(err, db.ErrApprovalNotPending), errors.Is(err, db.ErrAlreadyDecided):
+			ctx.JSON(http.StatusConflict, errorResponse(ctx, err))
+		case errors.Is(err, db.ErrAccountFrozen), errors.Is(err, db.ErrInsufficientFunds), errors.Is(err, db.ErrQuorumUnreachable):
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(ctx, err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		}
+		return
+	}
+
+	ids := []int64{result.Request.FromAccountID, result.Request.ToAccountID}
+	var known []db.Account
+	if result.Transfer != nil {
+		known = []db.Account{result.Transfer.FromAccount, result.Transfer.ToAccount}
+		ids = append(ids, transferTxAccountIDs(*result.Transfer)...)
+	}
+	numbers, ok := server.lookupAccountNumbers(ctx, known, ids...)
+	if !ok {
+		return
+	}
+
+	rsp := approvalDecisionResponse{
+		Request:  newApprovalRequestResponse(result.Request, numbers),
+		Decision: result.Decision,
+	}
+	if result.Transfer != nil {
+		transfer := newTransferTxResponse(*result.Transfer, numbers)
+		rsp.Transfer = &transfer
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
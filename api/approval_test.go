@@ -0,0 +1,338 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/singhJasvinder101/go_bank/db/mock"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApproveTransferAPI(t *testing.T) {
+	approver := utils.RandomOwner()
+	requestID := int64(utils.RandomInt(1, 1000))
+
+	testCases := []struct {
+		name          string
+		requestID     int64
+		setupAuth     func(request *http.Request)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			requestID: requestID,
+			setupAuth: func(request *http.Request) {
+				addAuthorization(request, approver)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ApprovalDecisionTxParams{
+					RequestID: requestID,
+					Approver:  approver,
+					Approved:  true,
+				}
+				store.EXPECT().
+					ApprovalDecisionTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.ApprovalDecisionTxResult{}, nil)
+				store.EXPECT().
+					ListAccountNumbers(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.ListAccountNumbersRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "NotEligible",
+			requestID: requestID,
+			setupAuth: func(request *http.Request) {
+				addAuthorization(request, approver)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ApprovalDecisionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ApprovalDecisionTxResult{}, db.ErrNotEligibleApprover)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "NotPending",
+			requestID: requestID,
+			setupAuth: func(request *http.Request) {
+				addAuthorization(request, approver)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ApprovalDecisionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ApprovalDecisionTxResult{}, db.ErrApprovalNotPending)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:      "QuorumUnreachable",
+			requestID: requestID,
+			setupAuth: func(request *http.Request) {
+				addAuthorization(request, approver)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ApprovalDecisionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ApprovalDecisionTxResult{}, db.ErrQuorumUnreachable)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name:      "NotFound",
+			requestID: requestID,
+			setupAuth: func(request *http.Request) {
+				addAuthorization(request, approver)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ApprovalDecisionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ApprovalDecisionTxResult{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:      "NoAuthorization",
+			requestID: requestID,
+			setupAuth: func(request *http.Request) {},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ApprovalDecisionTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/approvals/%d/approve", testCase.requestID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			testCase.setupAuth(request)
+			server.router.ServeHTTP(recorder, request)
+
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCancelApprovalAPI(t *testing.T) {
+	maker := utils.RandomOwner()
+	manager := utils.RandomOwner()
+	request := db.ApprovalRequest{
+		ID:            int64(utils.RandomInt(1, 1000)),
+		FromAccountID: int64(utils.RandomInt(1, 1000)),
+		ToAccountID:   int64(utils.RandomInt(1001, 2000)),
+		Amount:        500,
+		RequestedBy:   maker,
+		Status:        db.ApprovalPending,
+	}
+	cancelled := request
+	cancelled.Status = db.ApprovalCancelled
+
+	testCases := []struct {
+		name          string
+		user          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Maker",
+			user: maker,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetApprovalRequest(gomock.Any(), gomock.Eq(request.ID)).Times(1).Return(request, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CancelApprovalRequest(gomock.Any(), gomock.Eq(request.ID)).Times(1).Return(cancelled, nil)
+				store.EXPECT().
+					ListAccountNumbers(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.ListAccountNumbersRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"status":"cancelled"`)
+			},
+		},
+		{
+			name: "Manager",
+			user: manager,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetApprovalRequest(gomock.Any(), gomock.Eq(request.ID)).Times(1).Return(request, nil)
+				store.EXPECT().
+					GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: request.FromAccountID, Username: manager})).
+					Times(1).
+					Return(db.AccountMember{AccountID: request.FromAccountID, Username: manager, Role: db.RoleOwner}, nil)
+				store.EXPECT().CancelApprovalRequest(gomock.Any(), gomock.Eq(request.ID)).Times(1).Return(cancelled, nil)
+				store.EXPECT().
+					ListAccountNumbers(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.ListAccountNumbersRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Viewer",
+			user: manager,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetApprovalRequest(gomock.Any(), gomock.Eq(request.ID)).Times(1).Return(request, nil)
+				store.EXPECT().
+					GetAccountMember(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AccountMember{AccountID: request.FromAccountID, Username: manager, Role: db.RoleViewer}, nil)
+				store.EXPECT().CancelApprovalRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "NotPending",
+			user: maker,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetApprovalRequest(gomock.Any(), gomock.Eq(request.ID)).Times(1).Return(request, nil)
+				store.EXPECT().
+					CancelApprovalRequest(gomock.Any(), gomock.Eq(request.ID)).
+					Times(1).
+					Return(db.ApprovalRequest{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			user: maker,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetApprovalRequest(gomock.Any(), gomock.Eq(request.ID)).
+					Times(1).
+					Return(db.ApprovalRequest{}, db.ErrRecordNotFound)
+				store.EXPECT().CancelApprovalRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/approvals/%d/cancel", request.ID)
+			httpRequest, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(httpRequest, testCase.user)
+			server.router.ServeHTTP(recorder, httpRequest)
+
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCreateTransferNeedsApprovalAPI(t *testing.T) {
+	account1 := randomAccount(utils.RandomOwner())
+	account2 := randomAccount(utils.RandomOwner())
+	account1.Currency = "USD"
+	account2.ID = account1.ID + 1
+	account2.Currency = account1.Currency
+
+	policy := db.ApprovalPolicy{
+		AccountID:         account1.ID,
+		Threshold:         100,
+		RequiredApprovals: 2,
+		Approvers:         []string{utils.RandomOwner(), utils.RandomOwner()},
+	}
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	store := mockdb.NewMockStore(controller)
+	store.EXPECT().
+		GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: account1.Owner})).
+		Times(1).
+		Return(db.AccountMember{AccountID: account1.ID, Username: account1.Owner, Role: db.RoleOwner}, nil)
+	store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+	store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+	store.EXPECT().GetApprovalPolicy(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(policy, nil)
+	store.EXPECT().
+		CreateApprovalRequest(gomock.Any(), gomock.Eq(db.CreateApprovalRequestParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        policy.Threshold + 1,
+			RequestedBy:   account1.Owner,
+		})).
+		Times(1).
+		Return(db.ApprovalRequest{ID: 1, Status: db.ApprovalPending}, nil)
+	store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(gin.H{
+		"from_account_id": account1.ID,
+		"to_account_id":   account2.ID,
+		"amount":          policy.Threshold + 1,
+		"currency":        account1.Currency,
+	})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+	require.NoError(t, err)
+	addAuthorization(request, account1.Owner)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusAccepted, recorder.Code)
+}
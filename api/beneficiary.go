@@ -0,0 +1,184 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+)
+
+type createBeneficiaryRequest struct {
+	Nickname      string `json:"nickname" binding:"required"`
+	AccountID     int64  `json:"account_id" binding:"omitempty,min=1"`
+	AccountNumber string `json:"account_number"`
+}
+
+func (server *Server) createBeneficiary(ctx *gin.Context) {
+	var req createBeneficiaryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	accountID, ok := server.accountRef(ctx, "account", req.AccountID, req.AccountNumber)
+	if !ok {
+		return
+	}
+
+	account, err := server.store.GetAccountById(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	beneficiary, err := server.store.CreateBeneficiary(ctx, db.CreateBeneficiaryParams{
+		Owner:     authUser(ctx),
+		Nickname:  req.Nickname,
+		AccountID: account.ID,
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			ctx.JSON(http.StatusConflict, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newBeneficiaryResponse(beneficiary, accountNumbers{account.ID: account.AccountNumber}))
+}
+
+type beneficiaryUri struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getOwnBeneficiary loads a beneficiary saved by the authenticated user.
+// Beneficiaries of other users are reported as not found.
+func (server *Server) getOwnBeneficiary(ctx *gin.Context, id int64) (db.Beneficiary, bool) {
+	beneficiary, err := server.bank.Beneficiary(ctx, authUser(ctx), id)
+	if err != nil {
+		server.bankError(ctx, err)
+		return beneficiary, false
+	}
+	return beneficiary, true
+}
+
+func (server *Server) getBeneficiary(ctx *gin.Context) {
+	var uri beneficiaryUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	beneficiary, ok := server.getOwnBeneficiary(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	server.beneficiaryResponse(ctx, beneficiary)
+}
+
+// beneficiaryResponse responds with a beneficiary and the number of its account.
+func (server *Server) beneficiaryResponse(ctx *gin.Context, beneficiary db.Beneficiary) {
+	numbers, ok := server.lookupAccountNumbers(ctx, nil, beneficiary.AccountID)
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, newBeneficiaryResponse(beneficiary, numbers))
+}
+
+type listBeneficiariesRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+func (server *Server) listBeneficiaries(ctx *gin.Context) {
+	var req listBeneficiariesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	beneficiaries, err := server.store.ListBeneficiaries(ctx, db.ListBeneficiariesParams{
+		Owner:  authUser(ctx),
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ids := make([]int64, 0, len(beneficiaries))
+	for _, beneficiary := range beneficiaries {
+		ids = append(ids, beneficiary.AccountID)
+	}
+	numbers, ok := server.lookupAccountNumbers(ctx, nil, ids...)
+	if !ok {
+		return
+	}
+
+	rsp := make([]beneficiaryResponse, 0, len(beneficiaries))
+	for _, beneficiary := range beneficiaries {
+		rsp = append(rsp, newBeneficiaryResponse(beneficiary, numbers))
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type updateBeneficiaryRequest struct {
+	Nickname string `json:"nickname" binding:"required"`
+}
+
+func (server *Server) updateBeneficiary(ctx *gin.Context) {
+	var uri beneficiaryUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req updateBeneficiaryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if _, ok := server.getOwnBeneficiary(ctx, uri.ID); !ok {
+		return
+	}
+
+	beneficiary, err := server.store.UpdateBeneficiaryNickname(ctx, db.UpdateBeneficiaryNicknameParams{
+		ID:       uri.ID,
+		Nickname: req.Nickname,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	server.beneficiaryResponse(ctx, beneficiary)
+}
+
+func (server *Server) deleteBeneficiary(ctx *gin.Context) {
+	var uri beneficiaryUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if _, ok := server.getOwnBeneficiary(ctx, uri.ID); !ok {
+		return
+	}
+
+	if err := server.store.DeleteBeneficiary(ctx, uri.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+)
+
+const (
+	// consistencyHeader set to strong makes a read only request read from
+	// the primary, e.g. right after the client changed what it reads.
+	consistencyHeader = "X-Consistency"
+	strongConsistency = "strong"
+)
+
+// readYourWritesMiddleware sends the reads of requests that change data, and
+// of requests asking for strong consistency, to the primary database. Other
+// reads may be served by a lagging replica.
+func readYourWritesMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		method := ctx.Request.Method
+		readOnly := method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+		if !readOnly || strings.EqualFold(ctx.GetHeader(consistencyHeader), strongConsistency) {
+			ctx.Request = ctx.Request.WithContext(db.WithPrimary(ctx.Request.Context()))
+		}
+		ctx.Next()
+	}
+}
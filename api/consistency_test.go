@@ -0,0 +1,51 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/singhJasvinder101/go_bank/db/mock"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadYourWritesMiddleware(t *testing.T) {
+	testCases := []struct {
+		name        string
+		method      string
+		consistency string
+		primary     bool
+	}{
+		{name: "Read", method: http.MethodGet, primary: false},
+		{name: "StrongRead", method: http.MethodGet, consistency: "strong", primary: true},
+		{name: "Write", method: http.MethodPost, primary: true},
+		{name: "Delete", method: http.MethodDelete, primary: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			server := newTestServer(t, mockdb.NewMockStore(controller))
+			server.router.Handle(testCase.method, "/consistency", func(ctx *gin.Context) {
+				ctx.String(http.StatusOK, strconv.FormatBool(db.UsesPrimary(ctx)))
+			})
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(testCase.method, "/consistency", nil)
+			require.NoError(t, err)
+			if testCase.consistency != "" {
+				request.Header.Set(consistencyHeader, testCase.consistency)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusOK, recorder.Code)
+			require.Equal(t, strconv.FormatBool(testCase.primary), recorder.Body.String())
+		})
+	}
+}
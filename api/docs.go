@@ -0,0 +1,52 @@
+package api
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+// openAPIDocument describes every route registered in NewServer. A test keeps
+// the two in sync.
+//
+//go:embed openapi.json
+var openAPIDocument []byte
+
+// swaggerUIPath serves the Swagger UI assets bundled with the binary.
+const swaggerUIPath = "/swagger-ui"
+
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Go Bank API</title>
+  <link rel="stylesheet" href="` + swaggerUIPath + `/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="` + swaggerUIPath + `/swagger-ui-bundle.js"></script>
+  <script src="` + swaggerUIPath + `/swagger-ui-standalone-preset.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({
+      url: "/openapi.json",
+      dom_id: "#swagger-ui",
+      presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+      layout: "StandaloneLayout",
+    });
+  </script>
+</body>
+</html>
+`
+
+// registerDocs serves the OpenAPI document and a Swagger UI page browsing it.
+func registerDocs(router *gin.Engine) {
+	router.GET("/openapi.json", func(ctx *gin.Context) {
+		ctx.Data(http.StatusOK, "application/json", openAPIDocument)
+	})
+	router.GET("/docs", func(ctx *gin.Context) {
+		ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUIPage))
+	})
+	router.StaticFS(swaggerUIPath, http.FS(swaggerFiles.FS))
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/singhJasvinder101/go_bank/health"
+)
+
+// SetReadiness sets the checks /readyz runs. Without them the server reports
+// ready as soon as it serves requests.
+func (server *Server) SetReadiness(checker *health.Checker) {
+	server.readiness = checker
+}
+
+// healthz reports that the process is alive. It checks no dependencies so
+// that an orchestrator does not restart instances during a database outage.
+func (server *Server) healthz(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"status": health.StatusOK})
+}
+
+// readyz reports whether the instance can serve traffic, with the result of
+// every readiness check.
+func (server *Server) readyz(ctx *gin.Context) {
+	if server.readiness == nil {
+		ctx.JSON(http.StatusOK, health.Report{Status: health.StatusOK, Checks: map[string]health.CheckResult{}})
+		return
+	}
+
+	report := server.readiness.Run(ctx)
+	if !report.OK() {
+		ctx.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
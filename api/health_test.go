@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/singhJasvinder101/go_bank/health"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthz(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/healthz", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestReadyz(t *testing.T) {
+	testCases := []struct {
+		name          string
+		checks        map[string]health.Check
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Ready",
+			checks: map[string]health.Check{
+				"database": func(ctx context.Context) error { return nil },
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var report health.Report
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+				require.Equal(t, health.StatusOK, report.Status)
+				require.Equal(t, health.StatusOK, report.Checks["database"].Status)
+			},
+		},
+		{
+			name: "NotReady",
+			checks: map[string]health.Check{
+				"database":   func(ctx context.Context) error { return nil },
+				"migrations": func(ctx context.Context) error { return errors.New("migrations pending") },
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+				var report health.Report
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+				require.Equal(t, health.StatusFail, report.Status)
+				require.Equal(t, health.StatusOK, report.Checks["database"].Status)
+				require.Equal(t, "migrations pending", report.Checks["migrations"].Error)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			server := newTestServer(t, nil)
+
+			checker := health.NewChecker()
+			for name, check := range testCase.checks {
+				checker.Add(name, check)
+			}
+			server.SetReadiness(checker)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/readyz", nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/singhJasvinder101/go_bank/logging"
+)
+
+var errInternal = errors.New("internal server error")
+
+// addLogAttrs adds args to the records logged with the request context,
+// including its access log line.
+func addLogAttrs(ctx *gin.Context, args ...any) {
+	ctx.Request = ctx.Request.WithContext(logging.With(ctx.Request.Context(), args...))
+}
+
+// logMiddleware replaces gin's logger with one structured access line per
+// request, logged with the attributes of the request context.
+func (server *Server) logMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+
+		server.logger.Log(ctx.Request.Context(), level, "request",
+			slog.String("method", ctx.Request.Method),
+			slog.String("path", ctx.Request.URL.Path),
+			slog.String("route", ctx.FullPath()),
+			slog.Int("status", status),
+			slog.Int("bytes", ctx.Writer.Size()),
+			slog.Duration("duration", time.Since(start)),
+			slog.String("client_ip", ctx.ClientIP()),
+		)
+	}
+}
+
+// recoveryMiddleware replaces gin's recovery. A panicking handler is logged
+// with its stack and answered with a JSON 500 carrying the request id, so the
+// caller can report it and the log line can be found.
+func (server *Server) recoveryMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// net/http aborts the response silently for this one
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			server.logger.ErrorContext(ctx.Request.Context(), "panic serving request",
+				slog.Any("panic", recovered),
+				slog.String("stack", string(debug.Stack())),
+			)
+			if ctx.Writer.Written() {
+				ctx.Abort()
+				return
+			}
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(ctx, errInternal))
+		}()
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/singhJasvinder101/go_bank/db/mock"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/logging"
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessLog(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	store := mockdb.NewMockStore(controller)
+	store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
+
+	var output bytes.Buffer
+	logger, err := logging.New(&output, "info", logging.FormatJSON)
+	require.NoError(t, err)
+
+	server := newTestServer(t, store)
+	server.SetLogger(logger)
+
+	user := utils.RandomOwner()
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/accounts/1", nil)
+	require.NoError(t, err)
+	request.Header.Set(requestIDHeader, "req-1")
+	addAuthorization(request, user)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(output.Bytes(), &line))
+	require.Equal(t, "request", line["msg"])
+	require.Equal(t, "WARN", line["level"])
+	require.Equal(t, "/accounts/1", line["path"])
+	require.EqualValues(t, http.StatusForbidden, line["status"])
+	require.Equal(t, "req-1", line[logging.RequestIDKey])
+	require.Equal(t, user, line[logging.UserKey])
+}
+
+func TestRequestID(t *testing.T) {
+	testCases := []struct {
+		name      string
+		requestID string
+		check     func(t *testing.T, requestID string)
+	}{
+		{
+			name:      "Forwarded",
+			requestID: "req-1",
+			check: func(t *testing.T, requestID string) {
+				require.Equal(t, "req-1", requestID)
+			},
+		},
+		{
+			name: "Generated",
+			check: func(t *testing.T, requestID string) {
+				require.Len(t, requestID, 32)
+			},
+		},
+		{
+			name:      "Invalid",
+			requestID: "req 1\n",
+			check: func(t *testing.T, requestID string) {
+				require.Len(t, requestID, 32)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			server := newTestServer(t, mockdb.NewMockStore(controller))
+			recorder := httptest.NewRecorder()
+
+			// the request has no user, so it fails in authMiddleware
+			request, err := http.NewRequest(http.MethodGet, "/accounts/1", nil)
+			require.NoError(t, err)
+			if testCase.requestID != "" {
+				request.Header.Set(requestIDHeader, testCase.requestID)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+			requestID := recorder.Header().Get(requestIDHeader)
+			testCase.check(t, requestID)
+
+			var rsp map[string]string
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+			require.Equal(t, requestID, rsp["request_id"])
+		})
+	}
+}
+
+func TestRecovery(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	var output bytes.Buffer
+	logger, err := logging.New(&output, "info", logging.FormatJSON)
+	require.NoError(t, err)
+
+	server := newTestServer(t, mockdb.NewMockStore(controller))
+	server.SetLogger(logger)
+	server.router.GET("/panic", func(ctx *gin.Context) {
+		panic("boom")
+	})
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/panic", nil)
+	require.NoError(t, err)
+	request.Header.Set(requestIDHeader, "req-1")
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusInternalServerError, recorder.Code)
+
+	var rsp map[string]string
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Equal(t, "req-1", rsp["request_id"])
+	require.Equal(t, errInternal.Error(), rsp["error"])
+
+	// the panic and then the access line are logged with the request id
+	decoder := json.NewDecoder(&output)
+	var panicLine, accessLine map[string]any
+	require.NoError(t, decoder.Decode(&panicLine))
+	require.NoError(t, decoder.Decode(&accessLine))
+
+	require.Equal(t, "boom", panicLine["panic"])
+	require.Contains(t, panicLine["stack"], "runtime/debug.Stack")
+	require.Equal(t, "req-1", panicLine[logging.RequestIDKey])
+
+	require.Equal(t, "request", accessLine["msg"])
+	require.EqualValues(t, http.StatusInternalServerError, accessLine["status"])
+	require.Equal(t, "req-1", accessLine[logging.RequestIDKey])
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/logging"
+	"github.com/singhJasvinder101/go_bank/stream"
+	"github.com/singhJasvinder101/go_bank/utils"
+)
+
+func newTestServer(t *testing.T, store db.Store) *Server {
+	config := utils.Config{
+		ACCOUNT_COUNTRY_CODE: "GB",
+		ACCOUNT_BANK_CODE:    "GOBK",
+		ENABLE_DOCS:          true,
+
+		BENEFICIARY_COOLING_OFF:    24 * time.Hour,
+		BENEFICIARY_LARGE_TRANSFER: "USD=1000,EUR=1000,BTC=1",
+	}
+
+	server := NewServer(config, store, stream.NewBroker())
+	server.SetLogger(logging.Discard())
+	server.SetAuth(testTokenMaker, nil)
+	return server
+}
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
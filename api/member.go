@@ -0,0 +1,143 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/singhJasvinder101/go_bank/bank"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+)
+
+// authorizeAccount loads the authenticated user's membership of the account and
+// responds with 403 unless allowed accepts it.
+func (server *Server) authorizeAccount(ctx *gin.Context, accountID int64, allowed func(db.AccountMember) bool) (db.AccountMember, bool) {
+	member, err := server.bank.AuthorizeAccount(ctx, authUser(ctx), accountID, allowed)
+	if err != nil {
+		server.bankError(ctx, err)
+		return member, false
+	}
+	return member, true
+}
+
+func accountManager(member db.AccountMember) bool {
+	return member.CanManage()
+}
+
+type accountMemberUri struct {
+	AccountID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type inviteMemberRequest struct {
+	Username      string `json:"username" binding:"required"`
+	Role          string `json:"role" binding:"required,oneof=owner co_owner viewer limited"`
+	TransferLimit int64  `json:"transfer_limit" binding:"min=0"`
+}
+
+func (server *Server) inviteMember(ctx *gin.Context) {
+	var uri accountMemberUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req inviteMemberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if req.Role != db.RoleLimited && req.TransferLimit != 0 {
+		err := fmt.Errorf("transfer limit only applies to %s members", db.RoleLimited)
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if _, ok := server.authorizeAccount(ctx, uri.AccountID, accountManager); !ok {
+		return
+	}
+
+	member, err := server.store.CreateAccountMember(ctx, db.CreateAccountMemberParams{
+		AccountID:     uri.AccountID,
+		Username:      req.Username,
+		Role:          req.Role,
+		TransferLimit: req.TransferLimit,
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			ctx.JSON(http.StatusConflict, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newMemberResponse(member))
+}
+
+func (server *Server) listMembers(ctx *gin.Context) {
+	var uri accountMemberUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if _, ok := server.authorizeAccount(ctx, uri.AccountID, bank.AnyMember); !ok {
+		return
+	}
+
+	members, err := server.store.ListAccountMembers(ctx, uri.AccountID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	rsp := make([]memberResponse, 0, len(members))
+	for _, member := range members {
+		rsp = append(rsp, newMemberResponse(member))
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type removeMemberUri struct {
+	AccountID int64  `uri:"id" binding:"required,min=1"`
+	Username  string `uri:"username" binding:"required"`
+}
+
+func (server *Server) removeMember(ctx *gin.Context) {
+	var uri removeMemberUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	// members may always leave an account, only owners may remove others
+	username := authUser(ctx)
+	allowed := func(member db.AccountMember) bool {
+		return member.CanManage() || uri.Username == username
+	}
+	if _, ok := server.authorizeAccount(ctx, uri.AccountID, allowed); !ok {
+		return
+	}
+
+	err := server.store.RemoveMemberTx(ctx, db.DeleteAccountMemberParams{
+		AccountID: uri.AccountID,
+		Username:  uri.Username,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrRecordNotFound):
+			err := fmt.Errorf("%s is not a member of account [%d]", uri.Username, uri.AccountID)
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+		case errors.Is(err, db.ErrLastOwner):
+			ctx.JSON(http.StatusConflict, errorResponse(ctx, err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,258 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgconn"
+	mockdb "github.com/singhJasvinder101/go_bank/db/mock"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInviteMemberAPI(t *testing.T) {
+	owner := utils.RandomOwner()
+	invitee := utils.RandomOwner()
+	account := randomAccount(owner)
+
+	ownerMember := db.AccountMember{AccountID: account.ID, Username: owner, Role: db.RoleOwner}
+
+	testCases := []struct {
+		name          string
+		username      string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: owner,
+			body:     gin.H{"username": invitee, "role": db.RoleLimited, "transfer_limit": 100},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: owner})).
+					Times(1).
+					Return(ownerMember, nil)
+
+				arg := db.CreateAccountMemberParams{
+					AccountID:     account.ID,
+					Username:      invitee,
+					Role:          db.RoleLimited,
+					TransferLimit: 100,
+				}
+				store.EXPECT().
+					CreateAccountMember(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.AccountMember{AccountID: account.ID, Username: invitee, Role: db.RoleLimited, TransferLimit: 100}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name:     "NotOwner",
+			username: invitee,
+			body:     gin.H{"username": invitee, "role": db.RoleOwner},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountMember(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AccountMember{AccountID: account.ID, Username: invitee, Role: db.RoleCoOwner}, nil)
+				store.EXPECT().
+					CreateAccountMember(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "AlreadyMember",
+			username: owner,
+			body:     gin.H{"username": invitee, "role": db.RoleViewer},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountMember(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(ownerMember, nil)
+				store.EXPECT().
+					CreateAccountMember(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AccountMember{}, &pgconn.PgError{Code: db.UniqueViolation})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "InvalidRole",
+			username: owner,
+			body:     gin.H{"username": invitee, "role": "admin"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAccountMember(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "LimitWithoutLimitedRole",
+			username: owner,
+			body:     gin.H{"username": invitee, "role": db.RoleViewer, "transfer_limit": 100},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAccountMember(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(testCase.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/accounts/%d/members", account.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(request, testCase.username)
+			server.router.ServeHTTP(recorder, request)
+
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRemoveMemberAPI(t *testing.T) {
+	owner := utils.RandomOwner()
+	viewer := utils.RandomOwner()
+	account := randomAccount(owner)
+
+	members := []db.AccountMember{
+		{AccountID: account.ID, Username: owner, Role: db.RoleOwner},
+		{AccountID: account.ID, Username: viewer, Role: db.RoleViewer},
+	}
+
+	testCases := []struct {
+		name          string
+		username      string
+		target        string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OwnerRemovesViewer",
+			username: owner,
+			target:   viewer,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(members[0], nil)
+				store.EXPECT().
+					RemoveMemberTx(gomock.Any(), gomock.Eq(db.DeleteAccountMemberParams{AccountID: account.ID, Username: viewer})).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		{
+			name:     "ViewerLeaves",
+			username: viewer,
+			target:   viewer,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(members[1], nil)
+				store.EXPECT().RemoveMemberTx(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		{
+			name:     "ViewerRemovesOwner",
+			username: viewer,
+			target:   owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(members[1], nil)
+				store.EXPECT().RemoveMemberTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "LastOwner",
+			username: owner,
+			target:   owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(members[0], nil)
+				store.EXPECT().
+					RemoveMemberTx(gomock.Any(), gomock.Eq(db.DeleteAccountMemberParams{AccountID: account.ID, Username: owner})).
+					Times(1).
+					Return(db.ErrLastOwner)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "NotAMember",
+			username: owner,
+			target:   "stranger",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(members[0], nil)
+				store.EXPECT().RemoveMemberTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/accounts/%d/members/%s", account.ID, testCase.target)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(request, testCase.username)
+			server.router.ServeHTTP(recorder, request)
+
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/singhJasvinder101/go_bank/db/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(controller))
+
+	// an unknown route is recorded under a single label value
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/no/such/route", nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, "/metrics", nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `gobank_http_request_duration_seconds_count{method="GET",route="unmatched",status="404"}`)
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/singhJasvinder101/go_bank/logging"
+	"github.com/singhJasvinder101/go_bank/token"
+	"github.com/singhJasvinder101/go_bank/utils"
+)
+
+const (
+	authorizationHeader     = "Authorization"
+	authorizationTypeBearer = "bearer"
+	authorizationUserHeader = "X-Username"
+	authorizationUserKey    = "authorization_user"
+)
+
+// SetAuth makes the server accept access tokens verified by tokenMaker, which
+// may be nil to accept none, and the X-Username header of requests coming
+// from authProxies.
+func (server *Server) SetAuth(tokenMaker token.Maker, authProxies []netip.Prefix) {
+	server.tokenMaker = tokenMaker
+	server.authProxies = authProxies
+}
+
+// fromAuthProxy reports whether the request was sent by an authenticating
+// proxy. The peer address is used rather than the client IP, which clients
+// can set through X-Forwarded-For.
+func (server *Server) fromAuthProxy(request *http.Request) bool {
+	return utils.InNetworks(server.authProxies, request.RemoteAddr)
+}
+
+// authMiddleware identifies the user making the request by the bearer token
+// in the Authorization header. Requests sent by an authenticating proxy may
+// name the user in the X-Username header instead, which is ignored from
+// every other client.
+func (server *Server) authMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		username, err := server.authenticate(ctx)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(ctx, err))
+			return
+		}
+
+		ctx.Set(authorizationUserKey, username)
+		addLogAttrs(ctx, logging.UserKey, username)
+		ctx.Next()
+	}
+}
+
+func (server *Server) authenticate(ctx *gin.Context) (string, error) {
+	if header := ctx.GetHeader(authorizationHeader); header != "" {
+		authType, accessToken, ok := strings.Cut(header, " ")
+		if !ok || !strings.EqualFold(authType, authorizationTypeBearer) {
+			return "", errors.New("invalid authorization header format")
+		}
+		if server.tokenMaker == nil {
+			return "", errors.New("access tokens are not accepted")
+		}
+		return server.tokenMaker.VerifyToken(accessToken)
+	}
+
+	if username := ctx.GetHeader(authorizationUserHeader); username != "" && server.fromAuthProxy(ctx.Request) {
+		return username, nil
+	}
+
+	return "", errors.New("authorization header is not provided")
+}
+
+// authUser returns the username set by authMiddleware.
+func authUser(ctx *gin.Context) string {
+	return ctx.MustGet(authorizationUserKey).(string)
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/singhJasvinder101/go_bank/db/mock"
+	"github.com/singhJasvinder101/go_bank/token"
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// testTokenMaker signs the access tokens of test servers.
+var testTokenMaker = func() token.Maker {
+	maker, err := token.NewJWTMaker("12345678901234567890123456789012")
+	if err != nil {
+		panic(err)
+	}
+	return maker
+}()
+
+func addAuthorizationToken(request *http.Request, username string, duration time.Duration) {
+	accessToken, err := testTokenMaker.CreateToken(username, duration)
+	if err != nil {
+		panic(err)
+	}
+	request.Header.Set(authorizationHeader, authorizationTypeBearer+" "+accessToken)
+}
+
+func addAuthorization(request *http.Request, username string) {
+	addAuthorizationToken(request, username, time.Minute)
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	username := utils.RandomOwner()
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(request *http.Request)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(request *http.Request) {
+				addAuthorization(request, username)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), username)
+			},
+		},
+		{
+			name:      "NoAuthorization",
+			setupAuth: func(request *http.Request) {},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "UnsupportedAuthorization",
+			setupAuth: func(request *http.Request) {
+				request.Header.Set(authorizationHeader, "Basic dXNlcjpwYXNz")
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidToken",
+			setupAuth: func(request *http.Request) {
+				request.Header.Set(authorizationHeader, authorizationTypeBearer+" "+utils.RandomString())
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "ExpiredToken",
+			setupAuth: func(request *http.Request) {
+				addAuthorizationToken(request, username, -time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "UserHeaderFromClient",
+			setupAuth: func(request *http.Request) {
+				request.RemoteAddr = "192.0.2.1:4000"
+				request.Header.Set(authorizationUserHeader, username)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "UserHeaderFromAuthProxy",
+			setupAuth: func(request *http.Request) {
+				request.RemoteAddr = "10.0.0.1:4000"
+				request.Header.Set(authorizationUserHeader, username)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), username)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			server := newTestServer(t, mockdb.NewMockStore(controller))
+			server.SetAuth(testTokenMaker, []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")})
+			server.router.GET("/auth", server.authMiddleware(), func(ctx *gin.Context) {
+				ctx.JSON(http.StatusOK, gin.H{"username": authUser(ctx)})
+			})
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/auth", nil)
+			require.NoError(t, err)
+
+			testCase.setupAuth(request)
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
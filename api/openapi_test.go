@@ -0,0 +1,453 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/singhJasvinder101/go_bank/db/mock"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// undocumentedRoutes are served but intentionally left out of the document.
+var undocumentedRoutes = map[string]bool{
+	"GET " + swaggerUIPath + "/*filepath":  true,
+	"HEAD " + swaggerUIPath + "/*filepath": true,
+}
+
+func loadOpenAPIDocument(t *testing.T) *openapi3.T {
+	doc, err := openapi3.NewLoader().LoadFromData(openAPIDocument)
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(context.Background()))
+	return doc
+}
+
+// openAPIPath turns a gin route path such as /accounts/:id into its OpenAPI
+// form /accounts/{id}.
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func TestEveryRouteIsDocumented(t *testing.T) {
+	doc := loadOpenAPIDocument(t)
+	server := newTestServer(t, nil)
+
+	routes := map[string]bool{}
+	for _, route := range server.router.Routes() {
+		key := route.Method + " " + route.Path
+		if undocumentedRoutes[key] {
+			continue
+		}
+
+		path := openAPIPath(route.Path)
+		routes[route.Method+" "+path] = true
+
+		item := doc.Paths.Find(path)
+		require.NotNil(t, item, "route %s is not documented", key)
+		require.NotNil(t, item.GetOperation(route.Method), "route %s is not documented", key)
+	}
+
+	// and every documented operation is served
+	for path, item := range doc.Paths {
+		for method := range item.Operations() {
+			require.True(t, routes[method+" "+path], "operation %s %s has no route", method, path)
+		}
+	}
+}
+
+func TestServeOpenAPIDocument(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/openapi.json", nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	require.JSONEq(t, string(openAPIDocument), recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, swaggerUIPath+"/swagger-ui-bundle.js", nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+// validateExchange checks a request and the response the server gave to it
+// against the operation documented for path.
+func validateExchange(t *testing.T, doc *openapi3.T, path string, request *http.Request, body []byte, recorder *httptest.ResponseRecorder) {
+	item := doc.Paths.Find(path)
+	require.NotNil(t, item, "path %s is not documented", path)
+	operation := item.GetOperation(request.Method)
+	require.NotNil(t, operation, "operation %s %s is not documented", request.Method, path)
+
+	pathParams := map[string]string{}
+	templateSegments := strings.Split(path, "/")
+	urlSegments := strings.Split(request.URL.Path, "/")
+	require.Len(t, urlSegments, len(templateSegments))
+	for i, segment := range templateSegments {
+		if strings.HasPrefix(segment, "{") {
+			pathParams[strings.Trim(segment, "{}")] = urlSegments[i]
+		}
+	}
+
+	request.Body = io.NopCloser(bytes.NewReader(body))
+	options := &openapi3filter.Options{
+		IncludeResponseStatus: true,
+		AuthenticationFunc:    openapi3filter.NoopAuthenticationFunc,
+	}
+	requestInput := &openapi3filter.RequestValidationInput{
+		Request:    request,
+		PathParams: pathParams,
+		Route: &routers.Route{
+			Spec:      doc,
+			Path:      path,
+			PathItem:  item,
+			Method:    request.Method,
+			Operation: operation,
+		},
+		Options: options,
+	}
+	require.NoError(t, openapi3filter.ValidateRequest(context.Background(), requestInput))
+
+	responseInput := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: requestInput,
+		Status:                 recorder.Code,
+		Header:                 recorder.Header(),
+		Options:                options,
+	}
+	responseInput.SetBodyBytes(recorder.Body.Bytes())
+	require.NoError(t, openapi3filter.ValidateResponse(context.Background(), responseInput))
+}
+
+func TestResponsesMatchOpenAPIDocument(t *testing.T) {
+	doc := loadOpenAPIDocument(t)
+
+	now := pgtype.Timestamp{Time: time.Now(), Valid: true}
+	user := utils.RandomOwner()
+	account := randomAccount(user)
+	account.Currency = "USD"
+	account.Balance = 1000
+	account.CreatedAt = now
+	pot := randomPot(account)
+	pot.CreatedAt = now
+	other := randomAccount(utils.RandomOwner())
+	other.ID = account.ID + 1
+	other.Currency = account.Currency
+	owner := db.AccountMember{AccountID: account.ID, Username: user, Role: db.RoleOwner, CreatedAt: now}
+
+	transferResult := db.TransferTxResult{
+		Transfer:    db.Transfer{ID: 1, FromAccountID: account.ID, ToAccountID: other.ID, Amount: 10, CreatedAt: now},
+		FromAccount: account,
+		ToAccount:   other,
+		FromEntry:   db.Entry{ID: 1, AccountID: account.ID, Amount: -10, CreatedAt: now},
+		ToEntry:     db.Entry{ID: 2, AccountID: other.ID, Amount: 10, CreatedAt: now},
+	}
+	approvalRequest := db.ApprovalRequest{
+		ID:            3,
+		FromAccountID: account.ID,
+		ToAccountID:   other.ID,
+		Amount:        10,
+		Status:        db.ApprovalPending,
+		CreatedAt:     now,
+	}
+	webhook := db.Webhook{
+		ID:         4,
+		Owner:      user,
+		Url:        "https://93.184.215.14/hooks",
+		EventTypes: []string{db.EventTransferCompleted},
+		Secret:     "whsec_test",
+		CreatedAt:  now,
+	}
+
+	transferBody := gin.H{
+		"from_account_id": account.ID,
+		"to_account_id":   other.ID,
+		"amount":          10,
+		"currency":        "USD",
+	}
+	expectTransferChecks := func(store *mockdb.MockStore) {
+		store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(owner, nil)
+		store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+		store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(other.ID)).Times(1).Return(other, nil)
+	}
+
+	testCases := []struct {
+		name       string
+		method     string
+		path       string
+		url        string
+		body       gin.H
+		buildStubs func(store *mockdb.MockStore)
+		status     int
+	}{
+		{
+			name:       "Ping",
+			method:     http.MethodGet,
+			path:       "/ping",
+			url:        "/ping",
+			buildStubs: func(store *mockdb.MockStore) {},
+			status:     http.StatusOK,
+		},
+		{
+			name:       "Healthz",
+			method:     http.MethodGet,
+			path:       "/healthz",
+			url:        "/healthz",
+			buildStubs: func(store *mockdb.MockStore) {},
+			status:     http.StatusOK,
+		},
+		{
+			name:       "Readyz",
+			method:     http.MethodGet,
+			path:       "/readyz",
+			url:        "/readyz",
+			buildStubs: func(store *mockdb.MockStore) {},
+			status:     http.StatusOK,
+		},
+		{
+			name:   "CreateAccount",
+			method: http.MethodPost,
+			path:   "/accounts",
+			url:    "/accounts",
+			body:   gin.H{"currency": "USD"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAccountTx(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(db.CreateAccountTxResult{Account: account, Member: owner}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:   "GetAccount",
+			method: http.MethodGet,
+			path:   "/accounts/{id}",
+			url:    fmt.Sprintf("/accounts/%d", account.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(owner, nil)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListAccountPots(gomock.Any(), gomock.Any()).Times(1).Return([]db.Account{pot}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:   "GetAccountForbidden",
+			method: http.MethodGet,
+			path:   "/accounts/{id}",
+			url:    fmt.Sprintf("/accounts/%d", account.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "ListAccounts",
+			method: http.MethodGet,
+			path:   "/accounts",
+			url:    "/accounts?page_id=1&page_size=5",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListMemberAccounts(gomock.Any(), gomock.Any()).Times(1).Return([]db.Account{account, pot}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:   "ListMembers",
+			method: http.MethodGet,
+			path:   "/accounts/{id}/members",
+			url:    fmt.Sprintf("/accounts/%d/members", account.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(owner, nil)
+				store.EXPECT().ListAccountMembers(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return([]db.AccountMember{owner}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:   "SetRoundUpRule",
+			method: http.MethodPut,
+			path:   "/accounts/{id}/round_up",
+			url:    fmt.Sprintf("/accounts/%d/round_up", account.ID),
+			body:   gin.H{"pot_id": pot.ID, "round_to": 100},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(owner, nil)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(pot.ID)).Times(1).Return(pot, nil)
+				store.EXPECT().
+					UpsertRoundUpRule(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RoundUpRule{AccountID: account.ID, PotID: pot.ID, RoundTo: 100, CreatedAt: now}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:   "CreateTransfer",
+			method: http.MethodPost,
+			path:   "/transfers",
+			url:    "/transfers",
+			body:   transferBody,
+			buildStubs: func(store *mockdb.MockStore) {
+				expectTransferChecks(store)
+				store.EXPECT().GetApprovalPolicy(gomock.Any(), gomock.Any()).Times(1).Return(db.ApprovalPolicy{}, db.ErrRecordNotFound)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(transferResult, nil)
+			},
+			status: http.StatusCreated,
+		},
+		{
+			name:   "CreateTransferNeedsApproval",
+			method: http.MethodPost,
+			path:   "/transfers",
+			url:    "/transfers",
+			body:   transferBody,
+			buildStubs: func(store *mockdb.MockStore) {
+				expectTransferChecks(store)
+				store.EXPECT().
+					GetApprovalPolicy(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ApprovalPolicy{AccountID: account.ID, Threshold: 5, RequiredApprovals: 1, Approvers: []string{utils.RandomOwner()}}, nil)
+				store.EXPECT().CreateApprovalRequest(gomock.Any(), gomock.Any()).Times(1).Return(approvalRequest, nil)
+			},
+			status: http.StatusAccepted,
+		},
+		{
+			name:   "ApproveTransfer",
+			method: http.MethodPost,
+			path:   "/approvals/{id}/approve",
+			url:    fmt.Sprintf("/approvals/%d/approve", approvalRequest.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				approved := approvalRequest
+				approved.Status = db.ApprovalApproved
+				approved.TransferID = pgtype.Int8{Int64: transferResult.Transfer.ID, Valid: true}
+				store.EXPECT().ApprovalDecisionTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ApprovalDecisionTxResult{
+					Request:  approved,
+					Decision: db.ApprovalDecision{ID: 1, RequestID: approved.ID, Approver: user, Approved: true, CreatedAt: now},
+					Transfer: &transferResult,
+				}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:   "CreateBeneficiary",
+			method: http.MethodPost,
+			path:   "/beneficiaries",
+			url:    "/beneficiaries",
+			body:   gin.H{"nickname": "rent", "account_id": other.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(other.ID)).Times(1).Return(other, nil)
+				store.EXPECT().
+					CreateBeneficiary(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Beneficiary{ID: 1, Owner: user, Nickname: "rent", AccountID: other.ID, CreatedAt: now}, nil)
+			},
+			status: http.StatusCreated,
+		},
+		{
+			name:   "GetBeneficiaryNotFound",
+			method: http.MethodGet,
+			path:   "/beneficiaries/{id}",
+			url:    "/beneficiaries/1",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetBeneficiary(gomock.Any(), gomock.Any()).Times(1).Return(db.Beneficiary{}, db.ErrRecordNotFound)
+			},
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "CreateWebhook",
+			method: http.MethodPost,
+			path:   "/webhooks",
+			url:    "/webhooks",
+			body:   gin.H{"url": webhook.Url, "event_types": webhook.EventTypes},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(1).Return(webhook, nil)
+			},
+			status: http.StatusCreated,
+		},
+		{
+			name:   "ListWebhookDeliveries",
+			method: http.MethodGet,
+			path:   "/webhooks/{id}/deliveries",
+			url:    fmt.Sprintf("/webhooks/%d/deliveries?page_id=1&page_size=5", webhook.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWebhook(gomock.Any(), gomock.Eq(webhook.ID)).Times(1).Return(webhook, nil)
+				store.EXPECT().ListWebhookDeliveries(gomock.Any(), gomock.Any()).Times(1).Return([]db.WebhookDelivery{
+					{
+						ID:            1,
+						WebhookID:     webhook.ID,
+						EventID:       1,
+						EventType:     db.EventTransferCompleted,
+						Payload:       []byte(`{"transfer":{"id":1}}`),
+						Status:        "failed",
+						Attempts:      1,
+						NextAttemptAt: now,
+						LastError:     pgtype.Text{String: "500 Internal Server Error", Valid: true},
+						CreatedAt:     now,
+					},
+				}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:   "DeleteWebhook",
+			method: http.MethodDelete,
+			path:   "/webhooks/{id}",
+			url:    fmt.Sprintf("/webhooks/%d", webhook.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWebhook(gomock.Any(), gomock.Eq(webhook.ID)).Times(1).Return(webhook, nil)
+				store.EXPECT().DeleteWebhook(gomock.Any(), gomock.Eq(webhook.ID)).Times(1).Return(nil)
+			},
+			status: http.StatusNoContent,
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			var body []byte
+			if testCase.body != nil {
+				var err error
+				body, err = json.Marshal(testCase.body)
+				require.NoError(t, err)
+			}
+
+			request, err := http.NewRequest(testCase.method, testCase.url, bytes.NewReader(body))
+			require.NoError(t, err)
+			if body != nil {
+				request.Header.Set("Content-Type", "application/json")
+			}
+
+			addAuthorization(request, user)
+			server.router.ServeHTTP(recorder, request)
+
+			require.Equal(t, testCase.status, recorder.Code, recorder.Body.String())
+			validateExchange(t, doc, testCase.path, request, body, recorder)
+		})
+	}
+}
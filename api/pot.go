@@ -0,0 +1,239 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/singhJasvinder101/go_bank/bank"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+)
+
+// getParentAccount loads an account that may own pots, responding with an
+// error if it does not exist or is itself a pot.
+func (server *Server) getParentAccount(ctx *gin.Context, accountID int64) (db.Account, bool) {
+	account, err := server.store.GetAccountById(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return account, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return account, false
+	}
+
+	if account.ParentID.Valid {
+		err := fmt.Errorf("account [%d] is a pot and cannot hold pots", account.ID)
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return account, false
+	}
+
+	return account, true
+}
+
+// getPot loads a pot and checks that it belongs to the parent account.
+func (server *Server) getPot(ctx *gin.Context, parentID int64, potID int64) (db.Account, bool) {
+	pot, err := server.store.GetAccountById(ctx, potID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return pot, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return pot, false
+	}
+
+	if !pot.ParentID.Valid || pot.ParentID.Int64 != parentID {
+		err := fmt.Errorf("account [%d] is not a pot of account [%d]", pot.ID, parentID)
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return pot, false
+	}
+
+	return pot, true
+}
+
+func (server *Server) createPot(ctx *gin.Context) {
+	var uri accountMemberUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if _, ok := server.authorizeAccount(ctx, uri.AccountID, accountManager); !ok {
+		return
+	}
+
+	parent, ok := server.getParentAccount(ctx, uri.AccountID)
+	if !ok {
+		return
+	}
+
+	// pots belong to the owner of their account, whichever manager made them
+	result, err := server.bank.CreateAccount(ctx, db.CreateAccountParams{
+		Owner:    parent.Owner,
+		Balance:  0,
+		Currency: parent.Currency,
+		ParentID: pgtype.Int8{Int64: parent.ID, Valid: true},
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newAccountResponse(result.Account, accountNumbers{parent.ID: parent.AccountNumber}))
+}
+
+func (server *Server) listPots(ctx *gin.Context) {
+	var uri accountMemberUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if _, ok := server.authorizeAccount(ctx, uri.AccountID, bank.AnyMember); !ok {
+		return
+	}
+
+	pots, err := server.store.ListAccountPots(ctx, pgtype.Int8{Int64: uri.AccountID, Valid: true})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	numbers, ok := server.lookupAccountNumbers(ctx, nil, db.ParentIDs(pots...)...)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAccountResponses(pots, numbers))
+}
+
+type potUri struct {
+	AccountID int64 `uri:"id" binding:"required,min=1"`
+	PotID     int64 `uri:"pot_id" binding:"required,min=1"`
+}
+
+type potTransferRequest struct {
+	Amount int64 `json:"amount" binding:"required,gt=0"`
+}
+
+func (server *Server) depositToPot(ctx *gin.Context) {
+	server.potTransfer(ctx, true)
+}
+
+func (server *Server) withdrawFromPot(ctx *gin.Context) {
+	server.potTransfer(ctx, false)
+}
+
+func (server *Server) potTransfer(ctx *gin.Context, deposit bool) {
+	var uri potUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req potTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	allowed := func(member db.AccountMember) bool {
+		return member.CanTransfer(req.Amount)
+	}
+	if _, ok := server.authorizeAccount(ctx, uri.AccountID, allowed); !ok {
+		return
+	}
+
+	if _, ok := server.getPot(ctx, uri.AccountID, uri.PotID); !ok {
+		return
+	}
+
+	arg := db.TransferTxParams{
+		FromAccountID: uri.AccountID,
+		ToAccountID:   uri.PotID,
+		Amount:        req.Amount,
+	}
+	if !deposit {
+		arg.FromAccountID, arg.ToAccountID = arg.ToAccountID, arg.FromAccountID
+	}
+
+	result, err := server.store.PotTransferTx(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrAccountFrozen) || errors.Is(err, db.ErrInsufficientFunds) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	server.transferTxResponse(ctx, http.StatusCreated, result)
+}
+
+type setRoundUpRequest struct {
+	PotID     int64  `json:"pot_id" binding:"omitempty,min=1"`
+	PotNumber string `json:"pot_number"`
+	RoundTo   int64  `json:"round_to" binding:"required,min=2"`
+}
+
+func (server *Server) setRoundUpRule(ctx *gin.Context) {
+	var uri accountMemberUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req setRoundUpRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if _, ok := server.authorizeAccount(ctx, uri.AccountID, accountManager); !ok {
+		return
+	}
+
+	potID, ok := server.accountRef(ctx, "pot", req.PotID, req.PotNumber)
+	if !ok {
+		return
+	}
+
+	pot, ok := server.getPot(ctx, uri.AccountID, potID)
+	if !ok {
+		return
+	}
+
+	rule, err := server.store.UpsertRoundUpRule(ctx, db.UpsertRoundUpRuleParams{
+		AccountID: uri.AccountID,
+		PotID:     potID,
+		RoundTo:   req.RoundTo,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newRoundUpRuleResponse(rule, pot))
+}
+
+func (server *Server) deleteRoundUpRule(ctx *gin.Context) {
+	var uri accountMemberUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if _, ok := server.authorizeAccount(ctx, uri.AccountID, accountManager); !ok {
+		return
+	}
+
+	if err := server.store.DeleteRoundUpRule(ctx, uri.AccountID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
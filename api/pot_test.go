@@ -0,0 +1,175 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/singhJasvinder101/go_bank/db/mock"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPotTransferAPI(t *testing.T) {
+	owner := utils.RandomOwner()
+	parent := randomAccount(owner)
+	pot := randomPot(parent)
+	pot.ID = parent.ID + 1
+	other := randomAccount(owner)
+	other.ID = parent.ID + 2
+
+	ownerMember := db.AccountMember{AccountID: parent.ID, Username: owner, Role: db.RoleOwner}
+	amount := int64(10)
+
+	testCases := []struct {
+		name          string
+		potID         int64
+		action        string
+		member        db.AccountMember
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Deposit",
+			potID:  pot.ID,
+			action: "deposit",
+			member: ownerMember,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(pot.ID)).Times(1).Return(pot, nil)
+				arg := db.TransferTxParams{FromAccountID: parent.ID, ToAccountID: pot.ID, Amount: amount}
+				store.EXPECT().PotTransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.TransferTxResult{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name:   "Withdraw",
+			potID:  pot.ID,
+			action: "withdraw",
+			member: ownerMember,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(pot.ID)).Times(1).Return(pot, nil)
+				arg := db.TransferTxParams{FromAccountID: pot.ID, ToAccountID: parent.ID, Amount: amount}
+				store.EXPECT().PotTransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.TransferTxResult{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name:   "InsufficientFunds",
+			potID:  pot.ID,
+			action: "withdraw",
+			member: ownerMember,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(pot.ID)).Times(1).Return(pot, nil)
+				store.EXPECT().PotTransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, db.ErrInsufficientFunds)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name:   "NotAPot",
+			potID:  other.ID,
+			action: "deposit",
+			member: ownerMember,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(other.ID)).Times(1).Return(other, nil)
+				store.EXPECT().PotTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "Viewer",
+			potID:  pot.ID,
+			action: "deposit",
+			member: db.AccountMember{AccountID: parent.ID, Username: owner, Role: db.RoleViewer},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().PotTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			store.EXPECT().
+				GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: parent.ID, Username: owner})).
+				Times(1).
+				Return(testCase.member, nil)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"amount": amount})
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/accounts/%d/pots/%d/%s", parent.ID, testCase.potID, testCase.action)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(request, owner)
+			server.router.ServeHTTP(recorder, request)
+
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCreatePotAPI(t *testing.T) {
+	owner := utils.RandomOwner()
+	// a second owner of the account, invited after it was opened
+	coOwner := utils.RandomOwner()
+	parent := randomAccount(owner)
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	store := mockdb.NewMockStore(controller)
+	store.EXPECT().
+		GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: parent.ID, Username: coOwner})).
+		Times(1).
+		Return(db.AccountMember{AccountID: parent.ID, Username: coOwner, Role: db.RoleOwner}, nil)
+	store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(parent.ID)).Times(1).Return(parent, nil)
+
+	// the pot belongs to the owner of the account, not the member making it
+	store.EXPECT().
+		CreateAccountTx(gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ any, arg db.CreateAccountParams, _ ...db.CreateAccountOption) (db.CreateAccountTxResult, error) {
+			require.Equal(t, owner, arg.Owner)
+			require.Equal(t, parent.Currency, arg.Currency)
+			require.Equal(t, parent.ID, arg.ParentID.Int64)
+			return db.CreateAccountTxResult{Account: randomPot(parent)}, nil
+		})
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/accounts/%d/pots", parent.ID), nil)
+	require.NoError(t, err)
+
+	addAuthorization(request, coOwner)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+}
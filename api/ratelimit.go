@@ -0,0 +1,130 @@
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/singhJasvinder101/go_bank/metrics"
+	"github.com/singhJasvinder101/go_bank/ratelimit"
+)
+
+// rateLimitRemainingKey holds the fewest requests left under any limit
+// checked so far, whose headers are the ones returned.
+const rateLimitRemainingKey = "rate_limit_remaining"
+
+// RateLimitGroups are the route groups that may have limits of their own in
+// ratelimit.Limits.Groups. Gateway routes count towards the group of the
+// route they mirror.
+var RateLimitGroups = []string{"accounts", "beneficiaries", "transfers", "approvals", "webhooks"}
+
+// SetRateLimits makes the server enforce limits with the buckets kept in
+// store.
+func (server *Server) SetRateLimits(store ratelimit.Store, limits ratelimit.Limits) {
+	server.rateLimiter = store
+	server.rateLimits = limits
+}
+
+// limitIP limits the requests of every client IP.
+func (server *Server) limitIP() gin.HandlerFunc {
+	return server.rateLimit(func(*gin.Context) (string, ratelimit.Limit) {
+		return "ip", server.rateLimits.IP
+	}, (*gin.Context).ClientIP)
+}
+
+// limitUser limits the requests of the authenticated user.
+func (server *Server) limitUser() gin.HandlerFunc {
+	return server.rateLimit(func(*gin.Context) (string, ratelimit.Limit) {
+		return "user", server.rateLimits.User
+	}, authUser)
+}
+
+// limitTransfers limits the transfers of the authenticated user.
+func (server *Server) limitTransfers() gin.HandlerFunc {
+	return server.rateLimit(func(*gin.Context) (string, ratelimit.Limit) {
+		return "transfers", server.rateLimits.Transfers
+	}, authUser)
+}
+
+// limitGroup limits the requests of the authenticated user to the routes of
+// a group.
+func (server *Server) limitGroup(group string) gin.HandlerFunc {
+	return server.rateLimit(func(*gin.Context) (string, ratelimit.Limit) {
+		return "group:" + group, server.rateLimits.Groups[group]
+	}, authUser)
+}
+
+// limitGateway applies the limits of the routes that the gateway routes
+// under prefix mirror: the group named by the first path segment and, for
+// POST /transfers, the transfers limit.
+func (server *Server) limitGateway(prefix string) gin.HandlerFunc {
+	group := server.rateLimit(func(ctx *gin.Context) (string, ratelimit.Limit) {
+		group := gatewayGroup(prefix, ctx.Request.URL.Path)
+		return "group:" + group, server.rateLimits.Groups[group]
+	}, authUser)
+	transfers := server.limitTransfers()
+
+	return func(ctx *gin.Context) {
+		if group(ctx); ctx.IsAborted() {
+			return
+		}
+		if ctx.Request.Method == http.MethodPost && gatewayGroup(prefix, ctx.Request.URL.Path) == "transfers" {
+			transfers(ctx)
+		}
+	}
+}
+
+// gatewayGroup returns the first segment of a path under prefix.
+func gatewayGroup(prefix string, path string) string {
+	group, _, _ := strings.Cut(strings.TrimPrefix(path, prefix+"/"), "/")
+	return group
+}
+
+// rateLimit refuses requests once the client identified by key has used up
+// the limit that pick returns along with its scope. Every scope has its own
+// buckets. Requests are let through when the store fails, so that an outage
+// of the limiter does not take the API down.
+func (server *Server) rateLimit(pick func(*gin.Context) (string, ratelimit.Limit), key func(*gin.Context) string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		scope, limit := pick(ctx)
+		if !limit.Enabled() {
+			return
+		}
+
+		result, err := server.rateLimiter.Take(ctx, scope+":"+key(ctx), limit)
+		if err != nil {
+			server.logger.ErrorContext(ctx, "cannot check rate limit", slog.String("scope", scope), slog.Any("error", err))
+			return
+		}
+
+		setRateLimitHeaders(ctx, result)
+		if !result.Allowed {
+			metrics.RateLimited.WithLabelValues(scope).Inc()
+			ctx.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			err := fmt.Errorf("rate limit of %s requests exceeded", limit)
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, errorResponse(ctx, err))
+		}
+	}
+}
+
+// setRateLimitHeaders sets the RateLimit headers of the response to result,
+// unless a stricter limit has already been checked.
+func setRateLimitHeaders(ctx *gin.Context, result ratelimit.Result) {
+	if remaining, ok := ctx.Get(rateLimitRemainingKey); ok && remaining.(int) <= result.Remaining {
+		return
+	}
+	ctx.Set(rateLimitRemainingKey, result.Remaining)
+
+	ctx.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+	ctx.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	ctx.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
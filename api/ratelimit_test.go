@@ -0,0 +1,175 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/singhJasvinder101/go_bank/db/mock"
+	"github.com/singhJasvinder101/go_bank/ratelimit"
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimit(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(controller))
+	server.SetRateLimits(ratelimit.NewMemoryStore(), ratelimit.Limits{
+		IP:        ratelimit.Limit{Requests: 100, Per: time.Minute},
+		Transfers: ratelimit.Limit{Requests: 2, Per: time.Minute},
+	})
+
+	// invalid transfers are refused after the limiter, so they count
+	transfer := func(user string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/transfers", strings.NewReader("{}"))
+		require.NoError(t, err)
+		addAuthorization(request, user)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	user := utils.RandomOwner()
+	for i := 1; i >= 0; i-- {
+		recorder := transfer(user)
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+		require.Equal(t, "2", recorder.Header().Get("RateLimit-Limit"))
+		require.Equal(t, strconv.Itoa(i), recorder.Header().Get("RateLimit-Remaining"))
+	}
+
+	recorder := transfer(user)
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	require.Equal(t, "30", recorder.Header().Get("Retry-After"))
+	require.Equal(t, "0", recorder.Header().Get("RateLimit-Remaining"))
+	require.Equal(t, "60", recorder.Header().Get("RateLimit-Reset"))
+	require.Contains(t, recorder.Body.String(), "rate limit of 2/1m0s requests exceeded")
+
+	// the limit is per user
+	recorder = transfer(utils.RandomOwner())
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestRateLimitByIP(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(controller))
+	server.SetRateLimits(ratelimit.NewMemoryStore(), ratelimit.Limits{
+		IP: ratelimit.Limit{Requests: 1, Per: time.Minute},
+	})
+
+	codes := []int{}
+	for i := 0; i < 2; i++ {
+		recorder := httptest.NewRecorder()
+		// unauthenticated requests are limited too
+		request, err := http.NewRequest(http.MethodGet, "/accounts", nil)
+		require.NoError(t, err)
+		server.router.ServeHTTP(recorder, request)
+		codes = append(codes, recorder.Code)
+	}
+	require.Equal(t, []int{http.StatusUnauthorized, http.StatusTooManyRequests}, codes)
+
+	// probes are never limited
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/healthz", nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestRateLimitGroups(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(controller))
+	server.SetRateLimits(ratelimit.NewMemoryStore(), ratelimit.Limits{
+		Groups: map[string]ratelimit.Limit{"webhooks": {Requests: 1, Per: time.Minute}},
+	})
+
+	// invalid requests are refused after the limiter, so they count
+	post := func(path string) int {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, path, strings.NewReader("{}"))
+		require.NoError(t, err)
+		addAuthorization(request, "ada")
+		server.router.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	require.Equal(t, http.StatusBadRequest, post("/webhooks"))
+	require.Equal(t, http.StatusTooManyRequests, post("/webhooks"))
+	// other groups have buckets of their own
+	require.Equal(t, http.StatusBadRequest, post("/beneficiaries"))
+}
+
+func TestRateLimitGateway(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(controller))
+	server.SetRateLimits(ratelimit.NewMemoryStore(), ratelimit.Limits{
+		User:      ratelimit.Limit{Requests: 100, Per: time.Minute},
+		Transfers: ratelimit.Limit{Requests: 1, Per: time.Minute},
+		Groups:    map[string]ratelimit.Limit{"accounts": {Requests: 2, Per: time.Minute}},
+	})
+	server.Mount("/v1", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	call := func(method string, path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(method, path, nil)
+		require.NoError(t, err)
+		addAuthorization(request, "ada")
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	require.Equal(t, http.StatusOK, call(http.MethodPost, "/v1/transfers").Code)
+	require.Equal(t, http.StatusTooManyRequests, call(http.MethodPost, "/v1/transfers").Code)
+
+	require.Equal(t, http.StatusOK, call(http.MethodGet, "/v1/accounts/1").Code)
+	recorder := call(http.MethodGet, "/v1/accounts")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "0", recorder.Header().Get("RateLimit-Remaining"))
+	require.Equal(t, http.StatusTooManyRequests, call(http.MethodGet, "/v1/accounts/1").Code)
+
+	// the user limit covers every gateway route
+	recorder = call(http.MethodGet, "/v1/approvals/1")
+	require.Equal(t, "100", recorder.Header().Get("RateLimit-Limit"))
+}
+
+func TestRateLimitTrustedProxies(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(controller))
+	server.SetRateLimits(ratelimit.NewMemoryStore(), ratelimit.Limits{
+		IP: ratelimit.Limit{Requests: 1, Per: time.Minute},
+	})
+
+	call := func(forwardedFor string) int {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/accounts", nil)
+		require.NoError(t, err)
+		request.RemoteAddr = "10.0.0.1:4321"
+		request.Header.Set("X-Forwarded-For", forwardedFor)
+		server.router.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	// X-Forwarded-For of untrusted peers is ignored
+	require.Equal(t, http.StatusUnauthorized, call("203.0.113.1"))
+	require.Equal(t, http.StatusTooManyRequests, call("203.0.113.2"))
+
+	require.NoError(t, server.SetTrustedProxies([]netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}))
+	require.Equal(t, http.StatusUnauthorized, call("203.0.113.3"))
+	require.Equal(t, http.StatusUnauthorized, call("203.0.113.4"))
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+	"github.com/singhJasvinder101/go_bank/logging"
+)
+
+const (
+	requestIDHeader = "X-Request-ID"
+	requestIDKey    = "request_id"
+	// maxRequestIDLength bounds the ids accepted from callers.
+	maxRequestIDLength = 128
+)
+
+// requestIDMiddleware correlates everything a request causes. It keeps the
+// X-Request-ID sent by the caller, or generates one, and returns it in the
+// response header, every error response and every record logged while
+// serving the request.
+func requestIDMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestID := ctx.GetHeader(requestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+
+		ctx.Set(requestIDKey, requestID)
+		ctx.Header(requestIDHeader, requestID)
+		addLogAttrs(ctx, logging.RequestIDKey, requestID)
+		ctx.Next()
+	}
+}
+
+// validRequestID reports whether a caller's request id can be used as is.
+// Ids are echoed into headers and logs, so only short printable ASCII ids
+// are accepted.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// requestID returns the id set by requestIDMiddleware.
+func requestID(ctx *gin.Context) string {
+	return ctx.GetString(requestIDKey)
+}
@@ -0,0 +1,254 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+)
+
+// Responses refer to accounts by their account number only, the sequential
+// account ids stay internal.
+
+// accountNumbers maps account ids to their account numbers.
+type accountNumbers map[int64]string
+
+// lookupAccountNumbers maps the given account ids and those of the known
+// accounts to their account numbers, see db.AccountNumbers.
+func (server *Server) lookupAccountNumbers(ctx *gin.Context, known []db.Account, ids ...int64) (accountNumbers, bool) {
+	numbers, err := db.AccountNumbers(ctx, server.store, known, ids...)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return nil, false
+	}
+	return numbers, true
+}
+
+type accountResponse struct {
+	AccountNumber string `json:"account_number"`
+	Owner         string `json:"owner"`
+	Balance       int64  `json:"balance"`
+	Currency      string `json:"currency"`
+	// ParentAccountNumber is set for savings pots.
+	ParentAccountNumber string    `json:"parent_account_number,omitempty"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+func newAccountResponse(account db.Account, numbers accountNumbers) accountResponse {
+	rsp := accountResponse{
+		AccountNumber: account.AccountNumber,
+		Owner:         account.Owner,
+		Balance:       account.Balance,
+		Currency:      account.Currency,
+		CreatedAt:     account.CreatedAt.Time,
+	}
+	if account.ParentID.Valid {
+		rsp.ParentAccountNumber = numbers[account.ParentID.Int64]
+	}
+	return rsp
+}
+
+func newAccountResponses(accounts []db.Account, numbers accountNumbers) []accountResponse {
+	rsp := make([]accountResponse, 0, len(accounts))
+	for _, account := range accounts {
+		rsp = append(rsp, newAccountResponse(account, numbers))
+	}
+	return rsp
+}
+
+// accountDetailResponse is an account together with its savings pots and
+// the balance held across all of them.
+type accountDetailResponse struct {
+	accountResponse
+	Pots         []accountResponse `json:"pots"`
+	TotalBalance int64             `json:"total_balance"`
+}
+
+func newAccountDetailResponse(account db.Account, pots []db.Account, numbers accountNumbers) accountDetailResponse {
+	rsp := accountDetailResponse{
+		accountResponse: newAccountResponse(account, numbers),
+		Pots:            newAccountResponses(pots, numbers),
+		TotalBalance:    account.Balance,
+	}
+	for _, pot := range pots {
+		rsp.TotalBalance += pot.Balance
+	}
+	return rsp
+}
+
+type transferResponse struct {
+	ID                int64     `json:"id"`
+	FromAccountNumber string    `json:"from_account_number"`
+	ToAccountNumber   string    `json:"to_account_number"`
+	Amount            int64     `json:"amount"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+func newTransferResponse(transfer db.Transfer, numbers accountNumbers) transferResponse {
+	return transferResponse{
+		ID:                transfer.ID,
+		FromAccountNumber: numbers[transfer.FromAccountID],
+		ToAccountNumber:   numbers[transfer.ToAccountID],
+		Amount:            transfer.Amount,
+		CreatedAt:         transfer.CreatedAt.Time,
+	}
+}
+
+type entryResponse struct {
+	ID            int64     `json:"id"`
+	AccountNumber string    `json:"account_number"`
+	Amount        int64     `json:"amount"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func newEntryResponse(entry db.Entry, numbers accountNumbers) entryResponse {
+	return entryResponse{
+		ID:            entry.ID,
+		AccountNumber: numbers[entry.AccountID],
+		Amount:        entry.Amount,
+		CreatedAt:     entry.CreatedAt.Time,
+	}
+}
+
+type transferTxResponse struct {
+	Transfer    transferResponse  `json:"transfer"`
+	FromAccount accountResponse   `json:"from_account"`
+	ToAccount   accountResponse   `json:"to_account"`
+	FromEntry   entryResponse     `json:"from_entry"`
+	ToEntry     entryResponse     `json:"to_entry"`
+	RoundUp     *transferResponse `json:"round_up,omitempty"`
+}
+
+// transferTxAccountIDs returns the ids of the accounts a transfer result
+// refers to beyond its own two accounts.
+func transferTxAccountIDs(result db.TransferTxResult) []int64 {
+	ids := db.ParentIDs(result.FromAccount, result.ToAccount)
+	if result.RoundUp != nil {
+		ids = append(ids, result.RoundUp.ToAccountID)
+	}
+	return ids
+}
+
+func newTransferTxResponse(result db.TransferTxResult, numbers accountNumbers) transferTxResponse {
+	rsp := transferTxResponse{
+		Transfer:    newTransferResponse(result.Transfer, numbers),
+		FromAccount: newAccountResponse(result.FromAccount, numbers),
+		ToAccount:   newAccountResponse(result.ToAccount, numbers),
+		FromEntry:   newEntryResponse(result.FromEntry, numbers),
+		ToEntry:     newEntryResponse(result.ToEntry, numbers),
+	}
+	if result.RoundUp != nil {
+		roundUp := newTransferResponse(*result.RoundUp, numbers)
+		rsp.RoundUp = &roundUp
+	}
+	return rsp
+}
+
+// transferTxResponse responds with a transfer result, looking up the
+// numbers of the accounts it refers to.
+func (server *Server) transferTxResponse(ctx *gin.Context, status int, result db.TransferTxResult) {
+	known := []db.Account{result.FromAccount, result.ToAccount}
+	numbers, ok := server.lookupAccountNumbers(ctx, known, transferTxAccountIDs(result)...)
+	if !ok {
+		return
+	}
+	ctx.JSON(status, newTransferTxResponse(result, numbers))
+}
+
+type approvalRequestResponse struct {
+	ID                int64       `json:"id"`
+	FromAccountNumber string      `json:"from_account_number"`
+	ToAccountNumber   string      `json:"to_account_number"`
+	Amount            int64       `json:"amount"`
+	RequestedBy       string      `json:"requested_by"`
+	Status            string      `json:"status"`
+	TransferID        pgtype.Int8 `json:"transfer_id"`
+	CreatedAt         time.Time   `json:"created_at"`
+}
+
+func newApprovalRequestResponse(request db.ApprovalRequest, numbers accountNumbers) approvalRequestResponse {
+	return approvalRequestResponse{
+		ID:                request.ID,
+		FromAccountNumber: numbers[request.FromAccountID],
+		ToAccountNumber:   numbers[request.ToAccountID],
+		Amount:            request.Amount,
+		RequestedBy:       request.RequestedBy,
+		Status:            request.Status,
+		TransferID:        request.TransferID,
+		CreatedAt:         request.CreatedAt.Time,
+	}
+}
+
+type approvalDecisionResponse struct {
+	Request  approvalRequestResponse `json:"request"`
+	Decision db.ApprovalDecision     `json:"decision"`
+	Transfer *transferTxResponse     `json:"transfer,omitempty"`
+}
+
+type memberResponse struct {
+	Username      string    `json:"username"`
+	Role          string    `json:"role"`
+	TransferLimit int64     `json:"transfer_limit"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func newMemberResponse(member db.AccountMember) memberResponse {
+	return memberResponse{
+		Username:      member.Username,
+		Role:          member.Role,
+		TransferLimit: member.TransferLimit,
+		CreatedAt:     member.CreatedAt.Time,
+	}
+}
+
+type approvalPolicyResponse struct {
+	Threshold         int64     `json:"threshold"`
+	RequiredApprovals int32     `json:"required_approvals"`
+	Approvers         []string  `json:"approvers"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+func newApprovalPolicyResponse(policy db.ApprovalPolicy) approvalPolicyResponse {
+	return approvalPolicyResponse{
+		Threshold:         policy.Threshold,
+		RequiredApprovals: policy.RequiredApprovals,
+		Approvers:         policy.Approvers,
+		CreatedAt:         policy.CreatedAt.Time,
+	}
+}
+
+type beneficiaryResponse struct {
+	ID            int64     `json:"id"`
+	Owner         string    `json:"owner"`
+	Nickname      string    `json:"nickname"`
+	AccountNumber string    `json:"account_number"`
+	Verified      bool      `json:"verified"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func newBeneficiaryResponse(beneficiary db.Beneficiary, numbers accountNumbers) beneficiaryResponse {
+	return beneficiaryResponse{
+		ID:            beneficiary.ID,
+		Owner:         beneficiary.Owner,
+		Nickname:      beneficiary.Nickname,
+		AccountNumber: numbers[beneficiary.AccountID],
+		Verified:      beneficiary.Verified,
+		CreatedAt:     beneficiary.CreatedAt.Time,
+	}
+}
+
+type roundUpRuleResponse struct {
+	PotNumber string    `json:"pot_number"`
+	RoundTo   int64     `json:"round_to"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newRoundUpRuleResponse(rule db.RoundUpRule, pot db.Account) roundUpRuleResponse {
+	return roundUpRuleResponse{
+		PotNumber: pot.AccountNumber,
+		RoundTo:   rule.RoundTo,
+		CreatedAt: rule.CreatedAt.Time,
+	}
+}
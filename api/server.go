@@ -1,38 +1,244 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/singhJasvinder101/go_bank/bank"
 	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/health"
+	"github.com/singhJasvinder101/go_bank/ratelimit"
+	"github.com/singhJasvinder101/go_bank/stream"
+	"github.com/singhJasvinder101/go_bank/token"
+	"github.com/singhJasvinder101/go_bank/tracing"
+	"github.com/singhJasvinder101/go_bank/utils"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
-func errorResponse(err error) gin.H {
-	return gin.H{"error": err.Error()}
+// errorResponse is the body of a failed request. It carries the request and
+// trace ids so that errors reported by clients can be found in the logs and
+// traces.
+func errorResponse(ctx *gin.Context, err error) gin.H {
+	rsp := gin.H{"error": err.Error(), "request_id": requestID(ctx)}
+	if traceID := tracing.TraceID(ctx.Request.Context()); traceID != "" {
+		rsp["trace_id"] = traceID
+	}
+	return rsp
+}
+
+// bankError responds to an error of the bank service with its status.
+func (server *Server) bankError(ctx *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	var invalid *bank.InvalidArgumentError
+	switch {
+	case errors.As(err, &invalid), errors.Is(err, bank.ErrCurrencyMismatch):
+		status = http.StatusBadRequest
+	case errors.Is(err, bank.ErrAccessDenied), errors.Is(err, bank.ErrBeneficiaryNotAllowed):
+		status = http.StatusForbidden
+	case errors.Is(err, db.ErrRecordNotFound), errors.Is(err, bank.ErrBeneficiaryNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, db.ErrAccountFrozen), errors.Is(err, db.ErrInsufficientFunds), errors.Is(err, bank.ErrPotTransfer),
+		errors.Is(err, db.ErrQuorumUnreachable):
+		status = http.StatusUnprocessableEntity
+	}
+	ctx.JSON(status, errorResponse(ctx, err))
+}
+
+// tracedRequest leaves probes and scrapes out of the traces.
+func tracedRequest(request *http.Request) bool {
+	switch request.URL.Path {
+	case "/metrics", "/healthz", "/readyz":
+		return false
+	}
+	return true
 }
 
 // Server serves HTTP requests for our banking service.
 type Server struct {
+	config utils.Config
 	store  db.Store
+	// bank performs the account and transfer operations shared with gRPC
+	bank   *bank.Service
+	broker *stream.Broker
+	logger *slog.Logger
 	router *gin.Engine
+	// tokenMaker verifies access tokens, nil when none are accepted
+	tokenMaker token.Maker
+	// authProxies may name the user in the X-Username header
+	authProxies []netip.Prefix
+	// rateLimiter holds the buckets of the rateLimits
+	rateLimiter ratelimit.Store
+	rateLimits  ratelimit.Limits
+	// readiness holds the checks run by /readyz
+	readiness *health.Checker
+
+	httpServer *http.Server
+	// baseCtx is the parent of every request context. It is cancelled when
+	// shutdown starts so that long-lived streams end instead of holding up
+	// the drain.
+	baseCtx    context.Context
+	cancelBase context.CancelFunc
 }
 
-func NewServer(store db.Store) *Server {
-	server := &Server{store: store}
-	router := gin.Default()
+func NewServer(config utils.Config, store db.Store, broker *stream.Broker) *Server {
+	server := &Server{
+		config:      config,
+		store:       store,
+		bank:        bank.NewService(config, store),
+		broker:      broker,
+		logger:      slog.Default(),
+		rateLimiter: ratelimit.NewMemoryStore(),
+	}
+	router := gin.New()
+	// the client IP is the peer address unless SetTrustedProxies names
+	// proxies whose X-Forwarded-For header is believed
+	router.SetTrustedProxies(nil)
+	// handlers pass the gin context to the store, which must see the
+	// cancellation and trace of the underlying request
+	router.ContextWithFallback = true
+	// otelgin continues the trace of an incoming traceparent header
+	router.Use(
+		otelgin.Middleware(tracing.ServiceName, otelgin.WithFilter(tracedRequest)),
+		requestIDMiddleware(),
+		server.logMiddleware(),
+		metricsMiddleware(),
+		server.recoveryMiddleware(),
+		readYourWritesMiddleware(),
+	)
 
 	router.GET("/ping", func(ctx *gin.Context) {
 		ctx.JSON(200, gin.H{
 			"message": "Hello, World!",
 		})
 	})
-	router.POST("/accounts", server.createAccount)
-	router.GET("/accounts/:id", server.getAccount)
-	router.GET("/accounts", server.listAccounts)
-	// router.POST("/transfers", server.createTransfer)
+
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	router.GET("/healthz", server.healthz)
+	router.GET("/readyz", server.readyz)
+
+	if server.config.ENABLE_DOCS {
+		registerDocs(router)
+	}
+
+	authRoutes := router.Group("/", server.limitIP(), server.authMiddleware(), server.limitUser())
+
+	// account routes accept an account number wherever they take an account id
+	accountRoutes := authRoutes.Group("/accounts", server.limitGroup("accounts"), server.resolveAccountNumbers("id", "pot_id"))
+	accountRoutes.POST("", server.createAccount)
+	accountRoutes.GET("/:id", server.getAccount)
+	accountRoutes.GET("", server.listAccounts)
+	accountRoutes.GET("/:id/stream", server.streamAccountEvents)
+	accountRoutes.GET("/:id/ws", server.streamAccountWebSocket)
+	accountRoutes.PUT("/:id/approval_policy", server.setApprovalPolicy)
+
+	accountRoutes.GET("/:id/members", server.listMembers)
+	accountRoutes.POST("/:id/members", server.inviteMember)
+	accountRoutes.DELETE("/:id/members/:username", server.removeMember)
+
+	accountRoutes.POST("/:id/pots", server.createPot)
+	accountRoutes.GET("/:id/pots", server.listPots)
+	accountRoutes.POST("/:id/pots/:pot_id/deposit", server.depositToPot)
+	accountRoutes.POST("/:id/pots/:pot_id/withdraw", server.withdrawFromPot)
+	accountRoutes.PUT("/:id/round_up", server.setRoundUpRule)
+	accountRoutes.DELETE("/:id/round_up", server.deleteRoundUpRule)
+
+	beneficiaryRoutes := authRoutes.Group("/beneficiaries", server.limitGroup("beneficiaries"))
+	beneficiaryRoutes.POST("", server.createBeneficiary)
+	beneficiaryRoutes.GET("", server.listBeneficiaries)
+	beneficiaryRoutes.GET("/:id", server.getBeneficiary)
+	beneficiaryRoutes.PATCH("/:id", server.updateBeneficiary)
+	beneficiaryRoutes.DELETE("/:id", server.deleteBeneficiary)
+
+	transferRoutes := authRoutes.Group("/transfers", server.limitGroup("transfers"))
+	transferRoutes.POST("", server.limitTransfers(), server.CreateTransfer)
+
+	approvalRoutes := authRoutes.Group("/approvals", server.limitGroup("approvals"))
+	approvalRoutes.GET("/:id", server.getApprovalRequest)
+	approvalRoutes.POST("/:id/approve", server.approveTransfer)
+	approvalRoutes.POST("/:id/reject", server.rejectTransfer)
+	approvalRoutes.POST("/:id/cancel", server.cancelApproval)
+
+	webhookRoutes := authRoutes.Group("/webhooks", server.limitGroup("webhooks"))
+	webhookRoutes.POST("", server.createWebhook)
+	webhookRoutes.GET("", server.listWebhooks)
+	webhookRoutes.GET("/:id", server.getWebhook)
+	webhookRoutes.DELETE("/:id", server.deleteWebhook)
+	webhookRoutes.POST("/:id/enable", server.enableWebhook)
+	webhookRoutes.GET("/:id/deliveries", server.listWebhookDeliveries)
+	webhookRoutes.POST("/:id/deliveries/:delivery_id/replay", server.replayWebhookDelivery)
 
 	server.router = router
+
+	server.baseCtx, server.cancelBase = context.WithCancel(context.Background())
+	server.httpServer = &http.Server{
+		Handler:      router,
+		ReadTimeout:  config.HTTP_READ_TIMEOUT,
+		WriteTimeout: config.HTTP_WRITE_TIMEOUT,
+		IdleTimeout:  config.HTTP_IDLE_TIMEOUT,
+		BaseContext: func(net.Listener) context.Context {
+			return server.baseCtx
+		},
+	}
+	server.httpServer.RegisterOnShutdown(server.cancelBase)
+
 	return server
 }
 
+// SetLogger makes the server log requests to logger.
+func (server *Server) SetLogger(logger *slog.Logger) {
+	server.logger = logger
+}
+
+// SetTrustedProxies makes the server take the client IP of requests sent by
+// proxies from their X-Forwarded-For header. No proxy is trusted by default.
+func (server *Server) SetTrustedProxies(proxies []netip.Prefix) error {
+	var cidrs []string
+	for _, proxy := range proxies {
+		cidrs = append(cidrs, proxy.String())
+	}
+	return server.router.SetTrustedProxies(cidrs)
+}
+
+// Mount serves every request under prefix with handler, e.g. the gRPC
+// gateway under /v1. Requests are authenticated and rate limited like the
+// routes they mirror before they reach handler, and the X-Username header is
+// only passed on when it came from an authenticating proxy.
+func (server *Server) Mount(prefix string, handler http.Handler) {
+	server.router.Any(prefix+"/*path", server.limitIP(), server.authMiddleware(), server.limitUser(), server.limitGateway(prefix), func(ctx *gin.Context) {
+		if !server.fromAuthProxy(ctx.Request) {
+			ctx.Request.Header.Del(authorizationUserHeader)
+		}
+		handler.ServeHTTP(ctx.Writer, ctx.Request)
+	})
+}
+
+// Start listens on address and serves requests until Shutdown is called.
 func (server *Server) Start(address string) error {
-	return server.router.Run(address)
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	return server.Serve(listener)
+}
+
+// Serve serves requests on listener until Shutdown is called. It returns nil
+// after a shutdown.
+func (server *Server) Serve(listener net.Listener) error {
+	err := server.httpServer.Serve(listener)
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Shutdown stops accepting connections, ends open account streams and waits
+// for in-flight requests to finish or ctx to expire.
+func (server *Server) Shutdown(ctx context.Context) error {
+	return server.httpServer.Shutdown(ctx)
 }
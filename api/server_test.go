@@ -0,0 +1,117 @@
+package api
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// startTestServer serves server on a random local port and returns its base
+// url and a channel receiving the result of Serve.
+func startTestServer(t *testing.T, server *Server) (string, <-chan error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(listener)
+	}()
+
+	return "http://" + listener.Addr().String(), served
+}
+
+func TestShutdownDrainsRequests(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server.router.GET("/slow", func(ctx *gin.Context) {
+		close(started)
+		<-release
+		ctx.Status(http.StatusOK)
+	})
+
+	url, served := startTestServer(t, server)
+
+	responses := make(chan int, 1)
+	go func() {
+		rsp, err := http.Get(url + "/slow")
+		if err != nil {
+			responses <- 0
+			return
+		}
+		rsp.Body.Close()
+		responses <- rsp.StatusCode
+	}()
+	<-started
+
+	shutdown := make(chan error, 1)
+	go func() {
+		shutdown <- server.Shutdown(context.Background())
+	}()
+
+	// shutdown waits for the in-flight request
+	select {
+	case err := <-shutdown:
+		t.Fatalf("shutdown returned before the request finished: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	require.Equal(t, http.StatusOK, <-responses)
+	require.NoError(t, <-shutdown)
+	require.NoError(t, <-served)
+
+	// no new connections are accepted
+	_, err := http.Get(url + "/ping")
+	require.Error(t, err)
+}
+
+func TestShutdownEndsStreams(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	started := make(chan struct{})
+	server.router.GET("/stream", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+		ctx.Writer.Flush()
+		close(started)
+		<-ctx.Request.Context().Done()
+	})
+
+	url, served := startTestServer(t, server)
+
+	rsp, err := http.Get(url + "/stream")
+	require.NoError(t, err)
+	defer rsp.Body.Close()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, server.Shutdown(ctx))
+	require.NoError(t, <-served)
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	server.router.GET("/stuck", func(ctx *gin.Context) {
+		close(started)
+		<-release
+	})
+
+	url, _ := startTestServer(t, server)
+	go http.Get(url + "/stuck")
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, server.Shutdown(ctx), context.DeadlineExceeded)
+}
@@ -0,0 +1,293 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/singhJasvinder101/go_bank/bank"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+)
+
+const (
+	streamHeartbeat    = 15 * time.Second
+	streamWriteTimeout = 10 * time.Second
+	// streamRetry tells SSE clients how soon to reconnect, in milliseconds.
+	streamRetry = 3000
+
+	streamEventBalance = "balance"
+	streamEventEntry   = "entry"
+)
+
+var errStreamClosed = errors.New("stream closed, reconnect with the last event id")
+
+// streamEvent is a message sent on an account stream. Entry events carry the
+// entry id as their id so that clients can resume after it.
+type streamEvent struct {
+	ID   int64  `json:"id,omitempty"`
+	Type string `json:"type"`
+	Data any    `json:"data"`
+}
+
+type balanceSnapshot struct {
+	AccountNumber string `json:"account_number"`
+	Balance       int64  `json:"balance"`
+}
+
+// accountUpdateResponse is an entry booked on the account with the balance
+// it left.
+type accountUpdateResponse struct {
+	AccountNumber string        `json:"account_number"`
+	Balance       int64         `json:"balance"`
+	Entry         entryResponse `json:"entry"`
+}
+
+func newAccountUpdateResponse(update db.AccountUpdate, numbers accountNumbers) accountUpdateResponse {
+	return accountUpdateResponse{
+		AccountNumber: numbers[update.AccountID],
+		Balance:       update.Balance,
+		Entry:         newEntryResponse(update.Entry, numbers),
+	}
+}
+
+// lastEventID reads the id of the last entry a client saw from the
+// Last-Event-ID header, or from the last_event_id query parameter for
+// clients that cannot set headers.
+func lastEventID(ctx *gin.Context) (int64, bool) {
+	value := ctx.GetHeader("Last-Event-ID")
+	if value == "" {
+		value = ctx.Query("last_event_id")
+	}
+	if value == "" {
+		return 0, true
+	}
+
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id < 0 {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, fmt.Errorf("invalid last event id %q", value)))
+		return 0, false
+	}
+	return id, true
+}
+
+// authorizeStream checks the account of a stream request and returns it with
+// the last event id the client saw.
+func (server *Server) authorizeStream(ctx *gin.Context) (int64, int64, bool) {
+	var uri accountMemberUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return 0, 0, false
+	}
+
+	lastID, ok := lastEventID(ctx)
+	if !ok {
+		return 0, 0, false
+	}
+
+	if _, ok := server.authorizeAccount(ctx, uri.AccountID, bank.AnyMember); !ok {
+		return 0, 0, false
+	}
+
+	return uri.AccountID, lastID, true
+}
+
+// streamAccount sends the updates of an account until ctx is done or the
+// subscription is dropped. Clients resuming after lastID first get the entries
+// they missed, others get a snapshot of the balance.
+func (server *Server) streamAccount(
+	ctx context.Context,
+	accountID int64,
+	lastID int64,
+	send func(streamEvent) error,
+	heartbeat func() error,
+) error {
+	// subscribe before catching up so that no update falls in between
+	updates, unsubscribe := server.broker.Subscribe(accountID)
+	defer unsubscribe()
+
+	// the snapshot is read from the primary like the updates that follow it:
+	// a lagging replica would miss entries committed before Subscribe
+	account, err := server.store.GetAccountById(db.WithPrimary(ctx), accountID)
+	if err != nil {
+		return err
+	}
+	numbers := accountNumbers{account.ID: account.AccountNumber}
+
+	sent := make(map[int64]bool)
+	if lastID > 0 {
+		entries, err := server.store.ListAccountEntriesAfter(ctx, db.ListAccountEntriesAfterParams{
+			AccountID: accountID,
+			AfterID:   lastID,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			err := send(streamEvent{
+				ID:   entry.ID,
+				Type: streamEventEntry,
+				Data: newAccountUpdateResponse(db.AccountUpdate{
+					AccountID: entry.AccountID,
+					Balance:   entry.Balance,
+					Entry: db.Entry{
+						ID:        entry.ID,
+						AccountID: entry.AccountID,
+						Amount:    entry.Amount,
+						CreatedAt: entry.CreatedAt,
+					},
+				}, numbers),
+			})
+			if err != nil {
+				return err
+			}
+			sent[entry.ID] = true
+		}
+	} else {
+		err = send(streamEvent{
+			Type: streamEventBalance,
+			Data: balanceSnapshot{AccountNumber: account.AccountNumber, Balance: account.Balance},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(streamHeartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case update, ok := <-updates:
+			if !ok {
+				return errStreamClosed
+			}
+			if sent[update.Entry.ID] {
+				continue
+			}
+			err := send(streamEvent{ID: update.Entry.ID, Type: streamEventEntry, Data: newAccountUpdateResponse(update, numbers)})
+			if err != nil {
+				return err
+			}
+		case <-ticker.C:
+			if err := heartbeat(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// streamAccountEvents streams the balance and entries of an account as
+// Server-Sent Events.
+func (server *Server) streamAccountEvents(ctx *gin.Context) {
+	accountID, lastID, ok := server.authorizeStream(ctx)
+	if !ok {
+		return
+	}
+
+	w := ctx.Writer
+	// the stream outlives the server's write timeout
+	err := http.NewResponseController(w).SetWriteDeadline(time.Time{})
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry)
+	w.Flush()
+
+	send := func(event streamEvent) error {
+		data, err := json.Marshal(event.Data)
+		if err != nil {
+			return err
+		}
+
+		if event.ID != 0 {
+			fmt.Fprintf(w, "id: %d\n", event.ID)
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+			return err
+		}
+		w.Flush()
+		return nil
+	}
+	heartbeat := func() error {
+		if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+			return err
+		}
+		w.Flush()
+		return nil
+	}
+
+	err = server.streamAccount(ctx.Request.Context(), accountID, lastID, send, heartbeat)
+	if err != nil && ctx.Request.Context().Err() == nil {
+		data, _ := json.Marshal(errorResponse(ctx, err))
+		fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+		w.Flush()
+	}
+}
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+// streamAccountWebSocket streams the balance and entries of an account over
+// a WebSocket, one JSON message per event.
+func (server *Server) streamAccountWebSocket(ctx *gin.Context) {
+	accountID, lastID, ok := server.authorizeStream(ctx)
+	if !ok {
+		return
+	}
+
+	conn, err := upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		// the upgrader has already responded
+		return
+	}
+	defer conn.Close()
+
+	streamCtx, cancel := context.WithCancel(ctx.Request.Context())
+	defer cancel()
+
+	// the stream is one-way, reading only handles control frames and
+	// notices when the client goes away
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	send := func(event streamEvent) error {
+		conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		return conn.WriteJSON(event)
+	}
+	heartbeat := func() error {
+		return conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteTimeout))
+	}
+
+	err = server.streamAccount(streamCtx, accountID, lastID, send, heartbeat)
+	if err != nil && streamCtx.Err() == nil {
+		server.logger.WarnContext(ctx, "account stream failed", slog.Int64("account_id", accountID), slog.Any("error", err))
+		message := websocket.FormatCloseMessage(websocket.CloseTryAgainLater, err.Error())
+		conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(streamWriteTimeout))
+	}
+}
@@ -0,0 +1,202 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/websocket"
+	mockdb "github.com/singhJasvinder101/go_bank/db/mock"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// readSSEEvent reads the next event of a Server-Sent Events stream, skipping
+// comments and retry hints.
+func readSSEEvent(t *testing.T, reader *bufio.Reader) map[string]string {
+	event := make(map[string]string)
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			if _, ok := event["data"]; ok {
+				return event
+			}
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ": ")
+		event[field] = value
+	}
+}
+
+func TestStreamAccountEventsAPI(t *testing.T) {
+	user := utils.RandomOwner()
+	account := randomAccount(user)
+	member := db.AccountMember{AccountID: account.ID, Username: user, Role: db.RoleViewer}
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	store := mockdb.NewMockStore(controller)
+	store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(member, nil)
+	store.EXPECT().
+		GetAccountById(gomock.Any(), gomock.Eq(account.ID)).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, _ int64) (db.Account, error) {
+			// the stream is a GET but its snapshot must not lag behind
+			require.True(t, db.UsesPrimary(ctx))
+			return account, nil
+		})
+
+	server := newTestServer(t, store)
+	httpServer := httptest.NewServer(server.router)
+	defer httpServer.Close()
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/accounts/%d/stream", httpServer.URL, account.ID), nil)
+	require.NoError(t, err)
+	addAuthorization(request, user)
+
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	require.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(response.Body)
+
+	snapshot := readSSEEvent(t, reader)
+	require.Equal(t, streamEventBalance, snapshot["event"])
+	require.JSONEq(t, fmt.Sprintf(`{"account_number":%q,"balance":%d}`, account.AccountNumber, account.Balance), snapshot["data"])
+
+	update := db.AccountUpdate{AccountID: account.ID, Balance: account.Balance + 10, Entry: db.Entry{ID: 42, AccountID: account.ID, Amount: 10}}
+	server.broker.Publish(update)
+
+	event := readSSEEvent(t, reader)
+	require.Equal(t, streamEventEntry, event["event"])
+	require.Equal(t, "42", event["id"])
+
+	var got accountUpdateResponse
+	require.NoError(t, json.Unmarshal([]byte(event["data"]), &got))
+	require.Equal(t, update.Balance, got.Balance)
+	require.Equal(t, account.AccountNumber, got.Entry.AccountNumber)
+	requireNoAccountIDs(t, []byte(event["data"]))
+}
+
+func TestStreamAccountEventsResumeAPI(t *testing.T) {
+	user := utils.RandomOwner()
+	account := randomAccount(user)
+	member := db.AccountMember{AccountID: account.ID, Username: user, Role: db.RoleOwner}
+
+	missed := []db.ListAccountEntriesAfterRow{
+		{ID: 6, AccountID: account.ID, Amount: -5, Balance: 95},
+		{ID: 8, AccountID: account.ID, Amount: 20, Balance: 115},
+	}
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	store := mockdb.NewMockStore(controller)
+	store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(member, nil)
+	store.EXPECT().
+		ListAccountEntriesAfter(gomock.Any(), gomock.Eq(db.ListAccountEntriesAfterParams{AccountID: account.ID, AfterID: 5})).
+		Times(1).
+		Return(missed, nil)
+	store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+
+	server := newTestServer(t, store)
+	httpServer := httptest.NewServer(server.router)
+	defer httpServer.Close()
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/accounts/%d/stream", httpServer.URL, account.ID), nil)
+	require.NoError(t, err)
+	addAuthorization(request, user)
+	request.Header.Set("Last-Event-ID", "5")
+
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	reader := bufio.NewReader(response.Body)
+	for _, entry := range missed {
+		event := readSSEEvent(t, reader)
+		require.Equal(t, streamEventEntry, event["event"])
+		require.Equal(t, fmt.Sprint(entry.ID), event["id"])
+	}
+
+	// an update that was already replayed is not sent twice
+	server.broker.Publish(db.AccountUpdate{AccountID: account.ID, Balance: 115, Entry: db.Entry{ID: 8}})
+	server.broker.Publish(db.AccountUpdate{AccountID: account.ID, Balance: 100, Entry: db.Entry{ID: 9}})
+
+	event := readSSEEvent(t, reader)
+	require.Equal(t, "9", event["id"])
+}
+
+func TestStreamAccountEventsNotMemberAPI(t *testing.T) {
+	account := randomAccount(utils.RandomOwner())
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	store := mockdb.NewMockStore(controller)
+	store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/accounts/%d/stream", account.ID), nil)
+	require.NoError(t, err)
+	addAuthorization(request, utils.RandomOwner())
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+}
+
+func TestStreamAccountWebSocketAPI(t *testing.T) {
+	user := utils.RandomOwner()
+	account := randomAccount(user)
+	member := db.AccountMember{AccountID: account.ID, Username: user, Role: db.RoleViewer}
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	store := mockdb.NewMockStore(controller)
+	store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(member, nil)
+	store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+
+	server := newTestServer(t, store)
+	httpServer := httptest.NewServer(server.router)
+	defer httpServer.Close()
+
+	url := fmt.Sprintf("ws%s/accounts/%d/ws", strings.TrimPrefix(httpServer.URL, "http"), account.ID)
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	addAuthorization(request, user)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, request.Header)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	var snapshot streamEvent
+	require.NoError(t, conn.ReadJSON(&snapshot))
+	require.Equal(t, streamEventBalance, snapshot.Type)
+
+	server.broker.Publish(db.AccountUpdate{AccountID: account.ID, Balance: 1, Entry: db.Entry{ID: 7}})
+
+	var event streamEvent
+	require.NoError(t, conn.ReadJSON(&event))
+	require.Equal(t, streamEventEntry, event.Type)
+	require.Equal(t, int64(7), event.ID)
+}
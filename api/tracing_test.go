@@ -0,0 +1,54 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/singhJasvinder101/go_bank/db/mock"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/tracing"
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func TestErrorResponseCarriesTraceID(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	// the store runs within the trace of the request
+	store := mockdb.NewMockStore(controller)
+	store.EXPECT().
+		GetAccountMember(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.GetAccountMemberParams) (db.AccountMember, error) {
+			require.Equal(t, traceID, tracing.TraceID(ctx))
+			return db.AccountMember{}, db.ErrRecordNotFound
+		})
+
+	server := newTestServer(t, store)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/accounts/1", nil)
+	require.NoError(t, err)
+	request.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	addAuthorization(request, utils.RandomOwner())
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+
+	var rsp map[string]string
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Equal(t, traceID, rsp["trace_id"])
+	require.NotEmpty(t, rsp["error"])
+}
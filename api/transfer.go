@@ -1,73 +1,62 @@
 package api
 
 import (
-	"database/sql"
-	"fmt"
+	"log/slog"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/bank"
+	"github.com/singhJasvinder101/go_bank/logging"
 )
 
 type TransferRequest struct {
-	FromAccountID int64  `json:"from_account_id" binding:"required,min=1"`
-	ToAccountID   int64  `json:"to_account_id" binding:"required,min=1"`
-	Amount        int64  `json:"amount" binding:"required,gt=0"`
-	Currency      string `json:"currency" binding:"required,oneof= USD EUR BTC"`
+	FromAccountID     int64  `json:"from_account_id" binding:"omitempty,min=1"`
+	FromAccountNumber string `json:"from_account_number"`
+	ToAccountID       int64  `json:"to_account_id" binding:"omitempty,min=1"`
+	ToAccountNumber   string `json:"to_account_number"`
+	BeneficiaryID     int64  `json:"beneficiary_id" binding:"omitempty,min=1"`
+	Amount            int64  `json:"amount" binding:"required,gt=0"`
+	Currency          string `json:"currency" binding:"required,oneof= USD EUR BTC"`
 }
 
 func (server *Server) CreateTransfer(ctx *gin.Context) {
 	var req TransferRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
-	_, valid := server.valideCurrencyAccount(ctx, req.FromAccountID, req.Currency)
-
-	if !valid {
-		return
-	}
-
-
-	_, valid = server.valideCurrencyAccount(ctx, req.ToAccountID, req.Currency)
-
-	if !valid {
-		return
-	}
-
-	arg := db.TransferTxParams{
-		FromAccountID: req.FromAccountID,
-		ToAccountID:   req.ToAccountID,
-		Amount:        req.Amount,
-	}
-
-	result, err := server.store.TransferTx(ctx, arg)
+	result, err := server.bank.Transfer(ctx, bank.TransferParams{
+		User:              authUser(ctx),
+		FromAccountID:     req.FromAccountID,
+		FromAccountNumber: req.FromAccountNumber,
+		ToAccountID:       req.ToAccountID,
+		ToAccountNumber:   req.ToAccountNumber,
+		BeneficiaryID:     req.BeneficiaryID,
+		Amount:            req.Amount,
+		Currency:          req.Currency,
+	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		server.bankError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, result)
-}
-
-func (server *Server) valideCurrencyAccount(ctx *gin.Context, accountID int64, currency string) (db.Account, bool) {
-	account, err := server.store.GetAccountById(ctx, accountID)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return account, false
+	if result.ApprovalRequest != nil {
+		numbers := accountNumbers{
+			result.FromAccount.ID: result.FromAccount.AccountNumber,
+			result.ToAccount.ID:   result.ToAccount.AccountNumber,
 		}
-
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return account, false
+		ctx.JSON(http.StatusAccepted, newApprovalRequestResponse(*result.ApprovalRequest, numbers))
+		return
 	}
 
-	if account.Currency != currency {
-		err := fmt.Errorf("account [%d] currency mismatch: %s VS %s", account.ID, account.Currency, currency)
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
-		return account, false
-	}
+	transfer := result.Transfer.Transfer
+	addLogAttrs(ctx, logging.TransferIDKey, transfer.ID)
+	server.logger.InfoContext(ctx, "transfer created",
+		slog.Int64("from_account_id", transfer.FromAccountID),
+		slog.Int64("to_account_id", transfer.ToAccountID),
+		slog.Int64("amount", transfer.Amount),
+	)
 
-	return account, true
-}
\ No newline at end of file
+	server.transferTxResponse(ctx, http.StatusCreated, *result.Transfer)
+}
@@ -0,0 +1,372 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/singhJasvinder101/go_bank/db/mock"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTransferAPI(t *testing.T) {
+	user := utils.RandomOwner()
+	account1 := randomAccount(user)
+	account2 := randomAccount(utils.RandomOwner())
+	account1.Currency = "USD"
+	// the large transfer amount in USD of the test server
+	largeTransfer := int64(1000)
+	account1.Balance = 10 * largeTransfer
+	account2.ID = account1.ID + 1
+	account2.Currency = account1.Currency
+
+	owner := db.AccountMember{AccountID: account1.ID, Username: user, Role: db.RoleOwner}
+
+	oldBeneficiary := db.Beneficiary{
+		ID:        1,
+		Owner:     user,
+		AccountID: account2.ID,
+		Verified:  true,
+		CreatedAt: pgtype.Timestamp{Time: time.Now().Add(-48 * time.Hour), Valid: true},
+	}
+	newBeneficiary := oldBeneficiary
+	newBeneficiary.ID = 2
+	newBeneficiary.CreatedAt = pgtype.Timestamp{Time: time.Now(), Valid: true}
+	unverifiedBeneficiary := oldBeneficiary
+	unverifiedBeneficiary.ID = 3
+	unverifiedBeneficiary.Verified = false
+
+	expectTransfer := func(store *mockdb.MockStore, amount int64) {
+		store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+		store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+		store.EXPECT().GetApprovalPolicy(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(db.ApprovalPolicy{}, db.ErrRecordNotFound)
+		arg := db.TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        amount,
+		}
+		result := db.TransferTxResult{
+			Transfer:    db.Transfer{ID: 1, FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: amount},
+			FromAccount: account1,
+			ToAccount:   account2,
+			FromEntry:   db.Entry{ID: 1, AccountID: account1.ID, Amount: -amount},
+			ToEntry:     db.Entry{ID: 2, AccountID: account2.ID, Amount: amount},
+		}
+		store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          10,
+				"currency":        "USD",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(owner, nil)
+				expectTransfer(store, 10)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var rsp transferTxResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, account1.AccountNumber, rsp.Transfer.FromAccountNumber)
+				require.Equal(t, account2.AccountNumber, rsp.Transfer.ToAccountNumber)
+				require.Equal(t, account1.AccountNumber, rsp.FromEntry.AccountNumber)
+				require.Equal(t, account2.AccountNumber, rsp.ToAccount.AccountNumber)
+				requireNoAccountIDs(t, recorder.Body.Bytes())
+			},
+		},
+		{
+			name: "AccountNumbers",
+			body: gin.H{
+				"from_account_number": account1.AccountNumber,
+				"to_account_number":   account2.AccountNumber,
+				"amount":              10,
+				"currency":            "USD",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(account1.AccountNumber)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(account2.AccountNumber)).Times(1).Return(account2, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(owner, nil)
+				expectTransfer(store, 10)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name: "BothSources",
+			body: gin.H{
+				"from_account_id":     account1.ID,
+				"from_account_number": account1.AccountNumber,
+				"to_account_id":       account2.ID,
+				"amount":              10,
+				"currency":            "USD",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Beneficiary",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"beneficiary_id":  oldBeneficiary.ID,
+				"amount":          largeTransfer + 1,
+				"currency":        "USD",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(owner, nil)
+				store.EXPECT().GetBeneficiary(gomock.Any(), gomock.Eq(oldBeneficiary.ID)).Times(1).Return(oldBeneficiary, nil)
+				expectTransfer(store, largeTransfer+1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name: "BeneficiaryCoolingOff",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"beneficiary_id":  newBeneficiary.ID,
+				"amount":          largeTransfer + 1,
+				"currency":        "USD",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(owner, nil)
+				store.EXPECT().GetBeneficiary(gomock.Any(), gomock.Eq(newBeneficiary.ID)).Times(1).Return(newBeneficiary, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "UnverifiedBeneficiary",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"beneficiary_id":  unverifiedBeneficiary.ID,
+				"amount":          largeTransfer + 1,
+				"currency":        "USD",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(owner, nil)
+				store.EXPECT().GetBeneficiary(gomock.Any(), gomock.Eq(unverifiedBeneficiary.ID)).Times(1).Return(unverifiedBeneficiary, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "SmallTransferDuringCoolingOff",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"beneficiary_id":  newBeneficiary.ID,
+				"amount":          10,
+				"currency":        "USD",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(owner, nil)
+				store.EXPECT().GetBeneficiary(gomock.Any(), gomock.Eq(newBeneficiary.ID)).Times(1).Return(newBeneficiary, nil)
+				expectTransfer(store, 10)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name: "OtherUsersBeneficiary",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"beneficiary_id":  oldBeneficiary.ID,
+				"amount":          10,
+				"currency":        "USD",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				other := oldBeneficiary
+				other.Owner = utils.RandomOwner()
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(owner, nil)
+				store.EXPECT().GetBeneficiary(gomock.Any(), gomock.Eq(oldBeneficiary.ID)).Times(1).Return(other, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "BothDestinations",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"beneficiary_id":  oldBeneficiary.ID,
+				"amount":          10,
+				"currency":        "USD",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InsufficientFunds",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          account1.Balance + 1,
+				"currency":        "USD",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(owner, nil)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().GetApprovalPolicy(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(db.ApprovalPolicy{}, db.ErrRecordNotFound)
+				store.EXPECT().CreateApprovalRequest(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, db.ErrInsufficientFunds)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name: "ToAccountNotFound",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          10,
+				"currency":        "USD",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(owner, nil)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "FromPot",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          10,
+				"currency":        "USD",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				pot := account1
+				pot.ParentID = pgtype.Int8{Int64: account2.ID + 1, Valid: true}
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(owner, nil)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(pot, nil)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().GetApprovalPolicy(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name: "SameAccount",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account1.ID,
+				"amount":          10,
+				"currency":        "USD",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(owner, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "AccountFrozen",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          10,
+				"currency":        "USD",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(owner, nil)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().GetApprovalPolicy(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(db.ApprovalPolicy{}, db.ErrRecordNotFound)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, db.ErrAccountFrozen)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name: "NotAllowed",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          10,
+				"currency":        "USD",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				viewer := owner
+				viewer.Role = db.RoleViewer
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(viewer, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(testCase.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(request, user)
+			server.router.ServeHTTP(recorder, request)
+
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
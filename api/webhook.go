@@ -0,0 +1,305 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/singhJasvinder101/go_bank/webhooks"
+)
+
+var (
+	errWebhookNotFound         = errors.New("webhook not found")
+	errWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+// webhookResponse is a webhook without its secret, which is only returned
+// when the webhook is created.
+type webhookResponse struct {
+	ID                  int64     `json:"id"`
+	Url                 string    `json:"url"`
+	EventTypes          []string  `json:"event_types"`
+	Disabled            bool      `json:"disabled"`
+	ConsecutiveFailures int32     `json:"consecutive_failures"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+func newWebhookResponse(webhook db.Webhook) webhookResponse {
+	return webhookResponse{
+		ID:                  webhook.ID,
+		Url:                 webhook.Url,
+		EventTypes:          webhook.EventTypes,
+		Disabled:            webhook.Disabled,
+		ConsecutiveFailures: webhook.ConsecutiveFailures,
+		CreatedAt:           webhook.CreatedAt.Time,
+	}
+}
+
+type createWebhookResponse struct {
+	webhookResponse
+	Secret string `json:"secret"`
+}
+
+type webhookDeliveryResponse struct {
+	ID            int64            `json:"id"`
+	EventID       int64            `json:"event_id"`
+	EventType     string           `json:"event_type"`
+	Payload       json.RawMessage  `json:"payload"`
+	Status        string           `json:"status"`
+	Attempts      int32            `json:"attempts"`
+	NextAttemptAt time.Time        `json:"next_attempt_at"`
+	LastError     pgtype.Text      `json:"last_error"`
+	DeliveredAt   pgtype.Timestamp `json:"delivered_at"`
+}
+
+func newWebhookDeliveryResponse(delivery db.WebhookDelivery) webhookDeliveryResponse {
+	return webhookDeliveryResponse{
+		ID:            delivery.ID,
+		EventID:       delivery.EventID,
+		EventType:     delivery.EventType,
+		Payload:       delivery.Payload,
+		Status:        delivery.Status,
+		Attempts:      delivery.Attempts,
+		NextAttemptAt: delivery.NextAttemptAt.Time,
+		LastError:     delivery.LastError,
+		DeliveredAt:   delivery.DeliveredAt,
+	}
+}
+
+type createWebhookRequest struct {
+	Url        string   `json:"url" binding:"required,url"`
+	EventTypes []string `json:"event_types" binding:"required,min=1,dive,oneof=account.created transfer.completed"`
+}
+
+func (server *Server) createWebhook(ctx *gin.Context) {
+	var req createWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if u, err := url.Parse(req.Url); err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, errors.New("webhook url must be http or https")))
+		return
+	}
+
+	// the dispatcher checks the address again when it connects
+	allowed, err := utils.ParseNetworks(server.config.WEBHOOK_ALLOWED_NETWORKS)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	if err := webhooks.CheckURL(ctx, req.Url, allowed); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	secret, err := webhooks.NewSecret()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	webhook, err := server.store.CreateWebhook(ctx, db.CreateWebhookParams{
+		Owner:      authUser(ctx),
+		Url:        req.Url,
+		EventTypes: req.EventTypes,
+		Secret:     secret,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, createWebhookResponse{
+		webhookResponse: newWebhookResponse(webhook),
+		Secret:          webhook.Secret,
+	})
+}
+
+type webhookUri struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getOwnWebhook loads a webhook of the authenticated user. Webhooks of other
+// users are reported as not found.
+func (server *Server) getOwnWebhook(ctx *gin.Context, id int64) (db.Webhook, bool) {
+	webhook, err := server.store.GetWebhook(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, errWebhookNotFound))
+			return webhook, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return webhook, false
+	}
+
+	if webhook.Owner != authUser(ctx) {
+		ctx.JSON(http.StatusNotFound, errorResponse(ctx, errWebhookNotFound))
+		return webhook, false
+	}
+
+	return webhook, true
+}
+
+func (server *Server) getWebhook(ctx *gin.Context) {
+	var uri webhookUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	webhook, ok := server.getOwnWebhook(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newWebhookResponse(webhook))
+}
+
+type listWebhooksRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+func (server *Server) listWebhooks(ctx *gin.Context) {
+	var req listWebhooksRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	webhooks, err := server.store.ListWebhooks(ctx, db.ListWebhooksParams{
+		Owner:  authUser(ctx),
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	rsp := make([]webhookResponse, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		rsp = append(rsp, newWebhookResponse(webhook))
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+func (server *Server) deleteWebhook(ctx *gin.Context) {
+	var uri webhookUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if _, ok := server.getOwnWebhook(ctx, uri.ID); !ok {
+		return
+	}
+
+	if err := server.store.DeleteWebhook(ctx, uri.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// enableWebhook re-enables a webhook that was disabled after repeated
+// failures. Its pending deliveries are retried.
+func (server *Server) enableWebhook(ctx *gin.Context) {
+	var uri webhookUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if _, ok := server.getOwnWebhook(ctx, uri.ID); !ok {
+		return
+	}
+
+	webhook, err := server.store.EnableWebhook(ctx, uri.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newWebhookResponse(webhook))
+}
+
+func (server *Server) listWebhookDeliveries(ctx *gin.Context) {
+	var uri webhookUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req listWebhooksRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if _, ok := server.getOwnWebhook(ctx, uri.ID); !ok {
+		return
+	}
+
+	deliveries, err := server.store.ListWebhookDeliveries(ctx, db.ListWebhookDeliveriesParams{
+		WebhookID: uri.ID,
+		Limit:     req.PageSize,
+		Offset:    (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	rsp := make([]webhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		rsp = append(rsp, newWebhookDeliveryResponse(delivery))
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type webhookDeliveryUri struct {
+	WebhookID  int64 `uri:"id" binding:"required,min=1"`
+	DeliveryID int64 `uri:"delivery_id" binding:"required,min=1"`
+}
+
+// replayWebhookDelivery queues a delivery to be sent again right away,
+// whether it was delivered, failed or is still being retried.
+func (server *Server) replayWebhookDelivery(ctx *gin.Context) {
+	var uri webhookDeliveryUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if _, ok := server.getOwnWebhook(ctx, uri.WebhookID); !ok {
+		return
+	}
+
+	delivery, err := server.store.GetWebhookDelivery(ctx, uri.DeliveryID)
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	if err != nil || delivery.WebhookID != uri.WebhookID {
+		ctx.JSON(http.StatusNotFound, errorResponse(ctx, errWebhookDeliveryNotFound))
+		return
+	}
+
+	delivery, err = server.store.ReplayWebhookDelivery(ctx, delivery.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, newWebhookDeliveryResponse(delivery))
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/singhJasvinder101/go_bank/db/mock"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateWebhookAPI(t *testing.T) {
+	user := utils.RandomOwner()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			// an address literal, so that the test does not resolve a host
+			body: gin.H{"url": "https://93.184.215.14/hook", "event_types": []string{db.EventTransferCompleted}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateWebhook(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.CreateWebhookParams) (db.Webhook, error) {
+						require.Equal(t, user, arg.Owner)
+						require.NotEmpty(t, arg.Secret)
+						return db.Webhook{ID: 1, Owner: arg.Owner, Url: arg.Url, EventTypes: arg.EventTypes, Secret: arg.Secret}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var rsp map[string]any
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.NotEmpty(t, rsp["secret"])
+			},
+		},
+		{
+			name: "UnknownEventType",
+			body: gin.H{"url": "https://example.com/hook", "event_types": []string{"account.deleted"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "PrivateAddress",
+			body: gin.H{"url": "http://169.254.169.254/latest/meta-data", "event_types": []string{db.EventAccountCreated}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotHTTP",
+			body: gin.H{"url": "ftp://example.com/hook", "event_types": []string{db.EventAccountCreated}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(testCase.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(request, user)
+			server.router.ServeHTTP(recorder, request)
+
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestReplayWebhookDeliveryAPI(t *testing.T) {
+	user := utils.RandomOwner()
+	webhook := db.Webhook{ID: 3, Owner: user, Url: "https://example.com/hook", Secret: "whsec_test"}
+	delivery := db.WebhookDelivery{ID: 9, WebhookID: webhook.ID, EventID: 1, Payload: []byte(`{}`), Status: "failed"}
+
+	testCases := []struct {
+		name          string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				replayed := delivery
+				replayed.Status = "pending"
+				store.EXPECT().GetWebhook(gomock.Any(), gomock.Eq(webhook.ID)).Times(1).Return(webhook, nil)
+				store.EXPECT().GetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).Times(1).Return(delivery, nil)
+				store.EXPECT().ReplayWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).Times(1).Return(replayed, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		{
+			name:     "OtherUsersWebhook",
+			username: utils.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWebhook(gomock.Any(), gomock.Eq(webhook.ID)).Times(1).Return(webhook, nil)
+				store.EXPECT().ReplayWebhookDelivery(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "DeliveryOfOtherWebhook",
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				other := delivery
+				other.WebhookID = webhook.ID + 1
+				store.EXPECT().GetWebhook(gomock.Any(), gomock.Eq(webhook.ID)).Times(1).Return(webhook, nil)
+				store.EXPECT().GetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).Times(1).Return(other, nil)
+				store.EXPECT().ReplayWebhookDelivery(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/webhooks/%d/deliveries/%d/replay", webhook.ID, delivery.ID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(request, testCase.username)
+			server.router.ServeHTTP(recorder, request)
+
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
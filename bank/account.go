@@ -0,0 +1,66 @@
+package bank
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/utils"
+)
+
+// CreateAccount creates an account with a freshly generated account number.
+func (service *Service) CreateAccount(ctx context.Context, arg db.CreateAccountParams) (db.CreateAccountTxResult, error) {
+	return service.store.CreateAccountTx(ctx, arg,
+		db.WithGeneratedNumber(service.config.ACCOUNT_COUNTRY_CODE, service.config.ACCOUNT_BANK_CODE))
+}
+
+// AccountRef resolves an account given either by its id or by its account
+// number and returns its id. Exactly one of the two must be set.
+func (service *Service) AccountRef(ctx context.Context, name string, id int64, number string) (int64, error) {
+	if (id == 0) == (number == "") {
+		return 0, invalidArgument("exactly one of %s_id and %s_number must be set", name, name)
+	}
+	if number == "" {
+		if id < 1 {
+			return 0, invalidArgument("%s_id must be at least 1", name)
+		}
+		return id, nil
+	}
+
+	number = utils.NormalizeAccountNumber(number)
+	if err := utils.ValidateAccountNumber(number); err != nil {
+		return 0, invalidArgument("%s_number: %v", name, err)
+	}
+
+	account, err := service.store.GetAccountByNumber(ctx, number)
+	if err != nil {
+		return 0, err
+	}
+	return account.ID, nil
+}
+
+// AuthorizeAccount loads the user's membership of the account and returns
+// ErrAccessDenied unless allowed accepts it.
+func (service *Service) AuthorizeAccount(ctx context.Context, user string, accountID int64, allowed func(db.AccountMember) bool) (db.AccountMember, error) {
+	member, err := service.store.GetAccountMember(ctx, db.GetAccountMemberParams{
+		AccountID: accountID,
+		Username:  user,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return member, ErrAccessDenied
+		}
+		return member, err
+	}
+
+	if !allowed(member) {
+		return member, ErrAccessDenied
+	}
+
+	return member, nil
+}
+
+// AnyMember allows every member of an account.
+func AnyMember(db.AccountMember) bool {
+	return true
+}
@@ -0,0 +1,55 @@
+// Package bank holds the account and transfer operations that the HTTP API
+// and the gRPC server both offer, so that the two enforce the same rules.
+// Errors are returned as the sentinels and types below, each server maps
+// them to its own status codes.
+package bank
+
+import (
+	"errors"
+	"fmt"
+
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/utils"
+)
+
+var (
+	// ErrAccessDenied is returned when the user is not a member of the
+	// account allowed to perform the operation.
+	ErrAccessDenied = errors.New("authenticated user is not allowed to do this on the account")
+	// ErrCurrencyMismatch is returned when an account does not hold the
+	// currency of a transfer.
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	// ErrBeneficiaryNotFound is returned for beneficiaries that do not exist
+	// or were saved by another user.
+	ErrBeneficiaryNotFound = errors.New("beneficiary not found")
+	// ErrBeneficiaryNotAllowed is returned when a beneficiary may not yet
+	// receive a transfer of the requested amount.
+	ErrBeneficiaryNotAllowed = errors.New("beneficiary cannot receive this transfer")
+	// ErrPotTransfer is returned when a transfer sends from or to a savings
+	// pot. Money only moves in and out of a pot through its parent account.
+	ErrPotTransfer = errors.New("pots only take deposits from and withdrawals to their parent account")
+)
+
+// InvalidArgumentError reports a request that is malformed regardless of the
+// accounts it refers to.
+type InvalidArgumentError struct {
+	msg string
+}
+
+func (err *InvalidArgumentError) Error() string {
+	return err.msg
+}
+
+func invalidArgument(format string, a ...any) error {
+	return &InvalidArgumentError{msg: fmt.Sprintf(format, a...)}
+}
+
+// Service performs account and transfer operations on behalf of users.
+type Service struct {
+	config utils.Config
+	store  db.Store
+}
+
+func NewService(config utils.Config, store db.Store) *Service {
+	return &Service{config: config, store: store}
+}
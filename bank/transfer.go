@@ -0,0 +1,193 @@
+package bank
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/metrics"
+	"github.com/singhJasvinder101/go_bank/utils"
+)
+
+// TransferParams describe a transfer requested by User. Each account is given
+// either by its id or by its account number, the receiving account may
+// instead be one of User's beneficiaries.
+type TransferParams struct {
+	User              string
+	FromAccountID     int64
+	FromAccountNumber string
+	ToAccountID       int64
+	ToAccountNumber   string
+	BeneficiaryID     int64
+	Amount            int64
+	Currency          string
+}
+
+// TransferResult is either the transfer made or, for transfers that need
+// sign-off, the approval request created instead.
+type TransferResult struct {
+	FromAccount     db.Account
+	ToAccount       db.Account
+	Transfer        *db.TransferTxResult
+	ApprovalRequest *db.ApprovalRequest
+}
+
+// Transfer moves money on behalf of a member of the sending account. Transfers
+// above the account's approval policy threshold are not made but wait for
+// sign-off. Pots can neither send nor receive transfers, their money moves
+// through the deposits and withdrawals of the parent account.
+func (service *Service) Transfer(ctx context.Context, arg TransferParams) (TransferResult, error) {
+	var result TransferResult
+
+	if arg.Amount <= 0 {
+		return result, invalidArgument("amount must be positive")
+	}
+	if !slices.Contains(utils.SupportedCurrencies, arg.Currency) {
+		return result, invalidArgument("currency must be one of %v", utils.SupportedCurrencies)
+	}
+	if arg.BeneficiaryID != 0 && (arg.ToAccountID != 0 || arg.ToAccountNumber != "") {
+		return result, invalidArgument("beneficiary_id cannot be combined with to_account_id or to_account_number")
+	}
+
+	fromAccountID, err := service.AccountRef(ctx, "from_account", arg.FromAccountID, arg.FromAccountNumber)
+	if err != nil {
+		return result, err
+	}
+
+	allowed := func(member db.AccountMember) bool {
+		return member.CanTransfer(arg.Amount)
+	}
+	if _, err := service.AuthorizeAccount(ctx, arg.User, fromAccountID, allowed); err != nil {
+		return result, err
+	}
+
+	var toAccountID int64
+	if arg.BeneficiaryID != 0 {
+		toAccountID, err = service.beneficiaryAccount(ctx, arg.User, arg.BeneficiaryID, arg.Amount, arg.Currency)
+	} else {
+		toAccountID, err = service.AccountRef(ctx, "to_account", arg.ToAccountID, arg.ToAccountNumber)
+	}
+	if err != nil {
+		return result, err
+	}
+	if toAccountID == fromAccountID {
+		return result, invalidArgument("from_account and to_account must be different accounts")
+	}
+
+	result.FromAccount, err = service.currencyAccount(ctx, fromAccountID, arg.Currency)
+	if err != nil {
+		return result, err
+	}
+	result.ToAccount, err = service.currencyAccount(ctx, toAccountID, arg.Currency)
+	if err != nil {
+		return result, err
+	}
+
+	// a pot is ring-fenced by its parent, which the approval policy and the
+	// members of the parent guard
+	for _, account := range []db.Account{result.FromAccount, result.ToAccount} {
+		if account.ParentID.Valid {
+			return result, fmt.Errorf("account [%s] is a pot: %w", account.AccountNumber, ErrPotTransfer)
+		}
+	}
+
+	// transfers above the account's approval policy threshold wait for sign-off
+	policy, err := service.store.GetApprovalPolicy(ctx, fromAccountID)
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		return result, err
+	}
+	if err == nil && policy.NeedsApproval(arg.Amount) {
+		// a request nobody could approve would stay pending for good
+		if !policy.QuorumReachable(arg.User) {
+			return result, db.ErrQuorumUnreachable
+		}
+
+		request, err := service.store.CreateApprovalRequest(ctx, db.CreateApprovalRequestParams{
+			FromAccountID: fromAccountID,
+			ToAccountID:   toAccountID,
+			Amount:        arg.Amount,
+			RequestedBy:   arg.User,
+		})
+		if err != nil {
+			return result, err
+		}
+
+		result.ApprovalRequest = &request
+		return result, nil
+	}
+
+	transfer, err := service.store.TransferTx(ctx, db.TransferTxParams{
+		FromAccountID: fromAccountID,
+		ToAccountID:   toAccountID,
+		Amount:        arg.Amount,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrAccountFrozen):
+			metrics.TransfersRejected.WithLabelValues(metrics.RejectAccountFrozen).Inc()
+		case errors.Is(err, db.ErrInsufficientFunds):
+			metrics.TransfersRejected.WithLabelValues(metrics.RejectInsufficientFunds).Inc()
+		}
+		return result, err
+	}
+
+	result.Transfer = &transfer
+	return result, nil
+}
+
+// Beneficiary loads a beneficiary saved by user. Beneficiaries of other users
+// are reported as not found.
+func (service *Service) Beneficiary(ctx context.Context, user string, id int64) (db.Beneficiary, error) {
+	beneficiary, err := service.store.GetBeneficiary(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return beneficiary, ErrBeneficiaryNotFound
+		}
+		return beneficiary, err
+	}
+
+	if beneficiary.Owner != user {
+		return beneficiary, ErrBeneficiaryNotFound
+	}
+
+	return beneficiary, nil
+}
+
+// beneficiaryAccount returns the account of one of user's beneficiaries.
+// Transfers above the large transfer limit of their currency need a verified
+// beneficiary saved longer ago than the cooling-off period.
+func (service *Service) beneficiaryAccount(ctx context.Context, user string, beneficiaryID int64, amount int64, currency string) (int64, error) {
+	beneficiary, err := service.Beneficiary(ctx, user, beneficiaryID)
+	if err != nil {
+		return 0, err
+	}
+
+	if large := service.config.LargeTransfer(currency); amount > large {
+		if !beneficiary.Verified {
+			return 0, fmt.Errorf("%w: beneficiary [%d] is not verified for transfers above %d %s", ErrBeneficiaryNotAllowed, beneficiary.ID, large, currency)
+		}
+
+		if until := beneficiary.CreatedAt.Time.Add(service.config.BENEFICIARY_COOLING_OFF); time.Now().Before(until) {
+			return 0, fmt.Errorf("%w: beneficiary [%d] can receive transfers above %d %s after %s", ErrBeneficiaryNotAllowed, beneficiary.ID, large, currency, until.Format(time.RFC3339))
+		}
+	}
+
+	return beneficiary.AccountID, nil
+}
+
+// currencyAccount loads an account and checks that it holds currency.
+func (service *Service) currencyAccount(ctx context.Context, accountID int64, currency string) (db.Account, error) {
+	account, err := service.store.GetAccountById(ctx, accountID)
+	if err != nil {
+		return account, err
+	}
+
+	if account.Currency != currency {
+		return account, fmt.Errorf("account [%s] %w: %s VS %s", account.AccountNumber, ErrCurrencyMismatch, account.Currency, currency)
+	}
+
+	return account, nil
+}
@@ -0,0 +1,210 @@
+package bank
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/metrics"
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestService(t *testing.T) (*Service, db.Store) {
+	store := db.NewMemoryStore()
+	config := utils.Config{
+		ACCOUNT_COUNTRY_CODE: "GB",
+		ACCOUNT_BANK_CODE:    "GOBK",
+		// beneficiaries need verifying for transfers above 50 USD
+		BENEFICIARY_LARGE_TRANSFER: "USD=50",
+	}
+	return NewService(config, store), store
+}
+
+func createAccount(t *testing.T, service *Service, store db.Store, balance int64) db.Account {
+	ctx := context.Background()
+	user, err := store.CreateUser(ctx, db.CreateUserParams{
+		Username: utils.RandomOwner(),
+		FullName: "Ada Lovelace",
+		Email:    utils.RandomOwner() + "@example.com",
+	})
+	require.NoError(t, err)
+
+	result, err := service.CreateAccount(ctx, db.CreateAccountParams{
+		Owner:    user.Username,
+		Balance:  balance,
+		Currency: "USD",
+	})
+	require.NoError(t, err)
+	require.NoError(t, utils.ValidateAccountNumber(result.Account.AccountNumber))
+	return result.Account
+}
+
+func TestTransfer(t *testing.T) {
+	service, store := newTestService(t)
+	account1 := createAccount(t, service, store, 100)
+	account2 := createAccount(t, service, store, 0)
+
+	result, err := service.Transfer(context.Background(), TransferParams{
+		User:            account1.Owner,
+		FromAccountID:   account1.ID,
+		ToAccountNumber: account2.AccountNumber,
+		Amount:          10,
+		Currency:        "USD",
+	})
+	require.NoError(t, err)
+	require.Nil(t, result.ApprovalRequest)
+	require.NotNil(t, result.Transfer)
+	require.Equal(t, int64(90), result.Transfer.FromAccount.Balance)
+	require.Equal(t, int64(10), result.Transfer.ToAccount.Balance)
+}
+
+func TestTransferQuorumUnreachable(t *testing.T) {
+	service, store := newTestService(t)
+	account1 := createAccount(t, service, store, 1000)
+	account2 := createAccount(t, service, store, 0)
+
+	// the owner makes the transfer, which leaves one approver for a quorum of two
+	_, err := store.UpsertApprovalPolicy(context.Background(), db.UpsertApprovalPolicyParams{
+		AccountID:         account1.ID,
+		Threshold:         100,
+		RequiredApprovals: 2,
+		Approvers:         []string{account1.Owner, utils.RandomOwner()},
+	})
+	require.NoError(t, err)
+
+	_, err = service.Transfer(context.Background(), TransferParams{
+		User:            account1.Owner,
+		FromAccountID:   account1.ID,
+		ToAccountNumber: account2.AccountNumber,
+		Amount:          500,
+		Currency:        "USD",
+	})
+	require.ErrorIs(t, err, db.ErrQuorumUnreachable)
+
+	// transfers below the threshold need no approval
+	result, err := service.Transfer(context.Background(), TransferParams{
+		User:            account1.Owner,
+		FromAccountID:   account1.ID,
+		ToAccountNumber: account2.AccountNumber,
+		Amount:          100,
+		Currency:        "USD",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.Transfer)
+}
+
+func TestTransferPots(t *testing.T) {
+	service, store := newTestService(t)
+	account1 := createAccount(t, service, store, 100)
+	account2 := createAccount(t, service, store, 0)
+	ctx := context.Background()
+
+	pot, err := service.CreateAccount(ctx, db.CreateAccountParams{
+		Owner:    account1.Owner,
+		Currency: "USD",
+		ParentID: pgtype.Int8{Int64: account1.ID, Valid: true},
+	})
+	require.NoError(t, err)
+	_, err = store.PotTransferTx(ctx, db.TransferTxParams{FromAccountID: account1.ID, ToAccountID: pot.Account.ID, Amount: 50})
+	require.NoError(t, err)
+
+	// the owner of the parent owns the pot, but its money only leaves
+	// through the parent
+	for _, arg := range []TransferParams{
+		{FromAccountID: pot.Account.ID, ToAccountID: account2.ID},
+		{FromAccountID: account1.ID, ToAccountID: pot.Account.ID},
+	} {
+		arg.User, arg.Amount, arg.Currency = account1.Owner, 10, "USD"
+		_, err := service.Transfer(ctx, arg)
+		require.ErrorIs(t, err, ErrPotTransfer)
+	}
+
+	_, err = service.Transfer(ctx, TransferParams{User: account1.Owner, FromAccountID: account1.ID, ToAccountNumber: account1.AccountNumber, Amount: 10, Currency: "USD"})
+	var invalid *InvalidArgumentError
+	require.ErrorAs(t, err, &invalid)
+
+	potAccount, err := store.GetAccountById(ctx, pot.Account.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(50), potAccount.Balance)
+}
+
+func TestTransferRejected(t *testing.T) {
+	service, store := newTestService(t)
+	account1 := createAccount(t, service, store, 100)
+	account2 := createAccount(t, service, store, 0)
+	ctx := context.Background()
+
+	arg := TransferParams{
+		User:          account1.Owner,
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		Currency:      "USD",
+	}
+
+	_, err := service.Transfer(ctx, TransferParams{User: account2.Owner, FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 10, Currency: "USD"})
+	require.ErrorIs(t, err, ErrAccessDenied)
+
+	mismatch := arg
+	mismatch.Currency = "EUR"
+	_, err = service.Transfer(ctx, mismatch)
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	invalid := arg
+	invalid.ToAccountNumber = account2.AccountNumber
+	_, err = service.Transfer(ctx, invalid)
+	var invalidArgument *InvalidArgumentError
+	require.ErrorAs(t, err, &invalidArgument)
+
+	rejected := metrics.TransfersRejected.WithLabelValues(metrics.RejectInsufficientFunds)
+	before := testutil.ToFloat64(rejected)
+	overdraw := arg
+	overdraw.Amount = account1.Balance + 1
+	_, err = service.Transfer(ctx, overdraw)
+	require.ErrorIs(t, err, db.ErrInsufficientFunds)
+	require.Equal(t, before+1, testutil.ToFloat64(rejected))
+
+	_, err = store.FreezeAccount(ctx, db.FreezeAccountParams{AccountID: account1.ID, Reason: "test"})
+	require.NoError(t, err)
+	frozen := metrics.TransfersRejected.WithLabelValues(metrics.RejectAccountFrozen)
+	before = testutil.ToFloat64(frozen)
+	_, err = service.Transfer(ctx, arg)
+	require.ErrorIs(t, err, db.ErrAccountFrozen)
+	require.Equal(t, before+1, testutil.ToFloat64(frozen))
+}
+
+func TestTransferToBeneficiary(t *testing.T) {
+	service, store := newTestService(t)
+	account1 := createAccount(t, service, store, 100)
+	account2 := createAccount(t, service, store, 0)
+	ctx := context.Background()
+
+	beneficiary, err := store.CreateBeneficiary(ctx, db.CreateBeneficiaryParams{
+		Owner:     account1.Owner,
+		Nickname:  "savings",
+		AccountID: account2.ID,
+	})
+	require.NoError(t, err)
+
+	arg := TransferParams{
+		User:          account1.Owner,
+		FromAccountID: account1.ID,
+		BeneficiaryID: beneficiary.ID,
+		Amount:        10,
+		Currency:      "USD",
+	}
+	result, err := service.Transfer(ctx, arg)
+	require.NoError(t, err)
+	require.Equal(t, account2.ID, result.ToAccount.ID)
+
+	arg.Amount = 60
+	_, err = service.Transfer(ctx, arg)
+	require.ErrorIs(t, err, ErrBeneficiaryNotAllowed)
+
+	arg.User = account2.Owner
+	_, err = service.Beneficiary(ctx, arg.User, beneficiary.ID)
+	require.ErrorIs(t, err, ErrBeneficiaryNotFound)
+}
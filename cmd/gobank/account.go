@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/spf13/cobra"
+)
+
+func (c *cli) accountCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "account",
+		Short: "Manage accounts",
+	}
+	cmd.AddCommand(
+		c.createAccountCommand(),
+		c.listAccountsCommand(),
+		c.freezeAccountCommand(),
+		c.unfreezeAccountCommand(),
+	)
+	return cmd
+}
+
+func accountTable(accounts ...db.Account) table {
+	t := table{header: []string{"ID", "NUMBER", "OWNER", "BALANCE", "CURRENCY", "PARENT", "CREATED AT"}}
+	for _, account := range accounts {
+		t.add(account.ID, account.AccountNumber, account.Owner, account.Balance, account.Currency, account.ParentID, account.CreatedAt)
+	}
+	return t
+}
+
+func (c *cli) createAccountCommand() *cobra.Command {
+	var arg db.CreateAccountParams
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Open an account",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if arg.Balance < 0 {
+				return fmt.Errorf("opening balance must not be negative, got %d", arg.Balance)
+			}
+
+			return c.withStore(cmd.Context(), func(store db.Store) error {
+				// the store books the opening balance as an entry
+				result, err := store.CreateAccountTx(cmd.Context(), arg,
+					db.WithGeneratedNumber(c.config.ACCOUNT_COUNTRY_CODE, c.config.ACCOUNT_BANK_CODE))
+				if err != nil {
+					return err
+				}
+				return c.print(cmd.OutOrStdout(), result.Account, accountTable(result.Account))
+			})
+		},
+	}
+	cmd.Flags().StringVar(&arg.Owner, "owner", "", "username of the owner")
+	cmd.Flags().StringVar(&arg.Currency, "currency", "", "currency of the account, e.g. USD")
+	cmd.Flags().Int64Var(&arg.Balance, "balance", 0, "opening balance")
+	cmd.MarkFlagRequired("owner")
+	cmd.MarkFlagRequired("currency")
+	return cmd
+}
+
+func (c *cli) listAccountsCommand() *cobra.Command {
+	var page pageFlags
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List accounts",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.withStore(cmd.Context(), func(store db.Store) error {
+				accounts, err := store.ListAccounts(cmd.Context(), db.ListAccountsParams{
+					Limit:  page.limit(),
+					Offset: page.offset(),
+				})
+				if err != nil {
+					return err
+				}
+				return c.print(cmd.OutOrStdout(), accounts, accountTable(accounts...))
+			})
+		},
+	}
+	page.register(cmd)
+	return cmd
+}
+
+// accountID parses the account id argument of a command.
+func accountID(arg string) (int64, error) {
+	return strconv.ParseInt(arg, 10, 64)
+}
+
+func (c *cli) freezeAccountCommand() *cobra.Command {
+	var reason string
+
+	cmd := &cobra.Command{
+		Use:   "freeze ACCOUNT_ID",
+		Short: "Stop an account from sending or receiving money",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := accountID(args[0])
+			if err != nil {
+				return err
+			}
+
+			return c.withStore(cmd.Context(), func(store db.Store) error {
+				freeze, err := store.FreezeAccount(cmd.Context(), db.FreezeAccountParams{
+					AccountID: id,
+					Reason:    reason,
+				})
+				if err != nil {
+					return err
+				}
+
+				t := table{header: []string{"ACCOUNT", "REASON", "FROZEN AT"}}
+				t.add(freeze.AccountID, freeze.Reason, freeze.CreatedAt)
+				return c.print(cmd.OutOrStdout(), freeze, t)
+			})
+		},
+	}
+	cmd.Flags().StringVar(&reason, "reason", "", "why the account is frozen")
+	cmd.MarkFlagRequired("reason")
+	return cmd
+}
+
+func (c *cli) unfreezeAccountCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unfreeze ACCOUNT_ID",
+		Short: "Let a frozen account send and receive money again",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := accountID(args[0])
+			if err != nil {
+				return err
+			}
+
+			return c.withStore(cmd.Context(), func(store db.Store) error {
+				account, err := store.GetAccountById(cmd.Context(), id)
+				if err != nil {
+					return err
+				}
+				if err := store.UnfreezeAccount(cmd.Context(), id); err != nil {
+					return err
+				}
+				return c.print(cmd.OutOrStdout(), account, accountTable(account))
+			})
+		},
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"strconv"
+
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/spf13/cobra"
+)
+
+func (c *cli) beneficiaryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "beneficiary",
+		Short: "Manage the beneficiaries saved by users",
+	}
+	cmd.AddCommand(c.verifyBeneficiaryCommand())
+	return cmd
+}
+
+func beneficiaryTable(beneficiaries ...db.Beneficiary) table {
+	t := table{header: []string{"ID", "OWNER", "NICKNAME", "ACCOUNT", "VERIFIED", "CREATED AT"}}
+	for _, beneficiary := range beneficiaries {
+		t.add(beneficiary.ID, beneficiary.Owner, beneficiary.Nickname, beneficiary.AccountID, beneficiary.Verified, beneficiary.CreatedAt)
+	}
+	return t
+}
+
+func (c *cli) verifyBeneficiaryCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify BENEFICIARY_ID",
+		Short: "Mark a beneficiary whose payee was confirmed, allowing large transfers after its cooling-off period",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return err
+			}
+
+			return c.withStore(cmd.Context(), func(store db.Store) error {
+				beneficiary, err := store.VerifyBeneficiary(cmd.Context(), id)
+				if err != nil {
+					return err
+				}
+				return c.print(cmd.OutOrStdout(), beneficiary, beneficiaryTable(beneficiary))
+			})
+		},
+	}
+}
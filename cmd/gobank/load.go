@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	"slices"
+	"time"
+
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/fixture"
+	"github.com/singhJasvinder101/go_bank/loadtest"
+	"github.com/singhJasvinder101/go_bank/token"
+	"github.com/spf13/cobra"
+)
+
+// loadTokenMargin is how long the access tokens of a load test stay valid
+// beyond its duration, for creating the pool.
+const loadTokenMargin = time.Hour
+
+func (c *cli) loadCommand() *cobra.Command {
+	var (
+		cfg  loadtest.Config
+		pool fixture.Options
+	)
+
+	cmd := &cobra.Command{
+		Use:   "load",
+		Short: "Load test a running server over its HTTP API",
+		Long: "Register a pool of users and a funding account per currency in the\n" +
+			"database, open and fund the accounts of the users through the API with\n" +
+			"access tokens signed with TOKEN_SYMMETRIC_KEY, then send a mix of\n" +
+			"transfers, account reads and account listings as these users to the\n" +
+			"server at --target at --rate requests per second, and report latency\n" +
+			"percentiles, errors and throughput for each kind of request.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if c.dryRun {
+				return errors.New("load needs its users and funding accounts committed, it cannot run with --dry-run")
+			}
+			if c.config.TOKEN_SYMMETRIC_KEY == "" {
+				return errors.New("load authenticates its users with access tokens, TOKEN_SYMMETRIC_KEY is not set")
+			}
+			if cfg.Seed == 0 {
+				cfg.Seed = time.Now().UnixNano()
+			}
+			if cfg.Target == "" {
+				cfg.Target = targetURL(c.config.ADDRESS)
+			}
+			if err := cfg.Validate(); err != nil {
+				return err
+			}
+			pool.Seed = cfg.Seed
+			pool.CountryCode = c.config.ACCOUNT_COUNTRY_CODE
+			pool.BankCode = c.config.ACCOUNT_BANK_CODE
+
+			accounts, err := c.createLoadPool(cmd.Context(), cfg, fixture.Generate(pool))
+			if err != nil {
+				return fmt.Errorf("cannot create the pool of accounts: %w", err)
+			}
+
+			report, err := loadtest.Run(cmd.Context(), http.DefaultClient, cfg, accounts)
+			if err != nil {
+				return err
+			}
+			return c.printLoadReport(cmd.OutOrStdout(), report)
+		},
+	}
+	cmd.Flags().StringVar(&cfg.Target, "target", "", "base URL of the server, from ADDRESS if empty")
+	cmd.Flags().IntVar(&cfg.Rate, "rate", 50, fmt.Sprintf("requests to start per second, at most %d", loadtest.MaxRate))
+	cmd.Flags().DurationVar(&cfg.Duration, "duration", 10*time.Second, "how long to send requests")
+	cmd.Flags().IntVar(&cfg.Concurrency, "concurrency", 64, "maximum requests in flight, requests due beyond it are dropped")
+	cmd.Flags().StringToIntVar(&cfg.Mix, "mix",
+		map[string]int{loadtest.OpTransfer: 6, loadtest.OpGetAccount: 3, loadtest.OpListAccounts: 1},
+		"weights of the operations: transfer, get and list")
+	cmd.Flags().Int64Var(&cfg.MaxAmount, "max-amount", 10, "maximum amount of a transfer")
+	cmd.Flags().DurationVar(&cfg.Timeout, "timeout", 5*time.Second, "timeout of a request")
+	cmd.Flags().Int64Var(&cfg.Seed, "seed", 0, "seed of the pool and the requests, random if 0")
+	cmd.Flags().IntVar(&pool.Users, "users", 20, "number of users in the pool")
+	cmd.Flags().IntVar(&pool.AccountsPerUser, "accounts", 2, "number of accounts per user")
+	return cmd
+}
+
+// createLoadPool registers the users of data and a funding account for each
+// currency of its accounts, which the API has no way to create, then opens
+// and funds the accounts of data through the API.
+func (c *cli) createLoadPool(ctx context.Context, cfg loadtest.Config, data fixture.Dataset) ([]loadtest.Account, error) {
+	maker, err := token.NewJWTMaker(c.config.TOKEN_SYMMETRIC_KEY)
+	if err != nil {
+		return nil, err
+	}
+	users := make(map[string]loadtest.User)
+	issue := func(username string) error {
+		accessToken, err := maker.CreateToken(username, cfg.Duration+loadTokenMargin)
+		users[username] = loadtest.User{Username: username, Token: accessToken}
+		return err
+	}
+
+	funder := db.CreateUserParams{
+		Username: fmt.Sprintf("loadfunder%x", uint64(cfg.Seed)),
+		FullName: "Load Test Funder",
+	}
+	funder.Email = funder.Username + "@example.com"
+
+	// the funding accounts open with the balances of the pool, booked as
+	// opening entries
+	balances := make(map[string]int64)
+	for _, arg := range data.Accounts {
+		balances[arg.Currency] += arg.Balance
+	}
+
+	funding := make(map[string]string)
+	err = c.withStore(ctx, func(store db.Store) error {
+		for _, arg := range append(data.Users, funder) {
+			if _, err := store.CreateUser(ctx, arg); err != nil {
+				return fmt.Errorf("create user %s: %w", arg.Username, err)
+			}
+			if err := issue(arg.Username); err != nil {
+				return err
+			}
+		}
+
+		for _, currency := range slices.Sorted(maps.Keys(balances)) {
+			result, err := store.CreateAccountTx(ctx, db.CreateAccountParams{
+				Owner:    funder.Username,
+				Balance:  balances[currency],
+				Currency: currency,
+			}, db.WithGeneratedNumber(c.config.ACCOUNT_COUNTRY_CODE, c.config.ACCOUNT_BANK_CODE))
+			if err != nil {
+				return fmt.Errorf("create funding account in %s: %w", currency, err)
+			}
+			funding[currency] = result.Account.AccountNumber
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pool := loadtest.Pool{Funder: users[funder.Username], Funding: funding}
+	for _, arg := range data.Accounts {
+		pool.Accounts = append(pool.Accounts, loadtest.PoolAccount{
+			Owner:    users[arg.Owner],
+			Currency: arg.Currency,
+			Balance:  arg.Balance,
+		})
+	}
+	return loadtest.CreatePool(ctx, http.DefaultClient, cfg.Target, pool)
+}
+
+// targetURL returns the URL of the server listening on address, reaching
+// it on localhost when it listens on all interfaces.
+func targetURL(address string) string {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "http://" + address
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	return "http://" + net.JoinHostPort(host, port)
+}
+
+// printLoadReport prints report as JSON, or as a table of the operations
+// followed by a table of their errors.
+func (c *cli) printLoadReport(w io.Writer, report loadtest.Report) error {
+	t := table{header: []string{"OPERATION", "REQUESTS", "ERRORS", "THROUGHPUT", "P50", "P90", "P99", "MAX"}}
+	addRow := func(name string, requests, failed int, throughput float64, latency loadtest.Latency) {
+		t.add(name, requests, failed, fmt.Sprintf("%.1f/s", throughput),
+			round(latency.P50), round(latency.P90), round(latency.P99), round(latency.Max))
+	}
+	for _, op := range report.Operations {
+		addRow(op.Name, op.Requests, op.Errors, op.Throughput, op.Latency)
+	}
+	addRow("total", report.Requests, report.Errors, report.Throughput, report.Latency)
+
+	if err := c.print(w, report, t); err != nil || c.output == outputJSON {
+		return err
+	}
+
+	fmt.Fprintf(w, "\nseed %d, %s, %d requests dropped\n", report.Seed, round(report.Duration), report.Dropped)
+	if report.Errors == 0 {
+		return nil
+	}
+
+	errorTable := table{header: []string{"OPERATION", "ERROR", "COUNT"}}
+	for _, op := range report.Operations {
+		for _, kind := range slices.Sorted(maps.Keys(op.ErrorKinds)) {
+			errorTable.add(op.Name, kind, op.ErrorKinds[kind])
+		}
+	}
+	fmt.Fprintln(w)
+	return c.print(w, report, errorTable)
+}
+
+// round shortens a latency for display.
+func round(d time.Duration) time.Duration {
+	if d > time.Second {
+		return d.Round(time.Millisecond)
+	}
+	return d.Round(10 * time.Microsecond)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/singhJasvinder101/go_bank/loadtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTargetURL(t *testing.T) {
+	require.Equal(t, "http://localhost:3000", targetURL("0.0.0.0:3000"))
+	require.Equal(t, "http://localhost:3000", targetURL(":3000"))
+	require.Equal(t, "http://bank.internal:8080", targetURL("bank.internal:8080"))
+}
+
+func TestPrintLoadReport(t *testing.T) {
+	latency := loadtest.Latency{P50: time.Millisecond, P90: 2 * time.Millisecond, P99: 5 * time.Millisecond, Max: 7 * time.Millisecond}
+	report := loadtest.Report{
+		Seed:       42,
+		Duration:   10 * time.Second,
+		Requests:   100,
+		Errors:     2,
+		Throughput: 9.8,
+		Latency:    latency,
+		Operations: []loadtest.OperationReport{{
+			Name:       loadtest.OpTransfer,
+			Requests:   100,
+			Errors:     2,
+			Throughput: 9.8,
+			Latency:    latency,
+			ErrorKinds: map[string]int{"429": 1, "timeout": 1},
+		}},
+	}
+
+	var out bytes.Buffer
+	c := &cli{output: outputTable}
+	require.NoError(t, c.printLoadReport(&out, report))
+	require.Equal(t, ""+
+		"OPERATION  REQUESTS  ERRORS  THROUGHPUT  P50  P90  P99  MAX\n"+
+		"transfer   100       2       9.8/s       1ms  2ms  5ms  7ms\n"+
+		"total      100       2       9.8/s       1ms  2ms  5ms  7ms\n"+
+		"\n"+
+		"seed 42, 10s, 0 requests dropped\n"+
+		"\n"+
+		"OPERATION  ERROR    COUNT\n"+
+		"transfer   429      1\n"+
+		"transfer   timeout  1\n", out.String())
+
+	out.Reset()
+	c.output = outputJSON
+	require.NoError(t, c.printLoadReport(&out, report))
+	require.Contains(t, out.String(), `"error_kinds": {`)
+	require.NotContains(t, out.String(), "OPERATION")
+}
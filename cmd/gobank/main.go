@@ -0,0 +1,97 @@
+// Command gobank is the admin tool of the bank. It works on the database at
+// DB_SOURCE through db.Store, so its changes follow the same rules as the
+// API's, and runs every command in one transaction.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := newRootCommand().ExecuteContext(ctx); err != nil {
+		os.Exit(1)
+	}
+}
+
+// cli holds the global flags and the config shared by all commands.
+type cli struct {
+	config utils.Config
+	output string
+	dryRun bool
+}
+
+func newRootCommand() *cobra.Command {
+	c := &cli{}
+
+	root := &cobra.Command{
+		Use:          "gobank",
+		Short:        "Administer the bank",
+		SilenceUsage: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if c.output != outputTable && c.output != outputJSON {
+				return fmt.Errorf("unknown output %q: want %s or %s", c.output, outputTable, outputJSON)
+			}
+
+			var err error
+			c.config, err = utils.LoadToolConfig([]string{".", "/app"})
+			return err
+		},
+	}
+	root.PersistentFlags().StringVarP(&c.output, "output", "o", outputTable, "output format: table or json")
+	root.PersistentFlags().BoolVar(&c.dryRun, "dry-run", false, "roll back the changes of the command instead of committing them")
+
+	root.AddCommand(
+		c.userCommand(),
+		c.accountCommand(),
+		c.beneficiaryCommand(),
+		c.transferCommand(),
+		c.reconcileCommand(),
+		c.statementCommand(),
+		c.seedCommand(),
+		c.loadCommand(),
+	)
+	return root
+}
+
+// withStore runs fn on a store within a transaction, which is committed
+// when fn succeeds or rolled back on failure and with --dry-run.
+func (c *cli) withStore(ctx context.Context, fn func(store db.Store) error) error {
+	pool, err := pgxpool.New(ctx, c.config.DB_SOURCE)
+	if err != nil {
+		return fmt.Errorf("cannot connect to db: %w", err)
+	}
+	defer pool.Close()
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	if err := fn(db.NewStore(tx)); err != nil {
+		return err
+	}
+
+	if c.dryRun {
+		fmt.Fprintln(os.Stderr, "dry run, changes rolled back")
+		return tx.Rollback(ctx)
+	}
+	return tx.Commit(ctx)
+}
+
+// errInconsistent makes a command exit with a failure after printing its
+// result, e.g. when reconciliation finds a discrepancy.
+var errInconsistent = errors.New("ledger is inconsistent")
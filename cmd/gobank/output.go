@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Output formats selected with --output.
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// table is the tabular form of a command's result.
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+func (t *table) add(cells ...any) {
+	row := make([]string, len(cells))
+	for i, cell := range cells {
+		row[i] = formatCell(cell)
+	}
+	t.rows = append(t.rows, row)
+}
+
+func formatCell(cell any) string {
+	switch v := cell.(type) {
+	case pgtype.Timestamp:
+		if !v.Valid {
+			return ""
+		}
+		return v.Time.Format(time.RFC3339)
+	case pgtype.Int8:
+		if !v.Valid {
+			return ""
+		}
+		return fmt.Sprint(v.Int64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// print writes v as JSON, or t as an aligned table.
+func (c *cli) print(w io.Writer, v any, t table) error {
+	if c.output == outputJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(t.header, "\t"))
+	for _, row := range t.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
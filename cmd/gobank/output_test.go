@@ -0,0 +1,26 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrint(t *testing.T) {
+	users := []db.User{{Username: "alice", FullName: "Alice Smith", Email: "alice@example.com"}}
+
+	var out bytes.Buffer
+	c := &cli{output: outputTable}
+	require.NoError(t, c.print(&out, users, userTable(users...)))
+	require.Equal(t, "USERNAME  FULL NAME    EMAIL              CREATED AT\nalice     Alice Smith  alice@example.com  \n", out.String())
+
+	out.Reset()
+	c.output = outputJSON
+	require.NoError(t, c.print(&out, users, userTable(users...)))
+	var got []db.User
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	require.Equal(t, users, got)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/spf13/cobra"
+)
+
+// unbalancedLimit bounds how many unbalanced accounts are listed.
+const unbalancedLimit = 100
+
+// reconciliation is the result of the reconcile command.
+type reconciliation struct {
+	db.GetLedgerTotalsRow
+	Unbalanced []db.ListUnbalancedAccountsRow `json:"unbalanced"`
+	Problems   []string                       `json:"problems"`
+}
+
+// reconcile checks the invariants of the ledger totals and names the
+// accounts whose balance differs from their entries.
+func reconcile(totals db.GetLedgerTotalsRow, unbalanced []db.ListUnbalancedAccountsRow) reconciliation {
+	result := reconciliation{GetLedgerTotalsRow: totals, Unbalanced: unbalanced, Problems: []string{}}
+	if totals.EntryTotal != totals.OpeningTotal {
+		result.Problems = append(result.Problems, fmt.Sprintf("entries sum up to %d instead of the opening balances %d", totals.EntryTotal, totals.OpeningTotal))
+	}
+	if totals.EntryCount != 2*totals.TransferCount+totals.OpeningCount {
+		result.Problems = append(result.Problems, fmt.Sprintf("%d entries for %d transfers and %d opening balances, want two per transfer and one per opening balance",
+			totals.EntryCount, totals.TransferCount, totals.OpeningCount))
+	}
+	if totals.NegativeAccounts > 0 {
+		result.Problems = append(result.Problems, fmt.Sprintf("%d accounts have a negative balance", totals.NegativeAccounts))
+	}
+	if totals.UnbalancedAccounts > 0 {
+		result.Problems = append(result.Problems, fmt.Sprintf("%d accounts have a balance other than the sum of their entries", totals.UnbalancedAccounts))
+	}
+	for _, account := range unbalanced {
+		result.Problems = append(result.Problems, fmt.Sprintf("account %d (%s) has balance %d, but its entries add up to %d",
+			account.ID, account.AccountNumber, account.Balance, account.EntryTotal))
+	}
+	return result
+}
+
+func (c *cli) reconcileCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "reconcile",
+		Short: "Check that the ledger balances",
+		Long: "Check that the entries of all transfers cancel out, that every transfer\n" +
+			"booked exactly two entries, that the balance of every account is the sum of\n" +
+			"its entries and that no account is overdrawn. Exits with a failure when a\n" +
+			"check fails.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var result reconciliation
+
+			err := c.withStore(cmd.Context(), func(store db.Store) error {
+				totals, err := store.GetLedgerTotals(cmd.Context())
+				if err != nil {
+					return err
+				}
+				unbalanced, err := store.ListUnbalancedAccounts(cmd.Context(), unbalancedLimit)
+				if err != nil {
+					return err
+				}
+				result = reconcile(totals, unbalanced)
+
+				t := table{header: []string{"CHECK", "VALUE"}}
+				t.add("transfers", totals.TransferCount)
+				t.add("transferred", totals.TransferTotal)
+				t.add("entries", totals.EntryCount)
+				t.add("entry total", totals.EntryTotal)
+				t.add("opening balances", totals.OpeningCount)
+				t.add("opening total", totals.OpeningTotal)
+				t.add("negative accounts", totals.NegativeAccounts)
+				t.add("unbalanced accounts", totals.UnbalancedAccounts)
+				for _, problem := range result.Problems {
+					t.add("problem", problem)
+				}
+				return c.print(cmd.OutOrStdout(), result, t)
+			})
+			if err != nil {
+				return err
+			}
+
+			if len(result.Problems) > 0 {
+				return errInconsistent
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcile(t *testing.T) {
+	result := reconcile(db.GetLedgerTotalsRow{
+		EntryCount:    5,
+		EntryTotal:    100,
+		OpeningCount:  1,
+		OpeningTotal:  100,
+		TransferCount: 2,
+		TransferTotal: 30,
+	}, []db.ListUnbalancedAccountsRow{})
+	require.Empty(t, result.Problems)
+
+	result = reconcile(db.GetLedgerTotalsRow{
+		EntryCount:         3,
+		EntryTotal:         10,
+		TransferCount:      2,
+		NegativeAccounts:   1,
+		UnbalancedAccounts: 1,
+	}, []db.ListUnbalancedAccountsRow{{ID: 7, AccountNumber: "GB00GOBK00000007", Balance: 50, EntryTotal: 10}})
+	require.Equal(t, []string{
+		"entries sum up to 10 instead of the opening balances 0",
+		"3 entries for 2 transfers and 0 opening balances, want two per transfer and one per opening balance",
+		"1 accounts have a negative balance",
+		"1 accounts have a balance other than the sum of their entries",
+		"account 7 (GB00GOBK00000007) has balance 50, but its entries add up to 10",
+	}, result.Problems)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"time"
+
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/fixture"
+	"github.com/spf13/cobra"
+)
+
+// seedResult counts what the seed command created.
+type seedResult struct {
+	Seed      int64 `json:"seed"`
+	Users     int   `json:"users"`
+	Accounts  int   `json:"accounts"`
+	Transfers int   `json:"transfers"`
+}
+
+func (c *cli) seedCommand() *cobra.Command {
+	opts := fixture.Options{}
+
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Fill the database with sample users, accounts and transfers",
+		Long: "Fill the database with users with realistic names, accounts in the\n" +
+			"supported currencies and a history of transfers between them. The same\n" +
+			"--seed generates the same data; without it a seed is picked and printed.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Seed == 0 {
+				opts.Seed = time.Now().UnixNano()
+			}
+			opts.CountryCode = c.config.ACCOUNT_COUNTRY_CODE
+			opts.BankCode = c.config.ACCOUNT_BANK_CODE
+
+			return c.withStore(cmd.Context(), func(store db.Store) error {
+				loaded, err := fixture.Load(cmd.Context(), store, fixture.Generate(opts))
+				if err != nil {
+					return err
+				}
+
+				result := seedResult{
+					Seed:      opts.Seed,
+					Users:     len(loaded.Users),
+					Accounts:  len(loaded.Accounts),
+					Transfers: len(loaded.Transfers),
+				}
+				t := table{header: []string{"SEED", "USERS", "ACCOUNTS", "TRANSFERS"}}
+				t.add(result.Seed, result.Users, result.Accounts, result.Transfers)
+				return c.print(cmd.OutOrStdout(), result, t)
+			})
+		},
+	}
+	cmd.Flags().Int64Var(&opts.Seed, "seed", 0, "seed of the generated data, random if 0")
+	cmd.Flags().IntVar(&opts.Users, "users", 10, "number of users to create")
+	cmd.Flags().IntVar(&opts.AccountsPerUser, "accounts", 2, "number of accounts per user")
+	cmd.Flags().IntVar(&opts.Transfers, "transfers", 50, "number of transfers between the new accounts")
+	return cmd
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"time"
+
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/spf13/cobra"
+)
+
+// dateLayout is the format of the --from and --to flags.
+const dateLayout = "2006-01-02"
+
+// statement lists the entries of an account over a period with the balance
+// after each of them.
+type statement struct {
+	Account        db.Account                      `json:"account"`
+	From           string                          `json:"from,omitempty"`
+	To             string                          `json:"to,omitempty"`
+	OpeningBalance int64                           `json:"opening_balance"`
+	ClosingBalance int64                           `json:"closing_balance"`
+	Entries        []db.ListAccountEntriesAfterRow `json:"entries"`
+}
+
+// newStatement keeps the entries booked in [from, to). A zero time leaves
+// the period open at that end.
+func newStatement(account db.Account, entries []db.ListAccountEntriesAfterRow, from, to time.Time) statement {
+	s := statement{Account: account, Entries: []db.ListAccountEntriesAfterRow{}}
+	if !from.IsZero() {
+		s.From = from.Format(dateLayout)
+	}
+	if !to.IsZero() {
+		s.To = to.Format(dateLayout)
+	}
+
+	// without entries in the period the balance is the one after the last
+	// entry before it, or the current one if there is none after it
+	s.OpeningBalance = account.Balance
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].CreatedAt.Time.Before(from) {
+			break
+		}
+		s.OpeningBalance = entries[i].Balance - entries[i].Amount
+	}
+	s.ClosingBalance = s.OpeningBalance
+
+	for _, entry := range entries {
+		at := entry.CreatedAt.Time
+		if at.Before(from) || (!to.IsZero() && !at.Before(to)) {
+			continue
+		}
+		s.Entries = append(s.Entries, entry)
+		s.ClosingBalance = entry.Balance
+	}
+	return s
+}
+
+func (c *cli) statementCommand() *cobra.Command {
+	var fromFlag, toFlag string
+
+	cmd := &cobra.Command{
+		Use:   "statement ACCOUNT_ID",
+		Short: "Export the statement of an account",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := accountID(args[0])
+			if err != nil {
+				return err
+			}
+
+			var from, to time.Time
+			if fromFlag != "" {
+				if from, err = time.Parse(dateLayout, fromFlag); err != nil {
+					return err
+				}
+			}
+			if toFlag != "" {
+				if to, err = time.Parse(dateLayout, toFlag); err != nil {
+					return err
+				}
+				// the statement includes the whole last day
+				to = to.AddDate(0, 0, 1)
+			}
+
+			return c.withStore(cmd.Context(), func(store db.Store) error {
+				account, err := store.GetAccountById(cmd.Context(), id)
+				if err != nil {
+					return err
+				}
+				entries, err := store.ListAccountEntriesAfter(cmd.Context(), db.ListAccountEntriesAfterParams{
+					AccountID: id,
+					AfterID:   0,
+				})
+				if err != nil {
+					return err
+				}
+
+				s := newStatement(account, entries, from, to)
+				t := table{header: []string{"ENTRY", "DATE", "AMOUNT", "BALANCE"}}
+				t.add("", "opening", "", s.OpeningBalance)
+				for _, entry := range s.Entries {
+					t.add(entry.ID, entry.CreatedAt, entry.Amount, entry.Balance)
+				}
+				t.add("", "closing", "", s.ClosingBalance)
+				return c.print(cmd.OutOrStdout(), s, t)
+			})
+		},
+	}
+	cmd.Flags().StringVar(&fromFlag, "from", "", "first day of the statement, YYYY-MM-DD")
+	cmd.Flags().StringVar(&toFlag, "to", "", "last day of the statement, YYYY-MM-DD")
+	return cmd
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func entryAt(id int64, day int, amount, balance int64) db.ListAccountEntriesAfterRow {
+	return db.ListAccountEntriesAfterRow{
+		ID:        id,
+		Amount:    amount,
+		Balance:   balance,
+		CreatedAt: pgtype.Timestamp{Time: time.Date(2025, 1, day, 12, 0, 0, 0, time.UTC), Valid: true},
+	}
+}
+
+func TestNewStatement(t *testing.T) {
+	account := db.Account{ID: 1, Balance: 70}
+	entries := []db.ListAccountEntriesAfterRow{
+		entryAt(1, 1, 100, 100),
+		entryAt(2, 5, -50, 50),
+		entryAt(3, 10, 30, 80),
+		entryAt(4, 20, -10, 70),
+	}
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	s := newStatement(account, entries, time.Time{}, time.Time{})
+	require.Len(t, s.Entries, 4)
+	require.Equal(t, int64(0), s.OpeningBalance)
+	require.Equal(t, int64(70), s.ClosingBalance)
+
+	s = newStatement(account, entries, day(5), day(11))
+	require.Equal(t, []int64{2, 3}, []int64{s.Entries[0].ID, s.Entries[1].ID})
+	require.Equal(t, int64(100), s.OpeningBalance)
+	require.Equal(t, int64(80), s.ClosingBalance)
+
+	// a period without entries keeps the balance it started with
+	s = newStatement(account, entries, day(11), day(15))
+	require.Empty(t, s.Entries)
+	require.Equal(t, int64(80), s.OpeningBalance)
+	require.Equal(t, int64(80), s.ClosingBalance)
+}
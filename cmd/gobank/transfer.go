@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/spf13/cobra"
+)
+
+func (c *cli) transferCommand() *cobra.Command {
+	var arg db.TransferTxParams
+
+	cmd := &cobra.Command{
+		Use:   "transfer",
+		Short: "Move money between two accounts",
+		Long: "Move money between two accounts of the same currency. Unlike the API the\n" +
+			"transfer is made on behalf of the bank: it needs no membership or approval,\n" +
+			"but frozen accounts and overdrafts are refused.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if arg.Amount <= 0 {
+				return fmt.Errorf("amount must be positive")
+			}
+
+			return c.withStore(cmd.Context(), func(store db.Store) error {
+				from, err := store.GetAccountById(cmd.Context(), arg.FromAccountID)
+				if err != nil {
+					return fmt.Errorf("account [%d]: %w", arg.FromAccountID, err)
+				}
+				to, err := store.GetAccountById(cmd.Context(), arg.ToAccountID)
+				if err != nil {
+					return fmt.Errorf("account [%d]: %w", arg.ToAccountID, err)
+				}
+				if from.Currency != to.Currency {
+					return fmt.Errorf("account [%d] is in %s, account [%d] in %s", from.ID, from.Currency, to.ID, to.Currency)
+				}
+
+				result, err := store.TransferTx(cmd.Context(), arg)
+				if err != nil {
+					return err
+				}
+
+				t := table{header: []string{"TRANSFER", "FROM", "TO", "AMOUNT", "FROM BALANCE", "TO BALANCE"}}
+				t.add(result.Transfer.ID, result.FromAccount.ID, result.ToAccount.ID, result.Transfer.Amount,
+					result.FromAccount.Balance, result.ToAccount.Balance)
+				return c.print(cmd.OutOrStdout(), result, t)
+			})
+		},
+	}
+	cmd.Flags().Int64Var(&arg.FromAccountID, "from", 0, "id of the account sending the money")
+	cmd.Flags().Int64Var(&arg.ToAccountID, "to", 0, "id of the account receiving the money")
+	cmd.Flags().Int64Var(&arg.Amount, "amount", 0, "amount to transfer")
+	cmd.MarkFlagRequired("from")
+	cmd.MarkFlagRequired("to")
+	cmd.MarkFlagRequired("amount")
+	return cmd
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"errors"
+	"time"
+
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/token"
+	"github.com/spf13/cobra"
+)
+
+func (c *cli) userCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Manage users",
+	}
+	cmd.AddCommand(c.createUserCommand(), c.listUsersCommand(), c.userTokenCommand())
+	return cmd
+}
+
+func userTable(users ...db.User) table {
+	t := table{header: []string{"USERNAME", "FULL NAME", "EMAIL", "CREATED AT"}}
+	for _, user := range users {
+		t.add(user.Username, user.FullName, user.Email, user.CreatedAt)
+	}
+	return t
+}
+
+func (c *cli) createUserCommand() *cobra.Command {
+	var arg db.CreateUserParams
+
+	cmd := &cobra.Command{
+		Use:   "create USERNAME",
+		Short: "Create a user",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			arg.Username = args[0]
+			return c.withStore(cmd.Context(), func(store db.Store) error {
+				user, err := store.CreateUser(cmd.Context(), arg)
+				if err != nil {
+					return err
+				}
+				return c.print(cmd.OutOrStdout(), user, userTable(user))
+			})
+		},
+	}
+	cmd.Flags().StringVar(&arg.FullName, "name", "", "full name of the user")
+	cmd.Flags().StringVar(&arg.Email, "email", "", "email address of the user")
+	cmd.MarkFlagRequired("name")
+	cmd.MarkFlagRequired("email")
+	return cmd
+}
+
+func (c *cli) listUsersCommand() *cobra.Command {
+	var page pageFlags
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List users",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.withStore(cmd.Context(), func(store db.Store) error {
+				users, err := store.ListUsers(cmd.Context(), db.ListUsersParams{
+					Limit:  page.limit(),
+					Offset: page.offset(),
+				})
+				if err != nil {
+					return err
+				}
+				return c.print(cmd.OutOrStdout(), users, userTable(users...))
+			})
+		},
+	}
+	page.register(cmd)
+	return cmd
+}
+
+// userToken is the result of the user token command.
+type userToken struct {
+	Username    string    `json:"username"`
+	AccessToken string    `json:"access_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+func (c *cli) userTokenCommand() *cobra.Command {
+	var duration time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "token USERNAME",
+		Short: "Issue an access token for a user, signed with TOKEN_SYMMETRIC_KEY",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if c.config.TOKEN_SYMMETRIC_KEY == "" {
+				return errors.New("TOKEN_SYMMETRIC_KEY is not set")
+			}
+			maker, err := token.NewJWTMaker(c.config.TOKEN_SYMMETRIC_KEY)
+			if err != nil {
+				return err
+			}
+			if duration == 0 {
+				duration = c.config.ACCESS_TOKEN_DURATION
+			}
+
+			return c.withStore(cmd.Context(), func(store db.Store) error {
+				user, err := store.GetUser(cmd.Context(), args[0])
+				if err != nil {
+					return err
+				}
+
+				result := userToken{Username: user.Username, ExpiresAt: time.Now().Add(duration).UTC()}
+				result.AccessToken, err = maker.CreateToken(user.Username, duration)
+				if err != nil {
+					return err
+				}
+
+				t := table{header: []string{"USERNAME", "ACCESS TOKEN", "EXPIRES AT"}}
+				t.add(result.Username, result.AccessToken, result.ExpiresAt.Format(time.RFC3339))
+				return c.print(cmd.OutOrStdout(), result, t)
+			})
+		},
+	}
+	cmd.Flags().DurationVar(&duration, "duration", 0, "how long the token is valid, ACCESS_TOKEN_DURATION by default")
+	return cmd
+}
+
+// pageFlags select a page of a listing.
+type pageFlags struct {
+	page int32
+	size int32
+}
+
+func (p *pageFlags) register(cmd *cobra.Command) {
+	cmd.Flags().Int32Var(&p.page, "page", 1, "page to list, from 1")
+	cmd.Flags().Int32Var(&p.size, "page-size", 50, "number of items per page")
+}
+
+func (p *pageFlags) limit() int32 {
+	return p.size
+}
+
+func (p *pageFlags) offset() int32 {
+	return (max(p.page, 1) - 1) * p.size
+}
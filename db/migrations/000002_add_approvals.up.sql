@@ -0,0 +1,54 @@
+CREATE TABLE "approval_policies" (
+  "account_id" bigint PRIMARY KEY,
+  "threshold" bigint NOT NULL,
+  "required_approvals" int NOT NULL,
+  "approvers" varchar[] NOT NULL,
+  "created_at" timestamp DEFAULT (now())
+);
+
+CREATE TABLE "approval_requests" (
+  "id" bigserial PRIMARY KEY,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "requested_by" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "transfer_id" bigint,
+  "created_at" timestamp DEFAULT (now())
+);
+
+CREATE TABLE "approval_decisions" (
+  "id" bigserial PRIMARY KEY,
+  "request_id" bigint NOT NULL,
+  "approver" varchar NOT NULL,
+  "approved" boolean NOT NULL,
+  "created_at" timestamp DEFAULT (now())
+);
+
+CREATE INDEX ON "approval_requests" ("from_account_id");
+
+CREATE INDEX ON "approval_requests" ("status");
+
+CREATE UNIQUE INDEX ON "approval_decisions" ("request_id", "approver");
+
+COMMENT ON COLUMN "approval_policies"."threshold" IS 'Transfers above this amount need approval';
+
+COMMENT ON COLUMN "approval_requests"."requested_by" IS 'Maker of the transfer, who may not approve it';
+
+COMMENT ON COLUMN "approval_requests"."status" IS 'pending, approved or rejected';
+
+ALTER TABLE "approval_policies" ADD CHECK ("threshold" >= 0);
+
+ALTER TABLE "approval_policies" ADD CHECK ("required_approvals" > 0);
+
+ALTER TABLE "approval_requests" ADD CHECK ("status" IN ('pending', 'approved', 'rejected'));
+
+ALTER TABLE "approval_policies" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "approval_requests" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "approval_requests" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "approval_requests" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "approval_decisions" ADD FOREIGN KEY ("request_id") REFERENCES "approval_requests" ("id");
@@ -0,0 +1,24 @@
+CREATE TABLE "account_members" (
+  "account_id" bigint NOT NULL,
+  "username" varchar NOT NULL,
+  "role" varchar NOT NULL,
+  "transfer_limit" bigint NOT NULL DEFAULT 0,
+  "created_at" timestamp DEFAULT (now()),
+  PRIMARY KEY ("account_id", "username")
+);
+
+CREATE INDEX ON "account_members" ("username");
+
+COMMENT ON COLUMN "account_members"."role" IS 'owner, co_owner, viewer or limited';
+
+COMMENT ON COLUMN "account_members"."transfer_limit" IS 'Largest single transfer a limited member may make';
+
+ALTER TABLE "account_members" ADD CHECK ("role" IN ('owner', 'co_owner', 'viewer', 'limited'));
+
+ALTER TABLE "account_members" ADD CHECK ("transfer_limit" >= 0);
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+-- every existing account keeps its single owner as an owner member
+INSERT INTO "account_members" ("account_id", "username", "role")
+SELECT "id", "owner", 'owner' FROM "accounts";
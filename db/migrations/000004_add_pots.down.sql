@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS round_up_rules;
+ALTER TABLE accounts DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,22 @@
+ALTER TABLE "accounts" ADD COLUMN "parent_id" bigint;
+
+CREATE TABLE "round_up_rules" (
+  "account_id" bigint PRIMARY KEY,
+  "pot_id" bigint NOT NULL,
+  "round_to" bigint NOT NULL,
+  "created_at" timestamp DEFAULT (now())
+);
+
+CREATE INDEX ON "accounts" ("parent_id");
+
+COMMENT ON COLUMN "accounts"."parent_id" IS 'Set for savings pots, points to the parent account';
+
+COMMENT ON COLUMN "round_up_rules"."round_to" IS 'Outgoing transfers are rounded up to a multiple of this amount';
+
+ALTER TABLE "round_up_rules" ADD CHECK ("round_to" > 1);
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("parent_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "round_up_rules" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "round_up_rules" ADD FOREIGN KEY ("pot_id") REFERENCES "accounts" ("id");
@@ -0,0 +1,14 @@
+CREATE TABLE "beneficiaries" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "nickname" varchar NOT NULL,
+  "account_id" bigint NOT NULL,
+  "verified" boolean NOT NULL DEFAULT false,
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "beneficiaries" ("owner", "account_id");
+
+COMMENT ON COLUMN "beneficiaries"."verified" IS 'An operator confirmed the payee with gobank beneficiary verify';
+
+ALTER TABLE "beneficiaries" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
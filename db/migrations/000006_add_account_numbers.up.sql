@@ -0,0 +1,35 @@
+ALTER TABLE "accounts" ADD COLUMN "account_number" varchar;
+
+-- ISO 7064 mod-97 remainder, letters count as A=10 to Z=35
+CREATE FUNCTION pg_temp.mod97(s text) RETURNS int AS $$
+DECLARE
+  remainder int := 0;
+  c text;
+BEGIN
+  FOREACH c IN ARRAY regexp_split_to_array(s, '') LOOP
+    IF c ~ '[A-Z]' THEN
+      remainder := (remainder * 100 + ascii(c) - 55) % 97;
+    ELSE
+      remainder := (remainder * 10 + c::int) % 97;
+    END IF;
+  END LOOP;
+  RETURN remainder;
+END;
+$$ LANGUAGE plpgsql;
+
+-- existing accounts get a number with the default country and bank code.
+-- The digits are the id times an odd multiplier not divisible by 5, modulo
+-- 10^12: distinct ids give distinct numbers, the same on every run, without
+-- numbering the accounts in order.
+UPDATE "accounts" SET "account_number" = bban.country || lpad((98 - pg_temp.mod97(bban.bban || bban.country || '00'))::text, 2, '0') || bban.bban
+FROM (
+  SELECT "id", 'GB' AS country, 'GOBK' || lpad(("id" * 738219457 % 1000000000000)::text, 12, '0') AS bban
+  FROM "accounts"
+) AS bban
+WHERE "accounts"."id" = bban."id";
+
+ALTER TABLE "accounts" ALTER COLUMN "account_number" SET NOT NULL;
+
+CREATE UNIQUE INDEX ON "accounts" ("account_number");
+
+COMMENT ON COLUMN "accounts"."account_number" IS 'IBAN-style external identifier';
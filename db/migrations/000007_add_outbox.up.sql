@@ -0,0 +1,22 @@
+CREATE TABLE "outbox" (
+  "id" bigserial PRIMARY KEY,
+  "txid" bigint NOT NULL DEFAULT (txid_current()),
+  "aggregate_type" varchar NOT NULL,
+  "aggregate_id" bigint NOT NULL,
+  "event_type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "outbox_offsets" (
+  "consumer" varchar PRIMARY KEY,
+  "txid" bigint NOT NULL,
+  "event_id" bigint NOT NULL,
+  "updated_at" timestamp NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "outbox" ("txid", "id");
+
+COMMENT ON COLUMN "outbox"."txid" IS 'Transaction that wrote the event, events are relayed in (txid, id) order';
+
+COMMENT ON COLUMN "outbox_offsets"."event_id" IS 'Last event published to the consumer';
@@ -0,0 +1,38 @@
+CREATE TABLE "webhooks" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "url" varchar NOT NULL,
+  "event_types" varchar[] NOT NULL,
+  "secret" varchar NOT NULL,
+  "disabled" boolean NOT NULL DEFAULT false,
+  "consecutive_failures" int NOT NULL DEFAULT 0,
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "webhook_deliveries" (
+  "id" bigserial PRIMARY KEY,
+  "webhook_id" bigint NOT NULL,
+  "event_id" bigint NOT NULL,
+  "event_type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "attempts" int NOT NULL DEFAULT 0,
+  "next_attempt_at" timestamp NOT NULL DEFAULT (now()),
+  "last_error" varchar,
+  "delivered_at" timestamp,
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "webhooks" ("owner");
+
+CREATE UNIQUE INDEX ON "webhook_deliveries" ("webhook_id", "event_id");
+
+CREATE INDEX ON "webhook_deliveries" ("status", "next_attempt_at");
+
+COMMENT ON COLUMN "webhooks"."secret" IS 'Key of the HMAC-SHA256 signature sent with every delivery';
+
+COMMENT ON COLUMN "webhooks"."consecutive_failures" IS 'Failed attempts since the last successful delivery';
+
+COMMENT ON COLUMN "webhook_deliveries"."status" IS 'pending, delivered or failed';
+
+ALTER TABLE "webhook_deliveries" ADD FOREIGN KEY ("webhook_id") REFERENCES "webhooks" ("id") ON DELETE CASCADE;
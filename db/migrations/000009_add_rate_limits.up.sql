@@ -0,0 +1,12 @@
+CREATE TABLE "rate_limit_buckets" (
+  "key" varchar PRIMARY KEY,
+  "tokens" double precision NOT NULL,
+  "allowed" boolean NOT NULL,
+  "updated_at" timestamp NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "rate_limit_buckets" ("updated_at");
+
+COMMENT ON COLUMN "rate_limit_buckets"."tokens" IS 'Tokens left in the bucket at updated_at';
+
+COMMENT ON COLUMN "rate_limit_buckets"."allowed" IS 'Whether the last request took a token';
@@ -0,0 +1,8 @@
+CREATE TABLE "users" (
+  "username" varchar PRIMARY KEY,
+  "full_name" varchar NOT NULL,
+  "email" varchar UNIQUE NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+COMMENT ON TABLE "users" IS 'Account owners and members are referenced by username';
@@ -0,0 +1,9 @@
+CREATE TABLE "account_freezes" (
+  "account_id" bigint PRIMARY KEY,
+  "reason" varchar NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+COMMENT ON TABLE "account_freezes" IS 'Frozen accounts can neither send nor receive money';
+
+ALTER TABLE "account_freezes" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
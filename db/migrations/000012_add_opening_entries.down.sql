@@ -0,0 +1,3 @@
+DELETE FROM entries WHERE opening;
+
+ALTER TABLE entries DROP COLUMN IF EXISTS opening;
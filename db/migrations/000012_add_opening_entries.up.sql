@@ -0,0 +1,11 @@
+ALTER TABLE "entries" ADD COLUMN "opening" boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN "entries"."opening" IS 'Opening entries book the balance an account was created with';
+
+-- book the balances that accounts were created with before opening entries
+INSERT INTO "entries" ("account_id", "amount", "opening")
+SELECT a.id, a.balance - COALESCE(SUM(e.amount), 0), true
+FROM "accounts" a
+LEFT JOIN "entries" e ON e.account_id = a.id
+GROUP BY a.id
+HAVING a.balance <> COALESCE(SUM(e.amount), 0);
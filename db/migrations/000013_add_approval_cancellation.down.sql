@@ -0,0 +1,6 @@
+UPDATE approval_requests SET status = 'rejected' WHERE status = 'cancelled';
+
+ALTER TABLE approval_requests DROP CONSTRAINT IF EXISTS approval_requests_status_check;
+ALTER TABLE approval_requests ADD CHECK (status IN ('pending', 'approved', 'rejected'));
+
+COMMENT ON COLUMN approval_requests.status IS 'pending, approved or rejected';
@@ -0,0 +1,5 @@
+ALTER TABLE "approval_requests" DROP CONSTRAINT "approval_requests_status_check";
+
+ALTER TABLE "approval_requests" ADD CHECK ("status" IN ('pending', 'approved', 'rejected', 'cancelled'));
+
+COMMENT ON COLUMN "approval_requests"."status" IS 'pending, approved, rejected or cancelled';
@@ -0,0 +1,91 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/pgx/v5" // pgx5:// databases
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5"
+)
+
+// lockID is the Postgres advisory lock held while migrating on start, so
+// that of several instances starting together only one applies migrations.
+const lockID int64 = 0x676f5f62616e6b // "go_bank"
+
+// ErrNoChange is returned by Migrator methods that had nothing to apply.
+var ErrNoChange = migrate.ErrNoChange
+
+// ErrNilVersion is returned by Version before any migration was applied.
+var ErrNilVersion = migrate.ErrNilVersion
+
+// Migrator applies the embedded migrations to a database.
+type Migrator = migrate.Migrate
+
+// New returns a Migrator applying FS to the database at dsn, a postgres:// or
+// postgresql:// URL as used for DB_SOURCE.
+func New(dsn string) (*Migrator, error) {
+	source, err := iofs.New(FS, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := migrate.NewWithSourceInstance("iofs", source, databaseURL(dsn))
+	if err != nil {
+		return nil, fmt.Errorf("cannot open database: %w", err)
+	}
+	m.Log = logger{}
+	return m, nil
+}
+
+// databaseURL turns dsn into a URL of the pgx driver of golang-migrate.
+func databaseURL(dsn string) string {
+	for _, scheme := range []string{"postgresql://", "postgres://"} {
+		if rest, ok := strings.CutPrefix(dsn, scheme); ok {
+			return "pgx5://" + rest
+		}
+	}
+	return dsn
+}
+
+// Up applies all pending migrations to the database at dsn while holding an
+// advisory lock. Instances starting at the same time wait for the lock and
+// then find nothing left to apply.
+func Up(ctx context.Context, dsn string) error {
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return fmt.Errorf("cannot connect to db: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", lockID); err != nil {
+		return fmt.Errorf("cannot take migration lock: %w", err)
+	}
+	defer conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", lockID)
+
+	m, err := New(dsn)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	if err := m.Up(); err != nil && !errors.Is(err, ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// logger reports the progress of golang-migrate through the log package.
+type logger struct{}
+
+func (logger) Printf(format string, v ...any) {
+	log.Printf("migrate: "+strings.TrimSuffix(format, "\n"), v...)
+}
+
+func (logger) Verbose() bool {
+	return true
+}
@@ -0,0 +1,10 @@
+// Package migrations embeds the database migrations so that the binary knows
+// which schema version it expects.
+package migrations
+
+import "embed"
+
+// FS holds the golang-migrate up and down files.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	pgtype "github.com/jackc/pgx/v5/pgtype"
 	db "github.com/singhJasvinder101/go_bank/db/sqlc"
 )
 
@@ -35,6 +36,51 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// ApprovalDecisionTx mocks base method.
+func (m *MockStore) ApprovalDecisionTx(arg0 context.Context, arg1 db.ApprovalDecisionTxParams) (db.ApprovalDecisionTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApprovalDecisionTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ApprovalDecisionTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApprovalDecisionTx indicates an expected call of ApprovalDecisionTx.
+func (mr *MockStoreMockRecorder) ApprovalDecisionTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApprovalDecisionTx", reflect.TypeOf((*MockStore)(nil).ApprovalDecisionTx), arg0, arg1)
+}
+
+// CancelApprovalRequest mocks base method.
+func (m *MockStore) CancelApprovalRequest(arg0 context.Context, arg1 int64) (db.ApprovalRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelApprovalRequest", arg0, arg1)
+	ret0, _ := ret[0].(db.ApprovalRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelApprovalRequest indicates an expected call of CancelApprovalRequest.
+func (mr *MockStoreMockRecorder) CancelApprovalRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelApprovalRequest", reflect.TypeOf((*MockStore)(nil).CancelApprovalRequest), arg0, arg1)
+}
+
+// ClaimDueWebhookDeliveries mocks base method.
+func (m *MockStore) ClaimDueWebhookDeliveries(arg0 context.Context, arg1 db.ClaimDueWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueWebhookDeliveries indicates an expected call of ClaimDueWebhookDeliveries.
+func (mr *MockStoreMockRecorder) ClaimDueWebhookDeliveries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).ClaimDueWebhookDeliveries), arg0, arg1)
+}
+
 // CreateAccount mocks base method.
 func (m *MockStore) CreateAccount(arg0 context.Context, arg1 db.CreateAccountParams) (db.Account, error) {
 	m.ctrl.T.Helper()
@@ -50,6 +96,86 @@ func (mr *MockStoreMockRecorder) CreateAccount(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockStore)(nil).CreateAccount), arg0, arg1)
 }
 
+// CreateAccountMember mocks base method.
+func (m *MockStore) CreateAccountMember(arg0 context.Context, arg1 db.CreateAccountMemberParams) (db.AccountMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccountMember", arg0, arg1)
+	ret0, _ := ret[0].(db.AccountMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccountMember indicates an expected call of CreateAccountMember.
+func (mr *MockStoreMockRecorder) CreateAccountMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccountMember", reflect.TypeOf((*MockStore)(nil).CreateAccountMember), arg0, arg1)
+}
+
+// CreateAccountTx mocks base method.
+func (m *MockStore) CreateAccountTx(arg0 context.Context, arg1 db.CreateAccountParams, arg2 ...db.CreateAccountOption) (db.CreateAccountTxResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateAccountTx", varargs...)
+	ret0, _ := ret[0].(db.CreateAccountTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccountTx indicates an expected call of CreateAccountTx.
+func (mr *MockStoreMockRecorder) CreateAccountTx(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccountTx", reflect.TypeOf((*MockStore)(nil).CreateAccountTx), varargs...)
+}
+
+// CreateApprovalDecision mocks base method.
+func (m *MockStore) CreateApprovalDecision(arg0 context.Context, arg1 db.CreateApprovalDecisionParams) (db.ApprovalDecision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateApprovalDecision", arg0, arg1)
+	ret0, _ := ret[0].(db.ApprovalDecision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateApprovalDecision indicates an expected call of CreateApprovalDecision.
+func (mr *MockStoreMockRecorder) CreateApprovalDecision(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApprovalDecision", reflect.TypeOf((*MockStore)(nil).CreateApprovalDecision), arg0, arg1)
+}
+
+// CreateApprovalRequest mocks base method.
+func (m *MockStore) CreateApprovalRequest(arg0 context.Context, arg1 db.CreateApprovalRequestParams) (db.ApprovalRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateApprovalRequest", arg0, arg1)
+	ret0, _ := ret[0].(db.ApprovalRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateApprovalRequest indicates an expected call of CreateApprovalRequest.
+func (mr *MockStoreMockRecorder) CreateApprovalRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApprovalRequest", reflect.TypeOf((*MockStore)(nil).CreateApprovalRequest), arg0, arg1)
+}
+
+// CreateBeneficiary mocks base method.
+func (m *MockStore) CreateBeneficiary(arg0 context.Context, arg1 db.CreateBeneficiaryParams) (db.Beneficiary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBeneficiary", arg0, arg1)
+	ret0, _ := ret[0].(db.Beneficiary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBeneficiary indicates an expected call of CreateBeneficiary.
+func (mr *MockStoreMockRecorder) CreateBeneficiary(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBeneficiary", reflect.TypeOf((*MockStore)(nil).CreateBeneficiary), arg0, arg1)
+}
+
 // CreateEntry mocks base method.
 func (m *MockStore) CreateEntry(arg0 context.Context, arg1 db.CreateEntryParams) (db.Entry, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +191,36 @@ func (mr *MockStoreMockRecorder) CreateEntry(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntry", reflect.TypeOf((*MockStore)(nil).CreateEntry), arg0, arg1)
 }
 
+// CreateOpeningEntry mocks base method.
+func (m *MockStore) CreateOpeningEntry(arg0 context.Context, arg1 db.CreateOpeningEntryParams) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOpeningEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOpeningEntry indicates an expected call of CreateOpeningEntry.
+func (mr *MockStoreMockRecorder) CreateOpeningEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOpeningEntry", reflect.TypeOf((*MockStore)(nil).CreateOpeningEntry), arg0, arg1)
+}
+
+// CreateOutboxEvent mocks base method.
+func (m *MockStore) CreateOutboxEvent(arg0 context.Context, arg1 db.CreateOutboxEventParams) (db.Outbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOutboxEvent", arg0, arg1)
+	ret0, _ := ret[0].(db.Outbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOutboxEvent indicates an expected call of CreateOutboxEvent.
+func (mr *MockStoreMockRecorder) CreateOutboxEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOutboxEvent", reflect.TypeOf((*MockStore)(nil).CreateOutboxEvent), arg0, arg1)
+}
+
 // CreateTransfer mocks base method.
 func (m *MockStore) CreateTransfer(arg0 context.Context, arg1 db.CreateTransferParams) (db.Transfer, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +236,65 @@ func (mr *MockStoreMockRecorder) CreateTransfer(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockStore)(nil).CreateTransfer), arg0, arg1)
 }
 
+// CreateUser mocks base method.
+func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+}
+
+// CreateWebhook mocks base method.
+func (m *MockStore) CreateWebhook(arg0 context.Context, arg1 db.CreateWebhookParams) (db.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", arg0, arg1)
+	ret0, _ := ret[0].(db.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockStoreMockRecorder) CreateWebhook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockStore)(nil).CreateWebhook), arg0, arg1)
+}
+
+// CreateWebhookDelivery mocks base method.
+func (m *MockStore) CreateWebhookDelivery(arg0 context.Context, arg1 db.CreateWebhookDeliveryParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWebhookDelivery indicates an expected call of CreateWebhookDelivery.
+func (mr *MockStoreMockRecorder) CreateWebhookDelivery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookDelivery", reflect.TypeOf((*MockStore)(nil).CreateWebhookDelivery), arg0, arg1)
+}
+
+// DebitAccountBalanceByID mocks base method.
+func (m *MockStore) DebitAccountBalanceByID(arg0 context.Context, arg1 db.DebitAccountBalanceByIDParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DebitAccountBalanceByID", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DebitAccountBalanceByID indicates an expected call of DebitAccountBalanceByID.
+func (mr *MockStoreMockRecorder) DebitAccountBalanceByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DebitAccountBalanceByID", reflect.TypeOf((*MockStore)(nil).DebitAccountBalanceByID), arg0, arg1)
+}
+
 // DeleteAccountByID mocks base method.
 func (m *MockStore) DeleteAccountByID(arg0 context.Context, arg1 int64) error {
 	m.ctrl.T.Helper()
@@ -94,6 +309,120 @@ func (mr *MockStoreMockRecorder) DeleteAccountByID(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccountByID", reflect.TypeOf((*MockStore)(nil).DeleteAccountByID), arg0, arg1)
 }
 
+// DeleteAccountMember mocks base method.
+func (m *MockStore) DeleteAccountMember(arg0 context.Context, arg1 db.DeleteAccountMemberParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccountMember", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccountMember indicates an expected call of DeleteAccountMember.
+func (mr *MockStoreMockRecorder) DeleteAccountMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccountMember", reflect.TypeOf((*MockStore)(nil).DeleteAccountMember), arg0, arg1)
+}
+
+// DeleteApprovalPolicy mocks base method.
+func (m *MockStore) DeleteApprovalPolicy(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteApprovalPolicy", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteApprovalPolicy indicates an expected call of DeleteApprovalPolicy.
+func (mr *MockStoreMockRecorder) DeleteApprovalPolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteApprovalPolicy", reflect.TypeOf((*MockStore)(nil).DeleteApprovalPolicy), arg0, arg1)
+}
+
+// DeleteBeneficiary mocks base method.
+func (m *MockStore) DeleteBeneficiary(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBeneficiary", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBeneficiary indicates an expected call of DeleteBeneficiary.
+func (mr *MockStoreMockRecorder) DeleteBeneficiary(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBeneficiary", reflect.TypeOf((*MockStore)(nil).DeleteBeneficiary), arg0, arg1)
+}
+
+// DeleteIdleRateLimitBuckets mocks base method.
+func (m *MockStore) DeleteIdleRateLimitBuckets(arg0 context.Context, arg1 int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIdleRateLimitBuckets", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIdleRateLimitBuckets indicates an expected call of DeleteIdleRateLimitBuckets.
+func (mr *MockStoreMockRecorder) DeleteIdleRateLimitBuckets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdleRateLimitBuckets", reflect.TypeOf((*MockStore)(nil).DeleteIdleRateLimitBuckets), arg0, arg1)
+}
+
+// DeleteRoundUpRule mocks base method.
+func (m *MockStore) DeleteRoundUpRule(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRoundUpRule", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRoundUpRule indicates an expected call of DeleteRoundUpRule.
+func (mr *MockStoreMockRecorder) DeleteRoundUpRule(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRoundUpRule", reflect.TypeOf((*MockStore)(nil).DeleteRoundUpRule), arg0, arg1)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockStore) DeleteWebhook(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockStoreMockRecorder) DeleteWebhook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockStore)(nil).DeleteWebhook), arg0, arg1)
+}
+
+// EnableWebhook mocks base method.
+func (m *MockStore) EnableWebhook(arg0 context.Context, arg1 int64) (db.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableWebhook", arg0, arg1)
+	ret0, _ := ret[0].(db.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableWebhook indicates an expected call of EnableWebhook.
+func (mr *MockStoreMockRecorder) EnableWebhook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableWebhook", reflect.TypeOf((*MockStore)(nil).EnableWebhook), arg0, arg1)
+}
+
+// FreezeAccount mocks base method.
+func (m *MockStore) FreezeAccount(arg0 context.Context, arg1 db.FreezeAccountParams) (db.AccountFreeze, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FreezeAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.AccountFreeze)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FreezeAccount indicates an expected call of FreezeAccount.
+func (mr *MockStoreMockRecorder) FreezeAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FreezeAccount", reflect.TypeOf((*MockStore)(nil).FreezeAccount), arg0, arg1)
+}
+
 // GetAccountById mocks base method.
 func (m *MockStore) GetAccountById(arg0 context.Context, arg1 int64) (db.Account, error) {
 	m.ctrl.T.Helper()
@@ -109,6 +438,21 @@ func (mr *MockStoreMockRecorder) GetAccountById(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountById", reflect.TypeOf((*MockStore)(nil).GetAccountById), arg0, arg1)
 }
 
+// GetAccountByNumber mocks base method.
+func (m *MockStore) GetAccountByNumber(arg0 context.Context, arg1 string) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountByNumber", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountByNumber indicates an expected call of GetAccountByNumber.
+func (mr *MockStoreMockRecorder) GetAccountByNumber(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByNumber", reflect.TypeOf((*MockStore)(nil).GetAccountByNumber), arg0, arg1)
+}
+
 // GetAccountForUpdate mocks base method.
 func (m *MockStore) GetAccountForUpdate(arg0 context.Context, arg1 int64) (db.Account, error) {
 	m.ctrl.T.Helper()
@@ -124,122 +468,745 @@ func (mr *MockStoreMockRecorder) GetAccountForUpdate(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountForUpdate", reflect.TypeOf((*MockStore)(nil).GetAccountForUpdate), arg0, arg1)
 }
 
-// GetEntry mocks base method.
-func (m *MockStore) GetEntry(arg0 context.Context, arg1 int64) (db.Entry, error) {
+// GetAccountMember mocks base method.
+func (m *MockStore) GetAccountMember(arg0 context.Context, arg1 db.GetAccountMemberParams) (db.AccountMember, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEntry", arg0, arg1)
-	ret0, _ := ret[0].(db.Entry)
+	ret := m.ctrl.Call(m, "GetAccountMember", arg0, arg1)
+	ret0, _ := ret[0].(db.AccountMember)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetEntry indicates an expected call of GetEntry.
-func (mr *MockStoreMockRecorder) GetEntry(arg0, arg1 interface{}) *gomock.Call {
+// GetAccountMember indicates an expected call of GetAccountMember.
+func (mr *MockStoreMockRecorder) GetAccountMember(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockStore)(nil).GetEntry), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountMember", reflect.TypeOf((*MockStore)(nil).GetAccountMember), arg0, arg1)
 }
 
-// GetTransfer mocks base method.
-func (m *MockStore) GetTransfer(arg0 context.Context, arg1 int64) (db.Transfer, error) {
+// GetApprovalPolicy mocks base method.
+func (m *MockStore) GetApprovalPolicy(arg0 context.Context, arg1 int64) (db.ApprovalPolicy, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransfer", arg0, arg1)
-	ret0, _ := ret[0].(db.Transfer)
+	ret := m.ctrl.Call(m, "GetApprovalPolicy", arg0, arg1)
+	ret0, _ := ret[0].(db.ApprovalPolicy)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTransfer indicates an expected call of GetTransfer.
-func (mr *MockStoreMockRecorder) GetTransfer(arg0, arg1 interface{}) *gomock.Call {
+// GetApprovalPolicy indicates an expected call of GetApprovalPolicy.
+func (mr *MockStoreMockRecorder) GetApprovalPolicy(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockStore)(nil).GetTransfer), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApprovalPolicy", reflect.TypeOf((*MockStore)(nil).GetApprovalPolicy), arg0, arg1)
 }
 
-// ListAccounts mocks base method.
-func (m *MockStore) ListAccounts(arg0 context.Context, arg1 db.ListAccountsParams) ([]db.Account, error) {
+// GetApprovalRequest mocks base method.
+func (m *MockStore) GetApprovalRequest(arg0 context.Context, arg1 int64) (db.ApprovalRequest, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListAccounts", arg0, arg1)
-	ret0, _ := ret[0].([]db.Account)
+	ret := m.ctrl.Call(m, "GetApprovalRequest", arg0, arg1)
+	ret0, _ := ret[0].(db.ApprovalRequest)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListAccounts indicates an expected call of ListAccounts.
-func (mr *MockStoreMockRecorder) ListAccounts(arg0, arg1 interface{}) *gomock.Call {
+// GetApprovalRequest indicates an expected call of GetApprovalRequest.
+func (mr *MockStoreMockRecorder) GetApprovalRequest(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockStore)(nil).ListAccounts), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApprovalRequest", reflect.TypeOf((*MockStore)(nil).GetApprovalRequest), arg0, arg1)
 }
 
-// ListEntries mocks base method.
-func (m *MockStore) ListEntries(arg0 context.Context, arg1 db.ListEntriesParams) ([]db.Entry, error) {
+// GetApprovalRequestForUpdate mocks base method.
+func (m *MockStore) GetApprovalRequestForUpdate(arg0 context.Context, arg1 int64) (db.ApprovalRequest, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListEntries", arg0, arg1)
-	ret0, _ := ret[0].([]db.Entry)
+	ret := m.ctrl.Call(m, "GetApprovalRequestForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.ApprovalRequest)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListEntries indicates an expected call of ListEntries.
-func (mr *MockStoreMockRecorder) ListEntries(arg0, arg1 interface{}) *gomock.Call {
+// GetApprovalRequestForUpdate indicates an expected call of GetApprovalRequestForUpdate.
+func (mr *MockStoreMockRecorder) GetApprovalRequestForUpdate(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockStore)(nil).ListEntries), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApprovalRequestForUpdate", reflect.TypeOf((*MockStore)(nil).GetApprovalRequestForUpdate), arg0, arg1)
 }
 
-// ListTransfers mocks base method.
-func (m *MockStore) ListTransfers(arg0 context.Context, arg1 db.ListTransfersParams) ([]db.Transfer, error) {
+// GetBeneficiary mocks base method.
+func (m *MockStore) GetBeneficiary(arg0 context.Context, arg1 int64) (db.Beneficiary, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListTransfers", arg0, arg1)
-	ret0, _ := ret[0].([]db.Transfer)
+	ret := m.ctrl.Call(m, "GetBeneficiary", arg0, arg1)
+	ret0, _ := ret[0].(db.Beneficiary)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListTransfers indicates an expected call of ListTransfers.
-func (mr *MockStoreMockRecorder) ListTransfers(arg0, arg1 interface{}) *gomock.Call {
+// GetBeneficiary indicates an expected call of GetBeneficiary.
+func (mr *MockStoreMockRecorder) GetBeneficiary(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfers", reflect.TypeOf((*MockStore)(nil).ListTransfers), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBeneficiary", reflect.TypeOf((*MockStore)(nil).GetBeneficiary), arg0, arg1)
 }
 
-// TransferTx mocks base method.
-func (m *MockStore) TransferTx(arg0 context.Context, arg1 db.TransferTxParams) (db.TransferTxResult, error) {
+// GetEntry mocks base method.
+func (m *MockStore) GetEntry(arg0 context.Context, arg1 int64) (db.Entry, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "TransferTx", arg0, arg1)
-	ret0, _ := ret[0].(db.TransferTxResult)
+	ret := m.ctrl.Call(m, "GetEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// TransferTx indicates an expected call of TransferTx.
-func (mr *MockStoreMockRecorder) TransferTx(arg0, arg1 interface{}) *gomock.Call {
+// GetEntry indicates an expected call of GetEntry.
+func (mr *MockStoreMockRecorder) GetEntry(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferTx", reflect.TypeOf((*MockStore)(nil).TransferTx), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockStore)(nil).GetEntry), arg0, arg1)
 }
 
-// UpdateAccountBalanceByID mocks base method.
-func (m *MockStore) UpdateAccountBalanceByID(arg0 context.Context, arg1 db.UpdateAccountBalanceByIDParams) (db.Account, error) {
+// GetLedgerTotals mocks base method.
+func (m *MockStore) GetLedgerTotals(arg0 context.Context) (db.GetLedgerTotalsRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateAccountBalanceByID", arg0, arg1)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "GetLedgerTotals", arg0)
+	ret0, _ := ret[0].(db.GetLedgerTotalsRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateAccountBalanceByID indicates an expected call of UpdateAccountBalanceByID.
-func (mr *MockStoreMockRecorder) UpdateAccountBalanceByID(arg0, arg1 interface{}) *gomock.Call {
+// GetLedgerTotals indicates an expected call of GetLedgerTotals.
+func (mr *MockStoreMockRecorder) GetLedgerTotals(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountBalanceByID", reflect.TypeOf((*MockStore)(nil).UpdateAccountBalanceByID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLedgerTotals", reflect.TypeOf((*MockStore)(nil).GetLedgerTotals), arg0)
 }
 
-// UpdateAccountByID mocks base method.
-func (m *MockStore) UpdateAccountByID(arg0 context.Context, arg1 db.UpdateAccountByIDParams) (db.Account, error) {
+// GetOutboxOffset mocks base method.
+func (m *MockStore) GetOutboxOffset(arg0 context.Context, arg1 string) (db.OutboxOffset, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateAccountByID", arg0, arg1)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "GetOutboxOffset", arg0, arg1)
+	ret0, _ := ret[0].(db.OutboxOffset)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateAccountByID indicates an expected call of UpdateAccountByID.
-func (mr *MockStoreMockRecorder) UpdateAccountByID(arg0, arg1 interface{}) *gomock.Call {
+// GetOutboxOffset indicates an expected call of GetOutboxOffset.
+func (mr *MockStoreMockRecorder) GetOutboxOffset(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountByID", reflect.TypeOf((*MockStore)(nil).UpdateAccountByID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutboxOffset", reflect.TypeOf((*MockStore)(nil).GetOutboxOffset), arg0, arg1)
+}
+
+// GetRoundUpRule mocks base method.
+func (m *MockStore) GetRoundUpRule(arg0 context.Context, arg1 int64) (db.RoundUpRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoundUpRule", arg0, arg1)
+	ret0, _ := ret[0].(db.RoundUpRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoundUpRule indicates an expected call of GetRoundUpRule.
+func (mr *MockStoreMockRecorder) GetRoundUpRule(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoundUpRule", reflect.TypeOf((*MockStore)(nil).GetRoundUpRule), arg0, arg1)
+}
+
+// GetTransfer mocks base method.
+func (m *MockStore) GetTransfer(arg0 context.Context, arg1 int64) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransfer indicates an expected call of GetTransfer.
+func (mr *MockStoreMockRecorder) GetTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockStore)(nil).GetTransfer), arg0, arg1)
+}
+
+// GetUser mocks base method.
+func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+}
+
+// GetWebhook mocks base method.
+func (m *MockStore) GetWebhook(arg0 context.Context, arg1 int64) (db.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", arg0, arg1)
+	ret0, _ := ret[0].(db.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockStoreMockRecorder) GetWebhook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockStore)(nil).GetWebhook), arg0, arg1)
+}
+
+// GetWebhookDelivery mocks base method.
+func (m *MockStore) GetWebhookDelivery(arg0 context.Context, arg1 int64) (db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDelivery indicates an expected call of GetWebhookDelivery.
+func (mr *MockStoreMockRecorder) GetWebhookDelivery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDelivery", reflect.TypeOf((*MockStore)(nil).GetWebhookDelivery), arg0, arg1)
+}
+
+// ListAccountEntriesAfter mocks base method.
+func (m *MockStore) ListAccountEntriesAfter(arg0 context.Context, arg1 db.ListAccountEntriesAfterParams) ([]db.ListAccountEntriesAfterRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountEntriesAfter", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListAccountEntriesAfterRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountEntriesAfter indicates an expected call of ListAccountEntriesAfter.
+func (mr *MockStoreMockRecorder) ListAccountEntriesAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountEntriesAfter", reflect.TypeOf((*MockStore)(nil).ListAccountEntriesAfter), arg0, arg1)
+}
+
+// ListAccountFreezes mocks base method.
+func (m *MockStore) ListAccountFreezes(arg0 context.Context, arg1 []int64) ([]db.AccountFreeze, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountFreezes", arg0, arg1)
+	ret0, _ := ret[0].([]db.AccountFreeze)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountFreezes indicates an expected call of ListAccountFreezes.
+func (mr *MockStoreMockRecorder) ListAccountFreezes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountFreezes", reflect.TypeOf((*MockStore)(nil).ListAccountFreezes), arg0, arg1)
+}
+
+// ListAccountMembers mocks base method.
+func (m *MockStore) ListAccountMembers(arg0 context.Context, arg1 int64) ([]db.AccountMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountMembers", arg0, arg1)
+	ret0, _ := ret[0].([]db.AccountMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountMembers indicates an expected call of ListAccountMembers.
+func (mr *MockStoreMockRecorder) ListAccountMembers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountMembers", reflect.TypeOf((*MockStore)(nil).ListAccountMembers), arg0, arg1)
+}
+
+// ListAccountMembersForUpdate mocks base method.
+func (m *MockStore) ListAccountMembersForUpdate(arg0 context.Context, arg1 int64) ([]db.AccountMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountMembersForUpdate", arg0, arg1)
+	ret0, _ := ret[0].([]db.AccountMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountMembersForUpdate indicates an expected call of ListAccountMembersForUpdate.
+func (mr *MockStoreMockRecorder) ListAccountMembersForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountMembersForUpdate", reflect.TypeOf((*MockStore)(nil).ListAccountMembersForUpdate), arg0, arg1)
+}
+
+// ListAccountNumbers mocks base method.
+func (m *MockStore) ListAccountNumbers(arg0 context.Context, arg1 []int64) ([]db.ListAccountNumbersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountNumbers", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListAccountNumbersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountNumbers indicates an expected call of ListAccountNumbers.
+func (mr *MockStoreMockRecorder) ListAccountNumbers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountNumbers", reflect.TypeOf((*MockStore)(nil).ListAccountNumbers), arg0, arg1)
+}
+
+// ListAccountPots mocks base method.
+func (m *MockStore) ListAccountPots(arg0 context.Context, arg1 pgtype.Int8) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountPots", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountPots indicates an expected call of ListAccountPots.
+func (mr *MockStoreMockRecorder) ListAccountPots(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountPots", reflect.TypeOf((*MockStore)(nil).ListAccountPots), arg0, arg1)
+}
+
+// ListAccounts mocks base method.
+func (m *MockStore) ListAccounts(arg0 context.Context, arg1 db.ListAccountsParams) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccounts indicates an expected call of ListAccounts.
+func (mr *MockStoreMockRecorder) ListAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockStore)(nil).ListAccounts), arg0, arg1)
+}
+
+// ListApprovalDecisions mocks base method.
+func (m *MockStore) ListApprovalDecisions(arg0 context.Context, arg1 int64) ([]db.ApprovalDecision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListApprovalDecisions", arg0, arg1)
+	ret0, _ := ret[0].([]db.ApprovalDecision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListApprovalDecisions indicates an expected call of ListApprovalDecisions.
+func (mr *MockStoreMockRecorder) ListApprovalDecisions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApprovalDecisions", reflect.TypeOf((*MockStore)(nil).ListApprovalDecisions), arg0, arg1)
+}
+
+// ListBeneficiaries mocks base method.
+func (m *MockStore) ListBeneficiaries(arg0 context.Context, arg1 db.ListBeneficiariesParams) ([]db.Beneficiary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBeneficiaries", arg0, arg1)
+	ret0, _ := ret[0].([]db.Beneficiary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBeneficiaries indicates an expected call of ListBeneficiaries.
+func (mr *MockStoreMockRecorder) ListBeneficiaries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBeneficiaries", reflect.TypeOf((*MockStore)(nil).ListBeneficiaries), arg0, arg1)
+}
+
+// ListEntries mocks base method.
+func (m *MockStore) ListEntries(arg0 context.Context, arg1 db.ListEntriesParams) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntries indicates an expected call of ListEntries.
+func (mr *MockStoreMockRecorder) ListEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockStore)(nil).ListEntries), arg0, arg1)
+}
+
+// ListMemberAccounts mocks base method.
+func (m *MockStore) ListMemberAccounts(arg0 context.Context, arg1 db.ListMemberAccountsParams) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMemberAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMemberAccounts indicates an expected call of ListMemberAccounts.
+func (mr *MockStoreMockRecorder) ListMemberAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMemberAccounts", reflect.TypeOf((*MockStore)(nil).ListMemberAccounts), arg0, arg1)
+}
+
+// ListOutboxEvents mocks base method.
+func (m *MockStore) ListOutboxEvents(arg0 context.Context, arg1 db.ListOutboxEventsParams) ([]db.Outbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOutboxEvents", arg0, arg1)
+	ret0, _ := ret[0].([]db.Outbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOutboxEvents indicates an expected call of ListOutboxEvents.
+func (mr *MockStoreMockRecorder) ListOutboxEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOutboxEvents", reflect.TypeOf((*MockStore)(nil).ListOutboxEvents), arg0, arg1)
+}
+
+// ListTransfers mocks base method.
+func (m *MockStore) ListTransfers(arg0 context.Context, arg1 db.ListTransfersParams) ([]db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransfers indicates an expected call of ListTransfers.
+func (mr *MockStoreMockRecorder) ListTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfers", reflect.TypeOf((*MockStore)(nil).ListTransfers), arg0, arg1)
+}
+
+// ListUnbalancedAccounts mocks base method.
+func (m *MockStore) ListUnbalancedAccounts(arg0 context.Context, arg1 int32) ([]db.ListUnbalancedAccountsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnbalancedAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListUnbalancedAccountsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnbalancedAccounts indicates an expected call of ListUnbalancedAccounts.
+func (mr *MockStoreMockRecorder) ListUnbalancedAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnbalancedAccounts", reflect.TypeOf((*MockStore)(nil).ListUnbalancedAccounts), arg0, arg1)
+}
+
+// ListUsers mocks base method.
+func (m *MockStore) ListUsers(arg0 context.Context, arg1 db.ListUsersParams) ([]db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", arg0, arg1)
+	ret0, _ := ret[0].([]db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockStoreMockRecorder) ListUsers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockStore)(nil).ListUsers), arg0, arg1)
+}
+
+// ListWebhookDeliveries mocks base method.
+func (m *MockStore) ListWebhookDeliveries(arg0 context.Context, arg1 db.ListWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries.
+func (mr *MockStoreMockRecorder) ListWebhookDeliveries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).ListWebhookDeliveries), arg0, arg1)
+}
+
+// ListWebhooks mocks base method.
+func (m *MockStore) ListWebhooks(arg0 context.Context, arg1 db.ListWebhooksParams) ([]db.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks", arg0, arg1)
+	ret0, _ := ret[0].([]db.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockStoreMockRecorder) ListWebhooks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockStore)(nil).ListWebhooks), arg0, arg1)
+}
+
+// ListWebhooksForEvent mocks base method.
+func (m *MockStore) ListWebhooksForEvent(arg0 context.Context, arg1 db.ListWebhooksForEventParams) ([]db.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooksForEvent", arg0, arg1)
+	ret0, _ := ret[0].([]db.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooksForEvent indicates an expected call of ListWebhooksForEvent.
+func (mr *MockStoreMockRecorder) ListWebhooksForEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooksForEvent", reflect.TypeOf((*MockStore)(nil).ListWebhooksForEvent), arg0, arg1)
+}
+
+// MarkWebhookDeliveryDelivered mocks base method.
+func (m *MockStore) MarkWebhookDeliveryDelivered(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkWebhookDeliveryDelivered", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkWebhookDeliveryDelivered indicates an expected call of MarkWebhookDeliveryDelivered.
+func (mr *MockStoreMockRecorder) MarkWebhookDeliveryDelivered(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWebhookDeliveryDelivered", reflect.TypeOf((*MockStore)(nil).MarkWebhookDeliveryDelivered), arg0, arg1)
+}
+
+// MarkWebhookDeliveryFailed mocks base method.
+func (m *MockStore) MarkWebhookDeliveryFailed(arg0 context.Context, arg1 db.MarkWebhookDeliveryFailedParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkWebhookDeliveryFailed", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkWebhookDeliveryFailed indicates an expected call of MarkWebhookDeliveryFailed.
+func (mr *MockStoreMockRecorder) MarkWebhookDeliveryFailed(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWebhookDeliveryFailed", reflect.TypeOf((*MockStore)(nil).MarkWebhookDeliveryFailed), arg0, arg1)
+}
+
+// NotifyAccountUpdate mocks base method.
+func (m *MockStore) NotifyAccountUpdate(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NotifyAccountUpdate", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NotifyAccountUpdate indicates an expected call of NotifyAccountUpdate.
+func (mr *MockStoreMockRecorder) NotifyAccountUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyAccountUpdate", reflect.TypeOf((*MockStore)(nil).NotifyAccountUpdate), arg0, arg1)
+}
+
+// PotTransferTx mocks base method.
+func (m *MockStore) PotTransferTx(arg0 context.Context, arg1 db.TransferTxParams) (db.TransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PotTransferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PotTransferTx indicates an expected call of PotTransferTx.
+func (mr *MockStoreMockRecorder) PotTransferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PotTransferTx", reflect.TypeOf((*MockStore)(nil).PotTransferTx), arg0, arg1)
+}
+
+// RecordWebhookFailure mocks base method.
+func (m *MockStore) RecordWebhookFailure(arg0 context.Context, arg1 db.RecordWebhookFailureParams) (db.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordWebhookFailure", arg0, arg1)
+	ret0, _ := ret[0].(db.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordWebhookFailure indicates an expected call of RecordWebhookFailure.
+func (mr *MockStoreMockRecorder) RecordWebhookFailure(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordWebhookFailure", reflect.TypeOf((*MockStore)(nil).RecordWebhookFailure), arg0, arg1)
+}
+
+// RecordWebhookSuccess mocks base method.
+func (m *MockStore) RecordWebhookSuccess(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordWebhookSuccess", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordWebhookSuccess indicates an expected call of RecordWebhookSuccess.
+func (mr *MockStoreMockRecorder) RecordWebhookSuccess(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordWebhookSuccess", reflect.TypeOf((*MockStore)(nil).RecordWebhookSuccess), arg0, arg1)
+}
+
+// RemoveMemberTx mocks base method.
+func (m *MockStore) RemoveMemberTx(arg0 context.Context, arg1 db.DeleteAccountMemberParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMemberTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveMemberTx indicates an expected call of RemoveMemberTx.
+func (mr *MockStoreMockRecorder) RemoveMemberTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMemberTx", reflect.TypeOf((*MockStore)(nil).RemoveMemberTx), arg0, arg1)
+}
+
+// ReplayWebhookDelivery mocks base method.
+func (m *MockStore) ReplayWebhookDelivery(arg0 context.Context, arg1 int64) (db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplayWebhookDelivery indicates an expected call of ReplayWebhookDelivery.
+func (mr *MockStoreMockRecorder) ReplayWebhookDelivery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayWebhookDelivery", reflect.TypeOf((*MockStore)(nil).ReplayWebhookDelivery), arg0, arg1)
+}
+
+// TakeRateLimitToken mocks base method.
+func (m *MockStore) TakeRateLimitToken(arg0 context.Context, arg1 db.TakeRateLimitTokenParams) (db.RateLimitBucket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeRateLimitToken", arg0, arg1)
+	ret0, _ := ret[0].(db.RateLimitBucket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeRateLimitToken indicates an expected call of TakeRateLimitToken.
+func (mr *MockStoreMockRecorder) TakeRateLimitToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeRateLimitToken", reflect.TypeOf((*MockStore)(nil).TakeRateLimitToken), arg0, arg1)
+}
+
+// TransferTx mocks base method.
+func (m *MockStore) TransferTx(arg0 context.Context, arg1 db.TransferTxParams) (db.TransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransferTx indicates an expected call of TransferTx.
+func (mr *MockStoreMockRecorder) TransferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferTx", reflect.TypeOf((*MockStore)(nil).TransferTx), arg0, arg1)
+}
+
+// UnfreezeAccount mocks base method.
+func (m *MockStore) UnfreezeAccount(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnfreezeAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnfreezeAccount indicates an expected call of UnfreezeAccount.
+func (mr *MockStoreMockRecorder) UnfreezeAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnfreezeAccount", reflect.TypeOf((*MockStore)(nil).UnfreezeAccount), arg0, arg1)
+}
+
+// UpdateAccountBalanceByID mocks base method.
+func (m *MockStore) UpdateAccountBalanceByID(arg0 context.Context, arg1 db.UpdateAccountBalanceByIDParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountBalanceByID", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccountBalanceByID indicates an expected call of UpdateAccountBalanceByID.
+func (mr *MockStoreMockRecorder) UpdateAccountBalanceByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountBalanceByID", reflect.TypeOf((*MockStore)(nil).UpdateAccountBalanceByID), arg0, arg1)
+}
+
+// UpdateAccountByID mocks base method.
+func (m *MockStore) UpdateAccountByID(arg0 context.Context, arg1 db.UpdateAccountByIDParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountByID", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccountByID indicates an expected call of UpdateAccountByID.
+func (mr *MockStoreMockRecorder) UpdateAccountByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountByID", reflect.TypeOf((*MockStore)(nil).UpdateAccountByID), arg0, arg1)
+}
+
+// UpdateApprovalRequestStatus mocks base method.
+func (m *MockStore) UpdateApprovalRequestStatus(arg0 context.Context, arg1 db.UpdateApprovalRequestStatusParams) (db.ApprovalRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateApprovalRequestStatus", arg0, arg1)
+	ret0, _ := ret[0].(db.ApprovalRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateApprovalRequestStatus indicates an expected call of UpdateApprovalRequestStatus.
+func (mr *MockStoreMockRecorder) UpdateApprovalRequestStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApprovalRequestStatus", reflect.TypeOf((*MockStore)(nil).UpdateApprovalRequestStatus), arg0, arg1)
+}
+
+// UpdateBeneficiaryNickname mocks base method.
+func (m *MockStore) UpdateBeneficiaryNickname(arg0 context.Context, arg1 db.UpdateBeneficiaryNicknameParams) (db.Beneficiary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBeneficiaryNickname", arg0, arg1)
+	ret0, _ := ret[0].(db.Beneficiary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateBeneficiaryNickname indicates an expected call of UpdateBeneficiaryNickname.
+func (mr *MockStoreMockRecorder) UpdateBeneficiaryNickname(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBeneficiaryNickname", reflect.TypeOf((*MockStore)(nil).UpdateBeneficiaryNickname), arg0, arg1)
+}
+
+// UpsertApprovalPolicy mocks base method.
+func (m *MockStore) UpsertApprovalPolicy(arg0 context.Context, arg1 db.UpsertApprovalPolicyParams) (db.ApprovalPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertApprovalPolicy", arg0, arg1)
+	ret0, _ := ret[0].(db.ApprovalPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertApprovalPolicy indicates an expected call of UpsertApprovalPolicy.
+func (mr *MockStoreMockRecorder) UpsertApprovalPolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertApprovalPolicy", reflect.TypeOf((*MockStore)(nil).UpsertApprovalPolicy), arg0, arg1)
+}
+
+// UpsertOutboxOffset mocks base method.
+func (m *MockStore) UpsertOutboxOffset(arg0 context.Context, arg1 db.UpsertOutboxOffsetParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertOutboxOffset", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertOutboxOffset indicates an expected call of UpsertOutboxOffset.
+func (mr *MockStoreMockRecorder) UpsertOutboxOffset(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertOutboxOffset", reflect.TypeOf((*MockStore)(nil).UpsertOutboxOffset), arg0, arg1)
+}
+
+// UpsertRoundUpRule mocks base method.
+func (m *MockStore) UpsertRoundUpRule(arg0 context.Context, arg1 db.UpsertRoundUpRuleParams) (db.RoundUpRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertRoundUpRule", arg0, arg1)
+	ret0, _ := ret[0].(db.RoundUpRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertRoundUpRule indicates an expected call of UpsertRoundUpRule.
+func (mr *MockStoreMockRecorder) UpsertRoundUpRule(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertRoundUpRule", reflect.TypeOf((*MockStore)(nil).UpsertRoundUpRule), arg0, arg1)
+}
+
+// VerifyBeneficiary mocks base method.
+func (m *MockStore) VerifyBeneficiary(arg0 context.Context, arg1 int64) (db.Beneficiary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyBeneficiary", arg0, arg1)
+	ret0, _ := ret[0].(db.Beneficiary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyBeneficiary indicates an expected call of VerifyBeneficiary.
+func (mr *MockStoreMockRecorder) VerifyBeneficiary(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyBeneficiary", reflect.TypeOf((*MockStore)(nil).VerifyBeneficiary), arg0, arg1)
 }
@@ -1,19 +1,33 @@
 -- name: CreateAccount :one
 insert into accounts(
-    owner, balance, currency
+    owner, balance, currency, parent_id, account_number
 ) values (
-    $1, $2, $3
+    $1, $2, $3, $4, $5
 ) returning *;
 
 -- name: GetAccountById :one
 select * from accounts
 where id = $1 limit 1;
 
+-- name: GetAccountByNumber :one
+select * from accounts
+where account_number = $1 limit 1;
+
+-- name: ListAccountNumbers :many
+select id, account_number from accounts
+where id = any(sqlc.arg(ids)::bigint[])
+order by id;
+
 -- name: GetAccountForUpdate :one
 select * from accounts
 where id = $1 limit 1
 for no key update;
 
+-- name: ListAccountPots :many
+select * from accounts
+where parent_id = $1
+order by id;
+
 -- name: ListAccounts :many
 select * from accounts
 order by id
@@ -32,6 +46,12 @@ set balance = balance + sqlc.arg(amount)
 where id = sqlc.arg(account_id)
 RETURNING *;
 
+-- name: DebitAccountBalanceByID :one
+update accounts
+set balance = balance - sqlc.arg(amount)
+where id = sqlc.arg(account_id) and balance >= sqlc.arg(amount)
+RETURNING *;
+
 
 -- name: DeleteAccountByID :exec
 delete from accounts
@@ -0,0 +1,19 @@
+-- name: FreezeAccount :one
+INSERT INTO account_freezes (
+  account_id,
+  reason
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (account_id) DO UPDATE
+SET reason = EXCLUDED.reason
+RETURNING *;
+
+-- name: UnfreezeAccount :exec
+DELETE FROM account_freezes
+WHERE account_id = $1;
+
+-- name: ListAccountFreezes :many
+SELECT * FROM account_freezes
+WHERE account_id = ANY(sqlc.arg(account_ids)::bigint[])
+ORDER BY account_id;
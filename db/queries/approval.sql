@@ -0,0 +1,68 @@
+-- name: UpsertApprovalPolicy :one
+INSERT INTO approval_policies (
+  account_id,
+  threshold,
+  required_approvals,
+  approvers
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (account_id) DO UPDATE
+SET threshold = EXCLUDED.threshold,
+    required_approvals = EXCLUDED.required_approvals,
+    approvers = EXCLUDED.approvers
+RETURNING *;
+
+-- name: GetApprovalPolicy :one
+SELECT * FROM approval_policies
+WHERE account_id = $1 LIMIT 1;
+
+-- name: DeleteApprovalPolicy :exec
+DELETE FROM approval_policies
+WHERE account_id = $1;
+
+-- name: CreateApprovalRequest :one
+INSERT INTO approval_requests (
+  from_account_id,
+  to_account_id,
+  amount,
+  requested_by
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetApprovalRequest :one
+SELECT * FROM approval_requests
+WHERE id = $1 LIMIT 1;
+
+-- name: GetApprovalRequestForUpdate :one
+SELECT * FROM approval_requests
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: UpdateApprovalRequestStatus :one
+UPDATE approval_requests
+SET status = $2,
+    transfer_id = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: CancelApprovalRequest :one
+UPDATE approval_requests
+SET status = 'cancelled'
+WHERE id = $1 AND status = 'pending'
+RETURNING *;
+
+-- name: CreateApprovalDecision :one
+INSERT INTO approval_decisions (
+  request_id,
+  approver,
+  approved
+) VALUES (
+  $1, $2, $3
+) RETURNING *;
+
+-- name: ListApprovalDecisions :many
+SELECT * FROM approval_decisions
+WHERE request_id = $1
+ORDER BY id;
@@ -6,6 +6,16 @@ INSERT INTO entries (
   $1, $2
 ) RETURNING *;
 
+-- name: CreateOpeningEntry :one
+-- Books the balance an account was created with.
+INSERT INTO entries (
+  account_id,
+  amount,
+  opening
+) VALUES (
+  $1, $2, true
+) RETURNING *;
+
 -- name: GetEntry :one
 SELECT * FROM entries
 WHERE id = $1 LIMIT 1;
@@ -15,4 +25,16 @@ SELECT * FROM entries
 WHERE account_id = $1
 ORDER BY id
 LIMIT $2
-OFFSET $3;
\ No newline at end of file
+OFFSET $3;
+
+-- name: ListAccountEntriesAfter :many
+-- Entries of an account after the given entry, each with the balance the
+-- account had right after it.
+SELECT e.id, e.account_id, e.amount, e.created_at,
+  (a.balance - COALESCE(SUM(e.amount) OVER (
+    ORDER BY e.id DESC ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING
+  ), 0))::bigint AS balance
+FROM entries e
+JOIN accounts a ON a.id = e.account_id
+WHERE e.account_id = sqlc.arg(account_id) AND e.id > sqlc.arg(after_id)
+ORDER BY e.id;
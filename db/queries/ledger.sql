@@ -0,0 +1,26 @@
+-- name: GetLedgerTotals :one
+-- Totals of the whole ledger for reconciliation. Every transfer books two
+-- entries that cancel out and every account books its opening balance, so
+-- entry_total must equal opening_total, entry_count must be twice
+-- transfer_count plus opening_count and no account may be unbalanced.
+SELECT
+  (SELECT COUNT(*) FROM entries)::bigint AS entry_count,
+  (SELECT COALESCE(SUM(amount), 0) FROM entries)::bigint AS entry_total,
+  (SELECT COUNT(*) FROM entries WHERE opening)::bigint AS opening_count,
+  (SELECT COALESCE(SUM(amount), 0) FROM entries WHERE opening)::bigint AS opening_total,
+  (SELECT COUNT(*) FROM transfers)::bigint AS transfer_count,
+  (SELECT COALESCE(SUM(amount), 0) FROM transfers)::bigint AS transfer_total,
+  (SELECT COUNT(*) FROM accounts WHERE balance < 0)::bigint AS negative_accounts,
+  (SELECT COUNT(*) FROM accounts a WHERE a.balance <> (
+    SELECT COALESCE(SUM(e.amount), 0) FROM entries e WHERE e.account_id = a.id
+  ))::bigint AS unbalanced_accounts;
+
+-- name: ListUnbalancedAccounts :many
+-- Accounts whose balance differs from the sum of their entries.
+SELECT a.id, a.account_number, a.balance, COALESCE(SUM(e.amount), 0)::bigint AS entry_total
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id
+GROUP BY a.id
+HAVING a.balance <> COALESCE(SUM(e.amount), 0)
+ORDER BY a.id
+LIMIT $1;
@@ -0,0 +1,3 @@
+-- name: NotifyAccountUpdate :exec
+-- Notifies listeners of the account_updates channel once the transaction commits.
+SELECT pg_notify('account_updates', sqlc.arg(payload)::text);
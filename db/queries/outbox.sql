@@ -0,0 +1,35 @@
+-- name: CreateOutboxEvent :one
+INSERT INTO outbox (
+  aggregate_type,
+  aggregate_id,
+  event_type,
+  payload
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ListOutboxEvents :many
+-- Only events of transactions older than every running transaction are
+-- returned, so that an event committed late can never fall behind the offset.
+SELECT * FROM outbox
+WHERE (txid, id) > (sqlc.arg(txid)::bigint, sqlc.arg(event_id)::bigint)
+  AND txid < txid_snapshot_xmin(txid_current_snapshot())
+ORDER BY txid, id
+LIMIT sqlc.arg('limit');
+
+-- name: GetOutboxOffset :one
+SELECT * FROM outbox_offsets
+WHERE consumer = $1 LIMIT 1;
+
+-- name: UpsertOutboxOffset :exec
+INSERT INTO outbox_offsets (
+  consumer,
+  txid,
+  event_id
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (consumer) DO UPDATE
+SET txid = EXCLUDED.txid,
+    event_id = EXCLUDED.event_id,
+    updated_at = now();
@@ -0,0 +1,23 @@
+-- name: TakeRateLimitToken :one
+-- Refills the bucket of key at rate tokens per second up to burst and takes
+-- a token from it if one is left. The row lock of the upsert serializes
+-- concurrent requests for the same key.
+INSERT INTO rate_limit_buckets (
+  key,
+  tokens,
+  allowed
+) VALUES (
+  sqlc.arg(key), sqlc.arg(burst)::float8 - 1, true
+)
+ON CONFLICT (key) DO UPDATE
+SET tokens = LEAST(sqlc.arg(burst)::float8, rate_limit_buckets.tokens + sqlc.arg(rate)::float8 * EXTRACT(EPOCH FROM now() - rate_limit_buckets.updated_at)::float8)
+      - CASE WHEN LEAST(sqlc.arg(burst)::float8, rate_limit_buckets.tokens + sqlc.arg(rate)::float8 * EXTRACT(EPOCH FROM now() - rate_limit_buckets.updated_at)::float8) >= 1 THEN 1 ELSE 0 END,
+    allowed = LEAST(sqlc.arg(burst)::float8, rate_limit_buckets.tokens + sqlc.arg(rate)::float8 * EXTRACT(EPOCH FROM now() - rate_limit_buckets.updated_at)::float8) >= 1,
+    updated_at = now()
+RETURNING *;
+
+-- name: DeleteIdleRateLimitBuckets :exec
+-- Buckets idle for longer than it takes them to refill are full and can be
+-- recreated on the next request.
+DELETE FROM rate_limit_buckets
+WHERE updated_at < now() - sqlc.arg(max_idle_seconds)::int * interval '1 second';
@@ -0,0 +1,20 @@
+-- name: UpsertRoundUpRule :one
+INSERT INTO round_up_rules (
+  account_id,
+  pot_id,
+  round_to
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (account_id) DO UPDATE
+SET pot_id = EXCLUDED.pot_id,
+    round_to = EXCLUDED.round_to
+RETURNING *;
+
+-- name: GetRoundUpRule :one
+SELECT * FROM round_up_rules
+WHERE account_id = $1 LIMIT 1;
+
+-- name: DeleteRoundUpRule :exec
+DELETE FROM round_up_rules
+WHERE account_id = $1;
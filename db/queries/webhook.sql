@@ -0,0 +1,115 @@
+-- name: CreateWebhook :one
+INSERT INTO webhooks (
+  owner,
+  url,
+  event_types,
+  secret
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetWebhook :one
+SELECT * FROM webhooks
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhooks :many
+SELECT * FROM webhooks
+WHERE owner = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
+
+-- name: DeleteWebhook :exec
+DELETE FROM webhooks
+WHERE id = $1;
+
+-- name: EnableWebhook :one
+UPDATE webhooks
+SET disabled = false,
+    consecutive_failures = 0
+WHERE id = $1
+RETURNING *;
+
+-- name: ListWebhooksForEvent :many
+-- Enabled webhooks subscribed to the event type whose owner is a member of
+-- one of the accounts.
+SELECT DISTINCT w.* FROM webhooks w
+JOIN account_members m ON m.username = w.owner
+WHERE m.account_id = ANY(sqlc.arg(account_ids)::bigint[])
+  AND sqlc.arg(event_type)::varchar = ANY(w.event_types)
+  AND NOT w.disabled;
+
+-- name: RecordWebhookSuccess :exec
+UPDATE webhooks
+SET consecutive_failures = 0
+WHERE id = $1;
+
+-- name: RecordWebhookFailure :one
+UPDATE webhooks
+SET consecutive_failures = consecutive_failures + 1,
+    disabled = disabled OR consecutive_failures + 1 >= sqlc.arg(max_failures)::int
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreateWebhookDelivery :exec
+INSERT INTO webhook_deliveries (
+  webhook_id,
+  event_id,
+  event_type,
+  payload
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (webhook_id, event_id) DO NOTHING;
+
+-- name: GetWebhookDelivery :one
+SELECT * FROM webhook_deliveries
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhookDeliveries :many
+SELECT * FROM webhook_deliveries
+WHERE webhook_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: ClaimDueWebhookDeliveries :many
+-- Claims due deliveries for a dispatcher by pushing their next attempt back
+-- by the lease. Rows claimed by a concurrent dispatcher are skipped, and the
+-- outcome of the attempt sets the next attempt again.
+UPDATE webhook_deliveries
+SET next_attempt_at = now() + sqlc.arg(lease_seconds)::int * interval '1 second'
+WHERE id IN (
+  SELECT d.id FROM webhook_deliveries d
+  JOIN webhooks w ON w.id = d.webhook_id
+  WHERE d.status = 'pending'
+    AND d.next_attempt_at <= now()
+    AND NOT w.disabled
+  ORDER BY d.next_attempt_at
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE OF d SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkWebhookDeliveryDelivered :exec
+UPDATE webhook_deliveries
+SET status = 'delivered',
+    attempts = attempts + 1,
+    last_error = NULL,
+    delivered_at = now()
+WHERE id = $1;
+
+-- name: MarkWebhookDeliveryFailed :exec
+UPDATE webhook_deliveries
+SET status = sqlc.arg(status),
+    attempts = attempts + 1,
+    last_error = sqlc.arg(last_error),
+    next_attempt_at = now() + sqlc.arg(backoff_seconds)::int * interval '1 second'
+WHERE id = sqlc.arg(id);
+
+-- name: ReplayWebhookDelivery :one
+UPDATE webhook_deliveries
+SET status = 'pending',
+    next_attempt_at = now()
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,46 @@
+package db
+
+import (
+	"context"
+	"slices"
+)
+
+// AccountNumbers maps account ids to their account numbers, so that
+// responses can refer to accounts without their internal ids. The known
+// accounts are used as they are, the other ids are looked up in one query.
+func AccountNumbers(ctx context.Context, q Querier, known []Account, ids ...int64) (map[int64]string, error) {
+	numbers := make(map[int64]string, len(known)+len(ids))
+	for _, account := range known {
+		numbers[account.ID] = account.AccountNumber
+	}
+
+	var missing []int64
+	for _, id := range ids {
+		if _, ok := numbers[id]; !ok && !slices.Contains(missing, id) {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return numbers, nil
+	}
+
+	rows, err := q.ListAccountNumbers(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		numbers[row.ID] = row.AccountNumber
+	}
+	return numbers, nil
+}
+
+// ParentIDs returns the ids of the parent accounts of the pots among accounts.
+func ParentIDs(accounts ...Account) []int64 {
+	var ids []int64
+	for _, account := range accounts {
+		if account.ParentID.Valid {
+			ids = append(ids, account.ParentID.Int64)
+		}
+	}
+	return ids
+}
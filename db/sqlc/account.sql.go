@@ -7,24 +7,61 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createAccount = `-- name: CreateAccount :one
 insert into accounts(
-    owner, balance, currency
+    owner, balance, currency, parent_id, account_number
 ) values (
-    $1, $2, $3
-) returning id, owner, balance, currency, created_at
+    $1, $2, $3, $4, $5
+) returning id, owner, balance, currency, created_at, parent_id, account_number
 `
 
 type CreateAccountParams struct {
-	Owner    string `json:"owner"`
-	Balance  int64  `json:"balance"`
-	Currency string `json:"currency"`
+	Owner         string      `json:"owner"`
+	Balance       int64       `json:"balance"`
+	Currency      string      `json:"currency"`
+	ParentID      pgtype.Int8 `json:"parent_id"`
+	AccountNumber string      `json:"account_number"`
 }
 
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
-	row := q.db.QueryRow(ctx, createAccount, arg.Owner, arg.Balance, arg.Currency)
+	row := q.db.QueryRow(ctx, createAccount,
+		arg.Owner,
+		arg.Balance,
+		arg.Currency,
+		arg.ParentID,
+		arg.AccountNumber,
+	)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.ParentID,
+		&i.AccountNumber,
+	)
+	return i, err
+}
+
+const debitAccountBalanceByID = `-- name: DebitAccountBalanceByID :one
+update accounts
+set balance = balance - $1
+where id = $2 and balance >= $1
+RETURNING id, owner, balance, currency, created_at, parent_id, account_number
+`
+
+type DebitAccountBalanceByIDParams struct {
+	Amount    int64 `json:"amount"`
+	AccountID int64 `json:"account_id"`
+}
+
+func (q *Queries) DebitAccountBalanceByID(ctx context.Context, arg DebitAccountBalanceByIDParams) (Account, error) {
+	row := q.db.QueryRow(ctx, debitAccountBalanceByID, arg.Amount, arg.AccountID)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -32,6 +69,8 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.ParentID,
+		&i.AccountNumber,
 	)
 	return i, err
 }
@@ -47,7 +86,7 @@ func (q *Queries) DeleteAccountByID(ctx context.Context, id int64) error {
 }
 
 const getAccountById = `-- name: GetAccountById :one
-select id, owner, balance, currency, created_at from accounts
+select id, owner, balance, currency, created_at, parent_id, account_number from accounts
 where id = $1 limit 1
 `
 
@@ -60,12 +99,34 @@ func (q *Queries) GetAccountById(ctx context.Context, id int64) (Account, error)
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.ParentID,
+		&i.AccountNumber,
+	)
+	return i, err
+}
+
+const getAccountByNumber = `-- name: GetAccountByNumber :one
+select id, owner, balance, currency, created_at, parent_id, account_number from accounts
+where account_number = $1 limit 1
+`
+
+func (q *Queries) GetAccountByNumber(ctx context.Context, accountNumber string) (Account, error) {
+	row := q.db.QueryRow(ctx, getAccountByNumber, accountNumber)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.ParentID,
+		&i.AccountNumber,
 	)
 	return i, err
 }
 
 const getAccountForUpdate = `-- name: GetAccountForUpdate :one
-select id, owner, balance, currency, created_at from accounts
+select id, owner, balance, currency, created_at, parent_id, account_number from accounts
 where id = $1 limit 1
 for no key update
 `
@@ -79,12 +140,79 @@ func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Account, e
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.ParentID,
+		&i.AccountNumber,
 	)
 	return i, err
 }
 
+const listAccountNumbers = `-- name: ListAccountNumbers :many
+select id, account_number from accounts
+where id = any($1::bigint[])
+order by id
+`
+
+type ListAccountNumbersRow struct {
+	ID            int64  `json:"id"`
+	AccountNumber string `json:"account_number"`
+}
+
+func (q *Queries) ListAccountNumbers(ctx context.Context, ids []int64) ([]ListAccountNumbersRow, error) {
+	rows, err := q.db.Query(ctx, listAccountNumbers, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAccountNumbersRow{}
+	for rows.Next() {
+		var i ListAccountNumbersRow
+		if err := rows.Scan(&i.ID, &i.AccountNumber); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAccountPots = `-- name: ListAccountPots :many
+select id, owner, balance, currency, created_at, parent_id, account_number from accounts
+where parent_id = $1
+order by id
+`
+
+func (q *Queries) ListAccountPots(ctx context.Context, parentID pgtype.Int8) ([]Account, error) {
+	rows, err := q.db.Query(ctx, listAccountPots, parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.ParentID,
+			&i.AccountNumber,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listAccounts = `-- name: ListAccounts :many
-select id, owner, balance, currency, created_at from accounts
+select id, owner, balance, currency, created_at, parent_id, account_number from accounts
 order by id
 limit $1
 offset $2
@@ -110,6 +238,8 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
+			&i.ParentID,
+			&i.AccountNumber,
 		); err != nil {
 			return nil, err
 		}
@@ -125,7 +255,7 @@ const updateAccountBalanceByID = `-- name: UpdateAccountBalanceByID :one
 update accounts
 set balance = balance + $1
 where id = $2
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, parent_id, account_number
 `
 
 type UpdateAccountBalanceByIDParams struct {
@@ -142,6 +272,8 @@ func (q *Queries) UpdateAccountBalanceByID(ctx context.Context, arg UpdateAccoun
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.ParentID,
+		&i.AccountNumber,
 	)
 	return i, err
 }
@@ -150,7 +282,7 @@ const updateAccountByID = `-- name: UpdateAccountByID :one
 UPDATE accounts
 SET balance = $2
 WHERE id = $1
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, parent_id, account_number
 `
 
 type UpdateAccountByIDParams struct {
@@ -167,6 +299,8 @@ func (q *Queries) UpdateAccountByID(ctx context.Context, arg UpdateAccountByIDPa
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.ParentID,
+		&i.AccountNumber,
 	)
 	return i, err
 }
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferTxFrozenAccount(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	freeze, err := store.FreezeAccount(ctx, FreezeAccountParams{AccountID: account2.ID, Reason: "fraud review"})
+	require.NoError(t, err)
+	require.Equal(t, account2.ID, freeze.AccountID)
+
+	// neither sending to nor from the frozen account is allowed
+	for _, arg := range []TransferTxParams{
+		{FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 1},
+		{FromAccountID: account2.ID, ToAccountID: account1.ID, Amount: 1},
+	} {
+		_, err = store.TransferTx(ctx, arg)
+		require.ErrorIs(t, err, ErrAccountFrozen)
+	}
+
+	got, err := store.GetAccountById(ctx, account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, got.Balance)
+
+	require.NoError(t, store.UnfreezeAccount(ctx, account2.ID))
+	_, err = store.TransferTx(ctx, TransferTxParams{FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 1})
+	require.NoError(t, err)
+}
+
+func TestStoreInTransaction(t *testing.T) {
+	ctx := context.Background()
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	tx, err := testDB.Begin(ctx)
+	require.NoError(t, err)
+
+	// the transfer becomes a savepoint of tx and goes away with it
+	store := NewStore(tx)
+	_, err = store.TransferTx(ctx, TransferTxParams{FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 1})
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback(ctx))
+
+	got, err := testQueries.GetAccountById(ctx, account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, got.Balance)
+}
@@ -14,8 +14,10 @@ import (
 func createRandomAccount(t *testing.T) Account{
 	arg := CreateAccountParams{
 		Owner: utils.RandomOwner(),
-		Balance: utils.RandomMoney(),
+		// enough for the transfers the tests make, which may not overdraw
+		Balance: 1000 + utils.RandomMoney(),
 		Currency: utils.RandomCurrency(),
+		AccountNumber: utils.RandomAccountNumber(),
 	}
 
 	account, err := testQueries.CreateAccount(context.Background(), arg)
@@ -26,6 +28,7 @@ func createRandomAccount(t *testing.T) Account{
 	require.Equal(t, arg.Owner, account.Owner)
 	require.Equal(t, arg.Balance, account.Balance)
 	require.Equal(t, arg.Currency, account.Currency)
+	require.Equal(t, arg.AccountNumber, account.AccountNumber)
 
 	require.NotZero(t, account.ID)
 	require.NotZero(t, account.CreatedAt)
@@ -50,6 +53,25 @@ func TestGetAccount(t *testing.T){
 	require.WithinDuration(t, account1.CreatedAt.Time, account2.CreatedAt.Time, time.Second)
 }
 
+func TestGetAccountByNumber(t *testing.T){
+	account1 := createRandomAccount(t)
+	account2, err := testQueries.GetAccountByNumber(context.Background(), account1.AccountNumber)
+	require.NoError(t, err)
+	require.Equal(t, account1.ID, account2.ID)
+	require.Equal(t, account1.AccountNumber, account2.AccountNumber)
+}
+
+func TestListAccountNumbers(t *testing.T){
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	rows, err := testQueries.ListAccountNumbers(context.Background(), []int64{account2.ID, account1.ID})
+	require.NoError(t, err)
+	require.Equal(t, []ListAccountNumbersRow{
+		{ID: account1.ID, AccountNumber: account1.AccountNumber},
+		{ID: account2.ID, AccountNumber: account2.AccountNumber},
+	}, rows)
+}
 
 func TestDeleteAccount(t *testing.T){
 	account1 := createRandomAccount(t)
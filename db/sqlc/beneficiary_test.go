@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomBeneficiary(t *testing.T, owner string) Beneficiary {
+	account := createRandomAccount(t)
+
+	arg := CreateBeneficiaryParams{
+		Owner:     owner,
+		Nickname:  utils.RandomString(),
+		AccountID: account.ID,
+	}
+
+	beneficiary, err := testQueries.CreateBeneficiary(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, beneficiary.ID)
+	require.Equal(t, arg.Owner, beneficiary.Owner)
+	require.Equal(t, arg.Nickname, beneficiary.Nickname)
+	require.Equal(t, arg.AccountID, beneficiary.AccountID)
+	require.False(t, beneficiary.Verified)
+	require.NotZero(t, beneficiary.CreatedAt)
+
+	return beneficiary
+}
+
+func TestBeneficiaryCRUD(t *testing.T) {
+	owner := utils.RandomOwner()
+	beneficiary := createRandomBeneficiary(t, owner)
+	createRandomBeneficiary(t, owner)
+
+	beneficiaries, err := testQueries.ListBeneficiaries(context.Background(), ListBeneficiariesParams{
+		Owner:  owner,
+		Limit:  5,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, beneficiaries, 2)
+
+	updated, err := testQueries.UpdateBeneficiaryNickname(context.Background(), UpdateBeneficiaryNicknameParams{
+		ID:       beneficiary.ID,
+		Nickname: "rent",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "rent", updated.Nickname)
+
+	verified, err := testQueries.VerifyBeneficiary(context.Background(), beneficiary.ID)
+	require.NoError(t, err)
+	require.True(t, verified.Verified)
+	require.Equal(t, "rent", verified.Nickname)
+
+	// the same account can only be saved once per user
+	_, err = testQueries.CreateBeneficiary(context.Background(), CreateBeneficiaryParams{
+		Owner:     owner,
+		Nickname:  "duplicate",
+		AccountID: beneficiary.AccountID,
+	})
+	require.Equal(t, UniqueViolation, ErrorCode(err))
+
+	err = testQueries.DeleteBeneficiary(context.Background(), beneficiary.ID)
+	require.NoError(t, err)
+
+	_, err = testQueries.GetBeneficiary(context.Background(), beneficiary.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
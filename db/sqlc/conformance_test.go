@@ -0,0 +1,14 @@
+package db_test
+
+import (
+	"testing"
+
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/db/storetest"
+)
+
+func TestSQLStoreConformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) db.Store {
+		return db.NewStore(db.PoolForTests())
+	})
+}
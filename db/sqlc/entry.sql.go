@@ -7,6 +7,8 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createEntry = `-- name: CreateEntry :one
@@ -15,7 +17,7 @@ INSERT INTO entries (
   amount
 ) VALUES (
   $1, $2
-) RETURNING id, account_id, amount, created_at
+) RETURNING id, account_id, amount, created_at, opening
 `
 
 type CreateEntryParams struct {
@@ -31,12 +33,42 @@ func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Opening,
+	)
+	return i, err
+}
+
+const createOpeningEntry = `-- name: CreateOpeningEntry :one
+INSERT INTO entries (
+  account_id,
+  amount,
+  opening
+) VALUES (
+  $1, $2, true
+) RETURNING id, account_id, amount, created_at, opening
+`
+
+type CreateOpeningEntryParams struct {
+	AccountID int64 `json:"account_id"`
+	Amount    int64 `json:"amount"`
+}
+
+// Books the balance an account was created with.
+func (q *Queries) CreateOpeningEntry(ctx context.Context, arg CreateOpeningEntryParams) (Entry, error) {
+	row := q.db.QueryRow(ctx, createOpeningEntry, arg.AccountID, arg.Amount)
+	var i Entry
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Opening,
 	)
 	return i, err
 }
 
 const getEntry = `-- name: GetEntry :one
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, opening FROM entries
 WHERE id = $1 LIMIT 1
 `
 
@@ -48,12 +80,65 @@ func (q *Queries) GetEntry(ctx context.Context, id int64) (Entry, error) {
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Opening,
 	)
 	return i, err
 }
 
+const listAccountEntriesAfter = `-- name: ListAccountEntriesAfter :many
+SELECT e.id, e.account_id, e.amount, e.created_at,
+  (a.balance - COALESCE(SUM(e.amount) OVER (
+    ORDER BY e.id DESC ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING
+  ), 0))::bigint AS balance
+FROM entries e
+JOIN accounts a ON a.id = e.account_id
+WHERE e.account_id = $1 AND e.id > $2
+ORDER BY e.id
+`
+
+type ListAccountEntriesAfterParams struct {
+	AccountID int64 `json:"account_id"`
+	AfterID   int64 `json:"after_id"`
+}
+
+type ListAccountEntriesAfterRow struct {
+	ID        int64            `json:"id"`
+	AccountID int64            `json:"account_id"`
+	Amount    int64            `json:"amount"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	Balance   int64            `json:"balance"`
+}
+
+// Entries of an account after the given entry, each with the balance the
+// account had right after it.
+func (q *Queries) ListAccountEntriesAfter(ctx context.Context, arg ListAccountEntriesAfterParams) ([]ListAccountEntriesAfterRow, error) {
+	rows, err := q.db.Query(ctx, listAccountEntriesAfter, arg.AccountID, arg.AfterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAccountEntriesAfterRow{}
+	for rows.Next() {
+		var i ListAccountEntriesAfterRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Balance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listEntries = `-- name: ListEntries :many
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, opening FROM entries
 WHERE account_id = $1
 ORDER BY id
 LIMIT $2
@@ -80,6 +165,7 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 			&i.AccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.Opening,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListAccountEntriesAfter(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	var results []TransferTxResult
+	for _, amount := range []int64{1, 2, 3} {
+		result, err := store.TransferTx(context.Background(), TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        amount,
+		})
+		require.NoError(t, err)
+		results = append(results, result)
+	}
+
+	entries, err := testQueries.ListAccountEntriesAfter(context.Background(), ListAccountEntriesAfterParams{
+		AccountID: account1.ID,
+		AfterID:   results[0].FromEntry.ID,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	// every entry carries the balance right after it
+	for i, entry := range entries {
+		require.Equal(t, results[i+1].FromEntry.ID, entry.ID)
+		require.Equal(t, results[i+1].FromAccount.Balance, entry.Balance)
+	}
+}
@@ -0,0 +1,9 @@
+package db
+
+import "github.com/jackc/pgx/v5/pgxpool"
+
+// PoolForTests returns the pool of the test database to the tests of
+// package db_test.
+func PoolForTests() *pgxpool.Pool {
+	return testDB
+}
@@ -0,0 +1,27 @@
+package db
+
+const (
+	RoleOwner   = "owner"
+	RoleCoOwner = "co_owner"
+	RoleViewer  = "viewer"
+	// RoleLimited members may transfer up to their transfer limit per transfer.
+	RoleLimited = "limited"
+)
+
+// CanManage reports whether the member may change who has access to the account
+// and how it is configured.
+func (member AccountMember) CanManage() bool {
+	return member.Role == RoleOwner
+}
+
+// CanTransfer reports whether the member may move amount out of the account.
+func (member AccountMember) CanTransfer(amount int64) bool {
+	switch member.Role {
+	case RoleOwner, RoleCoOwner:
+		return true
+	case RoleLimited:
+		return amount <= member.TransferLimit
+	default:
+		return false
+	}
+}
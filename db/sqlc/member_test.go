@@ -0,0 +1,76 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAccountTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	arg := CreateAccountParams{
+		Owner:         utils.RandomOwner(),
+		Balance:       0,
+		Currency:      utils.RandomCurrency(),
+		AccountNumber: utils.RandomAccountNumber(),
+	}
+
+	result, err := store.CreateAccountTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Owner, result.Account.Owner)
+	require.Equal(t, result.Account.ID, result.Member.AccountID)
+	require.Equal(t, arg.Owner, result.Member.Username)
+	require.Equal(t, RoleOwner, result.Member.Role)
+
+	member, err := testQueries.GetAccountMember(context.Background(), GetAccountMemberParams{
+		AccountID: result.Account.ID,
+		Username:  arg.Owner,
+	})
+	require.NoError(t, err)
+	require.Equal(t, result.Member, member)
+}
+
+func TestListMemberAccounts(t *testing.T) {
+	account := createRandomAccount(t)
+	username := utils.RandomOwner()
+
+	_, err := testQueries.CreateAccountMember(context.Background(), CreateAccountMemberParams{
+		AccountID:     account.ID,
+		Username:      username,
+		Role:          RoleLimited,
+		TransferLimit: 50,
+	})
+	require.NoError(t, err)
+
+	accounts, err := testQueries.ListMemberAccounts(context.Background(), ListMemberAccountsParams{
+		Username: username,
+		Limit:    5,
+		Offset:   0,
+	})
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	require.Equal(t, account.ID, accounts[0].ID)
+
+	err = testQueries.DeleteAccountMember(context.Background(), DeleteAccountMemberParams{
+		AccountID: account.ID,
+		Username:  username,
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.GetAccountMember(context.Background(), GetAccountMemberParams{
+		AccountID: account.ID,
+		Username:  username,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestAccountMemberCanTransfer(t *testing.T) {
+	require.True(t, AccountMember{Role: RoleOwner}.CanTransfer(1_000_000))
+	require.True(t, AccountMember{Role: RoleCoOwner}.CanTransfer(1_000_000))
+	require.False(t, AccountMember{Role: RoleViewer}.CanTransfer(1))
+	require.True(t, AccountMember{Role: RoleLimited, TransferLimit: 100}.CanTransfer(100))
+	require.False(t, AccountMember{Role: RoleLimited, TransferLimit: 100}.CanTransfer(101))
+}
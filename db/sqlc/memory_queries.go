@@ -0,0 +1,1179 @@
+package db
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The queries below mirror the SQL of the generated ones, including the
+// constraints of the tables they write to.
+
+// accountReferenced returns the foreign key constraint that keeps account id
+// from being deleted, if any. Members, beneficiaries and freezes are
+// deleted along with the account instead.
+func (q *memQueries) accountReferenced(id int64) (table, constraint string) {
+	for _, entry := range q.db.entries {
+		if entry.AccountID == id {
+			return "entries", "entries_account_id_fkey"
+		}
+	}
+	for _, transfer := range q.db.transfers {
+		if transfer.FromAccountID == id {
+			return "transfers", "transfers_from_account_id_fkey"
+		}
+		if transfer.ToAccountID == id {
+			return "transfers", "transfers_to_account_id_fkey"
+		}
+	}
+	for _, account := range q.db.accounts {
+		if account.ParentID.Valid && account.ParentID.Int64 == id {
+			return "accounts", "accounts_parent_id_fkey"
+		}
+	}
+	for _, rule := range q.db.roundUpRules {
+		if rule.AccountID == id {
+			return "round_up_rules", "round_up_rules_account_id_fkey"
+		}
+		if rule.PotID == id {
+			return "round_up_rules", "round_up_rules_pot_id_fkey"
+		}
+	}
+	if _, ok := q.db.policies[id]; ok {
+		return "approval_policies", "approval_policies_account_id_fkey"
+	}
+	for _, request := range q.db.requests {
+		if request.FromAccountID == id {
+			return "approval_requests", "approval_requests_from_account_id_fkey"
+		}
+		if request.ToAccountID == id {
+			return "approval_requests", "approval_requests_to_account_id_fkey"
+		}
+	}
+	return "", ""
+}
+
+// checkAccount fails like a foreign key on table referencing account id.
+func (q *memQueries) checkAccount(id int64, table, constraint string) error {
+	if _, ok := q.db.accounts[id]; !ok {
+		return constraintError(ForeignKeyViolation, table, constraint)
+	}
+	return nil
+}
+
+func (q *memQueries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
+	q, end := q.begin()
+	defer end()
+
+	if arg.ParentID.Valid {
+		if err := q.checkAccount(arg.ParentID.Int64, "accounts", "accounts_parent_id_fkey"); err != nil {
+			return Account{}, err
+		}
+	}
+	for _, account := range q.db.accounts {
+		if account.AccountNumber == arg.AccountNumber {
+			return Account{}, constraintError(UniqueViolation, "accounts", "accounts_account_number_idx")
+		}
+	}
+
+	account := Account{
+		ID:            q.db.nextID("accounts"),
+		Owner:         arg.Owner,
+		Balance:       arg.Balance,
+		Currency:      arg.Currency,
+		CreatedAt:     q.tx.timestamp(),
+		ParentID:      arg.ParentID,
+		AccountNumber: arg.AccountNumber,
+	}
+	put(q, q.db.accounts, account.ID, account)
+	return account, nil
+}
+
+func (q *memQueries) DeleteAccountByID(ctx context.Context, id int64) error {
+	q, end := q.begin()
+	defer end()
+
+	if _, ok := q.db.accounts[id]; !ok {
+		return nil
+	}
+	if table, constraint := q.accountReferenced(id); constraint != "" {
+		return constraintError(ForeignKeyViolation, table, constraint)
+	}
+
+	for key := range q.db.members {
+		if key.accountID == id {
+			remove(q, q.db.members, key)
+		}
+	}
+	for key, beneficiary := range q.db.beneficiaries {
+		if beneficiary.AccountID == id {
+			remove(q, q.db.beneficiaries, key)
+		}
+	}
+	remove(q, q.db.freezes, id)
+	remove(q, q.db.accounts, id)
+	return nil
+}
+
+func (q *memQueries) GetAccountById(ctx context.Context, id int64) (Account, error) {
+	q, end := q.begin()
+	defer end()
+
+	account, ok := q.db.accounts[id]
+	if !ok {
+		return Account{}, ErrRecordNotFound
+	}
+	return account, nil
+}
+
+func (q *memQueries) GetAccountByNumber(ctx context.Context, accountNumber string) (Account, error) {
+	q, end := q.begin()
+	defer end()
+
+	for _, account := range q.db.accounts {
+		if account.AccountNumber == accountNumber {
+			return account, nil
+		}
+	}
+	return Account{}, ErrRecordNotFound
+}
+
+func (q *memQueries) ListAccountNumbers(ctx context.Context, ids []int64) ([]ListAccountNumbersRow, error) {
+	q, end := q.begin()
+	defer end()
+
+	accounts := selectRows(q.db.accounts, func(account Account) bool {
+		return slices.Contains(ids, account.ID)
+	}, func(a, b Account) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	rows := []ListAccountNumbersRow{}
+	for _, account := range accounts {
+		rows = append(rows, ListAccountNumbersRow{ID: account.ID, AccountNumber: account.AccountNumber})
+	}
+	return rows, nil
+}
+
+// GetAccountForUpdate needs no row lock, the transaction holds the store.
+func (q *memQueries) GetAccountForUpdate(ctx context.Context, id int64) (Account, error) {
+	return q.GetAccountById(ctx, id)
+}
+
+func (q *memQueries) ListAccountPots(ctx context.Context, parentID pgtype.Int8) ([]Account, error) {
+	q, end := q.begin()
+	defer end()
+
+	return selectRows(q.db.accounts, func(account Account) bool {
+		return parentID.Valid && account.ParentID.Valid && account.ParentID.Int64 == parentID.Int64
+	}, func(a, b Account) int {
+		return cmp.Compare(a.ID, b.ID)
+	}), nil
+}
+
+func (q *memQueries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error) {
+	q, end := q.begin()
+	defer end()
+
+	accounts := selectRows(q.db.accounts, func(Account) bool { return true }, func(a, b Account) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return page(accounts, arg.Limit, arg.Offset), nil
+}
+
+func (q *memQueries) UpdateAccountBalanceByID(ctx context.Context, arg UpdateAccountBalanceByIDParams) (Account, error) {
+	q, end := q.begin()
+	defer end()
+
+	account, ok := q.db.accounts[arg.AccountID]
+	if !ok {
+		return Account{}, ErrRecordNotFound
+	}
+	account.Balance += arg.Amount
+	put(q, q.db.accounts, account.ID, account)
+	return account, nil
+}
+
+func (q *memQueries) DebitAccountBalanceByID(ctx context.Context, arg DebitAccountBalanceByIDParams) (Account, error) {
+	q, end := q.begin()
+	defer end()
+
+	account, ok := q.db.accounts[arg.AccountID]
+	if !ok || account.Balance < arg.Amount {
+		return Account{}, ErrRecordNotFound
+	}
+	account.Balance -= arg.Amount
+	put(q, q.db.accounts, account.ID, account)
+	return account, nil
+}
+
+func (q *memQueries) UpdateAccountByID(ctx context.Context, arg UpdateAccountByIDParams) (Account, error) {
+	q, end := q.begin()
+	defer end()
+
+	account, ok := q.db.accounts[arg.ID]
+	if !ok {
+		return Account{}, ErrRecordNotFound
+	}
+	account.Balance = arg.Balance
+	put(q, q.db.accounts, account.ID, account)
+	return account, nil
+}
+
+func (q *memQueries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
+	return q.createEntry(arg.AccountID, arg.Amount, false)
+}
+
+func (q *memQueries) CreateOpeningEntry(ctx context.Context, arg CreateOpeningEntryParams) (Entry, error) {
+	return q.createEntry(arg.AccountID, arg.Amount, true)
+}
+
+func (q *memQueries) createEntry(accountID, amount int64, opening bool) (Entry, error) {
+	q, end := q.begin()
+	defer end()
+
+	if err := q.checkAccount(accountID, "entries", "entries_account_id_fkey"); err != nil {
+		return Entry{}, err
+	}
+
+	entry := Entry{
+		ID:        q.db.nextID("entries"),
+		AccountID: accountID,
+		Amount:    amount,
+		CreatedAt: q.tx.timestamp(),
+		Opening:   opening,
+	}
+	put(q, q.db.entries, entry.ID, entry)
+	return entry, nil
+}
+
+func (q *memQueries) GetEntry(ctx context.Context, id int64) (Entry, error) {
+	q, end := q.begin()
+	defer end()
+
+	entry, ok := q.db.entries[id]
+	if !ok {
+		return Entry{}, ErrRecordNotFound
+	}
+	return entry, nil
+}
+
+func (q *memQueries) ListAccountEntriesAfter(ctx context.Context, arg ListAccountEntriesAfterParams) ([]ListAccountEntriesAfterRow, error) {
+	q, end := q.begin()
+	defer end()
+
+	account, ok := q.db.accounts[arg.AccountID]
+	if !ok {
+		return []ListAccountEntriesAfterRow{}, nil
+	}
+
+	entries := selectRows(q.db.entries, func(entry Entry) bool {
+		return entry.AccountID == arg.AccountID && entry.ID > arg.AfterID
+	}, func(a, b Entry) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	// the balance after an entry is the current one minus all later entries
+	rows := make([]ListAccountEntriesAfterRow, len(entries))
+	balance := account.Balance
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		rows[i] = ListAccountEntriesAfterRow{
+			ID:        entry.ID,
+			AccountID: entry.AccountID,
+			Amount:    entry.Amount,
+			CreatedAt: entry.CreatedAt,
+			Balance:   balance,
+		}
+		balance -= entry.Amount
+	}
+	return rows, nil
+}
+
+func (q *memQueries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error) {
+	q, end := q.begin()
+	defer end()
+
+	entries := selectRows(q.db.entries, func(entry Entry) bool {
+		return entry.AccountID == arg.AccountID
+	}, func(a, b Entry) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return page(entries, arg.Limit, arg.Offset), nil
+}
+
+func (q *memQueries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
+	q, end := q.begin()
+	defer end()
+
+	if err := q.checkAccount(arg.FromAccountID, "transfers", "transfers_from_account_id_fkey"); err != nil {
+		return Transfer{}, err
+	}
+	if err := q.checkAccount(arg.ToAccountID, "transfers", "transfers_to_account_id_fkey"); err != nil {
+		return Transfer{}, err
+	}
+
+	transfer := Transfer{
+		ID:            q.db.nextID("transfers"),
+		FromAccountID: arg.FromAccountID,
+		ToAccountID:   arg.ToAccountID,
+		Amount:        arg.Amount,
+		CreatedAt:     q.tx.timestamp(),
+	}
+	put(q, q.db.transfers, transfer.ID, transfer)
+	return transfer, nil
+}
+
+func (q *memQueries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
+	q, end := q.begin()
+	defer end()
+
+	transfer, ok := q.db.transfers[id]
+	if !ok {
+		return Transfer{}, ErrRecordNotFound
+	}
+	return transfer, nil
+}
+
+func (q *memQueries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error) {
+	q, end := q.begin()
+	defer end()
+
+	transfers := selectRows(q.db.transfers, func(transfer Transfer) bool {
+		return transfer.FromAccountID == arg.FromAccountID || transfer.ToAccountID == arg.ToAccountID
+	}, func(a, b Transfer) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return page(transfers, arg.Limit, arg.Offset), nil
+}
+
+func (q *memQueries) GetLedgerTotals(ctx context.Context) (GetLedgerTotalsRow, error) {
+	q, end := q.begin()
+	defer end()
+
+	var totals GetLedgerTotalsRow
+	for _, entry := range q.db.entries {
+		totals.EntryCount++
+		totals.EntryTotal += entry.Amount
+		if entry.Opening {
+			totals.OpeningCount++
+			totals.OpeningTotal += entry.Amount
+		}
+	}
+	for _, transfer := range q.db.transfers {
+		totals.TransferCount++
+		totals.TransferTotal += transfer.Amount
+	}
+	booked := q.entryTotals()
+	for _, account := range q.db.accounts {
+		if account.Balance < 0 {
+			totals.NegativeAccounts++
+		}
+		if account.Balance != booked[account.ID] {
+			totals.UnbalancedAccounts++
+		}
+	}
+	return totals, nil
+}
+
+func (q *memQueries) ListUnbalancedAccounts(ctx context.Context, limit int32) ([]ListUnbalancedAccountsRow, error) {
+	q, end := q.begin()
+	defer end()
+
+	booked := q.entryTotals()
+	accounts := selectRows(q.db.accounts, func(account Account) bool {
+		return account.Balance != booked[account.ID]
+	}, func(a, b Account) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	rows := []ListUnbalancedAccountsRow{}
+	for _, account := range page(accounts, limit, 0) {
+		rows = append(rows, ListUnbalancedAccountsRow{
+			ID:            account.ID,
+			AccountNumber: account.AccountNumber,
+			Balance:       account.Balance,
+			EntryTotal:    booked[account.ID],
+		})
+	}
+	return rows, nil
+}
+
+// entryTotals sums up the entries of every account.
+func (q *memQueries) entryTotals() map[int64]int64 {
+	totals := make(map[int64]int64)
+	for _, entry := range q.db.entries {
+		totals[entry.AccountID] += entry.Amount
+	}
+	return totals
+}
+
+func (q *memQueries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
+	q, end := q.begin()
+	defer end()
+
+	if _, ok := q.db.users[arg.Username]; ok {
+		return User{}, constraintError(UniqueViolation, "users", "users_pkey")
+	}
+	for _, user := range q.db.users {
+		if user.Email == arg.Email {
+			return User{}, constraintError(UniqueViolation, "users", "users_email_key")
+		}
+	}
+
+	user := User{
+		Username:  arg.Username,
+		FullName:  arg.FullName,
+		Email:     arg.Email,
+		CreatedAt: q.tx.timestamp(),
+	}
+	put(q, q.db.users, user.Username, user)
+	return user, nil
+}
+
+func (q *memQueries) GetUser(ctx context.Context, username string) (User, error) {
+	q, end := q.begin()
+	defer end()
+
+	user, ok := q.db.users[username]
+	if !ok {
+		return User{}, ErrRecordNotFound
+	}
+	return user, nil
+}
+
+func (q *memQueries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error) {
+	q, end := q.begin()
+	defer end()
+
+	users := selectRows(q.db.users, func(User) bool { return true }, func(a, b User) int {
+		return cmp.Compare(a.Username, b.Username)
+	})
+	return page(users, arg.Limit, arg.Offset), nil
+}
+
+func (q *memQueries) CreateAccountMember(ctx context.Context, arg CreateAccountMemberParams) (AccountMember, error) {
+	q, end := q.begin()
+	defer end()
+
+	if err := q.checkAccount(arg.AccountID, "account_members", "account_members_account_id_fkey"); err != nil {
+		return AccountMember{}, err
+	}
+	if !slices.Contains([]string{RoleOwner, RoleCoOwner, RoleViewer, RoleLimited}, arg.Role) {
+		return AccountMember{}, constraintError(CheckViolation, "account_members", "account_members_role_check")
+	}
+	if arg.TransferLimit < 0 {
+		return AccountMember{}, constraintError(CheckViolation, "account_members", "account_members_transfer_limit_check")
+	}
+	key := memberKey{accountID: arg.AccountID, username: arg.Username}
+	if _, ok := q.db.members[key]; ok {
+		return AccountMember{}, constraintError(UniqueViolation, "account_members", "account_members_pkey")
+	}
+
+	member := AccountMember{
+		AccountID:     arg.AccountID,
+		Username:      arg.Username,
+		Role:          arg.Role,
+		TransferLimit: arg.TransferLimit,
+		CreatedAt:     q.tx.timestamp(),
+	}
+	put(q, q.db.members, key, member)
+	return member, nil
+}
+
+func (q *memQueries) DeleteAccountMember(ctx context.Context, arg DeleteAccountMemberParams) error {
+	q, end := q.begin()
+	defer end()
+
+	remove(q, q.db.members, memberKey{accountID: arg.AccountID, username: arg.Username})
+	return nil
+}
+
+func (q *memQueries) GetAccountMember(ctx context.Context, arg GetAccountMemberParams) (AccountMember, error) {
+	q, end := q.begin()
+	defer end()
+
+	member, ok := q.db.members[memberKey{accountID: arg.AccountID, username: arg.Username}]
+	if !ok {
+		return AccountMember{}, ErrRecordNotFound
+	}
+	return member, nil
+}
+
+func (q *memQueries) ListAccountMembers(ctx context.Context, accountID int64) ([]AccountMember, error) {
+	q, end := q.begin()
+	defer end()
+
+	return selectRows(q.db.members, func(member AccountMember) bool {
+		return member.AccountID == accountID
+	}, func(a, b AccountMember) int {
+		return cmp.Or(a.CreatedAt.Time.Compare(b.CreatedAt.Time), cmp.Compare(a.Username, b.Username))
+	}), nil
+}
+
+// ListAccountMembersForUpdate needs no row lock, the transaction holds the
+// store.
+func (q *memQueries) ListAccountMembersForUpdate(ctx context.Context, accountID int64) ([]AccountMember, error) {
+	return q.ListAccountMembers(ctx, accountID)
+}
+
+func (q *memQueries) ListMemberAccounts(ctx context.Context, arg ListMemberAccountsParams) ([]Account, error) {
+	q, end := q.begin()
+	defer end()
+
+	accounts := selectRows(q.db.accounts, func(account Account) bool {
+		_, ok := q.db.members[memberKey{accountID: account.ID, username: arg.Username}]
+		return ok
+	}, func(a, b Account) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return page(accounts, arg.Limit, arg.Offset), nil
+}
+
+func (q *memQueries) FreezeAccount(ctx context.Context, arg FreezeAccountParams) (AccountFreeze, error) {
+	q, end := q.begin()
+	defer end()
+
+	if err := q.checkAccount(arg.AccountID, "account_freezes", "account_freezes_account_id_fkey"); err != nil {
+		return AccountFreeze{}, err
+	}
+
+	freeze, ok := q.db.freezes[arg.AccountID]
+	if !ok {
+		freeze = AccountFreeze{AccountID: arg.AccountID, CreatedAt: q.tx.timestamp()}
+	}
+	freeze.Reason = arg.Reason
+	put(q, q.db.freezes, freeze.AccountID, freeze)
+	return freeze, nil
+}
+
+func (q *memQueries) ListAccountFreezes(ctx context.Context, accountIds []int64) ([]AccountFreeze, error) {
+	q, end := q.begin()
+	defer end()
+
+	return selectRows(q.db.freezes, func(freeze AccountFreeze) bool {
+		return slices.Contains(accountIds, freeze.AccountID)
+	}, func(a, b AccountFreeze) int {
+		return cmp.Compare(a.AccountID, b.AccountID)
+	}), nil
+}
+
+func (q *memQueries) UnfreezeAccount(ctx context.Context, accountID int64) error {
+	q, end := q.begin()
+	defer end()
+
+	remove(q, q.db.freezes, accountID)
+	return nil
+}
+
+func (q *memQueries) UpsertRoundUpRule(ctx context.Context, arg UpsertRoundUpRuleParams) (RoundUpRule, error) {
+	q, end := q.begin()
+	defer end()
+
+	if arg.RoundTo <= 1 {
+		return RoundUpRule{}, constraintError(CheckViolation, "round_up_rules", "round_up_rules_round_to_check")
+	}
+	if err := q.checkAccount(arg.AccountID, "round_up_rules", "round_up_rules_account_id_fkey"); err != nil {
+		return RoundUpRule{}, err
+	}
+	if err := q.checkAccount(arg.PotID, "round_up_rules", "round_up_rules_pot_id_fkey"); err != nil {
+		return RoundUpRule{}, err
+	}
+
+	rule, ok := q.db.roundUpRules[arg.AccountID]
+	if !ok {
+		rule = RoundUpRule{AccountID: arg.AccountID, CreatedAt: q.tx.timestamp()}
+	}
+	rule.PotID = arg.PotID
+	rule.RoundTo = arg.RoundTo
+	put(q, q.db.roundUpRules, rule.AccountID, rule)
+	return rule, nil
+}
+
+func (q *memQueries) GetRoundUpRule(ctx context.Context, accountID int64) (RoundUpRule, error) {
+	q, end := q.begin()
+	defer end()
+
+	rule, ok := q.db.roundUpRules[accountID]
+	if !ok {
+		return RoundUpRule{}, ErrRecordNotFound
+	}
+	return rule, nil
+}
+
+func (q *memQueries) DeleteRoundUpRule(ctx context.Context, accountID int64) error {
+	q, end := q.begin()
+	defer end()
+
+	remove(q, q.db.roundUpRules, accountID)
+	return nil
+}
+
+func (q *memQueries) CreateBeneficiary(ctx context.Context, arg CreateBeneficiaryParams) (Beneficiary, error) {
+	q, end := q.begin()
+	defer end()
+
+	if err := q.checkAccount(arg.AccountID, "beneficiaries", "beneficiaries_account_id_fkey"); err != nil {
+		return Beneficiary{}, err
+	}
+	for _, beneficiary := range q.db.beneficiaries {
+		if beneficiary.Owner == arg.Owner && beneficiary.AccountID == arg.AccountID {
+			return Beneficiary{}, constraintError(UniqueViolation, "beneficiaries", "beneficiaries_owner_account_id_idx")
+		}
+	}
+
+	beneficiary := Beneficiary{
+		ID:        q.db.nextID("beneficiaries"),
+		Owner:     arg.Owner,
+		Nickname:  arg.Nickname,
+		AccountID: arg.AccountID,
+		CreatedAt: q.tx.timestamp(),
+	}
+	put(q, q.db.beneficiaries, beneficiary.ID, beneficiary)
+	return beneficiary, nil
+}
+
+func (q *memQueries) DeleteBeneficiary(ctx context.Context, id int64) error {
+	q, end := q.begin()
+	defer end()
+
+	remove(q, q.db.beneficiaries, id)
+	return nil
+}
+
+func (q *memQueries) GetBeneficiary(ctx context.Context, id int64) (Beneficiary, error) {
+	q, end := q.begin()
+	defer end()
+
+	beneficiary, ok := q.db.beneficiaries[id]
+	if !ok {
+		return Beneficiary{}, ErrRecordNotFound
+	}
+	return beneficiary, nil
+}
+
+func (q *memQueries) ListBeneficiaries(ctx context.Context, arg ListBeneficiariesParams) ([]Beneficiary, error) {
+	q, end := q.begin()
+	defer end()
+
+	beneficiaries := selectRows(q.db.beneficiaries, func(beneficiary Beneficiary) bool {
+		return beneficiary.Owner == arg.Owner
+	}, func(a, b Beneficiary) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return page(beneficiaries, arg.Limit, arg.Offset), nil
+}
+
+func (q *memQueries) UpdateBeneficiaryNickname(ctx context.Context, arg UpdateBeneficiaryNicknameParams) (Beneficiary, error) {
+	q, end := q.begin()
+	defer end()
+
+	beneficiary, ok := q.db.beneficiaries[arg.ID]
+	if !ok {
+		return Beneficiary{}, ErrRecordNotFound
+	}
+	beneficiary.Nickname = arg.Nickname
+	put(q, q.db.beneficiaries, beneficiary.ID, beneficiary)
+	return beneficiary, nil
+}
+
+func (q *memQueries) VerifyBeneficiary(ctx context.Context, id int64) (Beneficiary, error) {
+	q, end := q.begin()
+	defer end()
+
+	beneficiary, ok := q.db.beneficiaries[id]
+	if !ok {
+		return Beneficiary{}, ErrRecordNotFound
+	}
+	beneficiary.Verified = true
+	put(q, q.db.beneficiaries, beneficiary.ID, beneficiary)
+	return beneficiary, nil
+}
+
+func (q *memQueries) UpsertApprovalPolicy(ctx context.Context, arg UpsertApprovalPolicyParams) (ApprovalPolicy, error) {
+	q, end := q.begin()
+	defer end()
+
+	if arg.Threshold < 0 {
+		return ApprovalPolicy{}, constraintError(CheckViolation, "approval_policies", "approval_policies_threshold_check")
+	}
+	if arg.RequiredApprovals <= 0 {
+		return ApprovalPolicy{}, constraintError(CheckViolation, "approval_policies", "approval_policies_required_approvals_check")
+	}
+	if err := q.checkAccount(arg.AccountID, "approval_policies", "approval_policies_account_id_fkey"); err != nil {
+		return ApprovalPolicy{}, err
+	}
+
+	policy, ok := q.db.policies[arg.AccountID]
+	if !ok {
+		policy = ApprovalPolicy{AccountID: arg.AccountID, CreatedAt: q.tx.timestamp()}
+	}
+	policy.Threshold = arg.Threshold
+	policy.RequiredApprovals = arg.RequiredApprovals
+	policy.Approvers = slices.Clone(arg.Approvers)
+	put(q, q.db.policies, policy.AccountID, policy)
+	return policy, nil
+}
+
+func (q *memQueries) GetApprovalPolicy(ctx context.Context, accountID int64) (ApprovalPolicy, error) {
+	q, end := q.begin()
+	defer end()
+
+	policy, ok := q.db.policies[accountID]
+	if !ok {
+		return ApprovalPolicy{}, ErrRecordNotFound
+	}
+	policy.Approvers = slices.Clone(policy.Approvers)
+	return policy, nil
+}
+
+func (q *memQueries) DeleteApprovalPolicy(ctx context.Context, accountID int64) error {
+	q, end := q.begin()
+	defer end()
+
+	remove(q, q.db.policies, accountID)
+	return nil
+}
+
+func (q *memQueries) CreateApprovalRequest(ctx context.Context, arg CreateApprovalRequestParams) (ApprovalRequest, error) {
+	q, end := q.begin()
+	defer end()
+
+	if err := q.checkAccount(arg.FromAccountID, "approval_requests", "approval_requests_from_account_id_fkey"); err != nil {
+		return ApprovalRequest{}, err
+	}
+	if err := q.checkAccount(arg.ToAccountID, "approval_requests", "approval_requests_to_account_id_fkey"); err != nil {
+		return ApprovalRequest{}, err
+	}
+
+	request := ApprovalRequest{
+		ID:            q.db.nextID("approval_requests"),
+		FromAccountID: arg.FromAccountID,
+		ToAccountID:   arg.ToAccountID,
+		Amount:        arg.Amount,
+		RequestedBy:   arg.RequestedBy,
+		Status:        ApprovalPending,
+		CreatedAt:     q.tx.timestamp(),
+	}
+	put(q, q.db.requests, request.ID, request)
+	return request, nil
+}
+
+func (q *memQueries) GetApprovalRequest(ctx context.Context, id int64) (ApprovalRequest, error) {
+	q, end := q.begin()
+	defer end()
+
+	request, ok := q.db.requests[id]
+	if !ok {
+		return ApprovalRequest{}, ErrRecordNotFound
+	}
+	return request, nil
+}
+
+// GetApprovalRequestForUpdate needs no row lock, the transaction holds the
+// store.
+func (q *memQueries) GetApprovalRequestForUpdate(ctx context.Context, id int64) (ApprovalRequest, error) {
+	return q.GetApprovalRequest(ctx, id)
+}
+
+func (q *memQueries) CancelApprovalRequest(ctx context.Context, id int64) (ApprovalRequest, error) {
+	q, end := q.begin()
+	defer end()
+
+	request, ok := q.db.requests[id]
+	if !ok || request.Status != ApprovalPending {
+		return ApprovalRequest{}, ErrRecordNotFound
+	}
+
+	request.Status = ApprovalCancelled
+	put(q, q.db.requests, request.ID, request)
+	return request, nil
+}
+
+func (q *memQueries) UpdateApprovalRequestStatus(ctx context.Context, arg UpdateApprovalRequestStatusParams) (ApprovalRequest, error) {
+	q, end := q.begin()
+	defer end()
+
+	request, ok := q.db.requests[arg.ID]
+	if !ok {
+		return ApprovalRequest{}, ErrRecordNotFound
+	}
+	if !slices.Contains([]string{ApprovalPending, ApprovalApproved, ApprovalRejected, ApprovalCancelled}, arg.Status) {
+		return ApprovalRequest{}, constraintError(CheckViolation, "approval_requests", "approval_requests_status_check")
+	}
+	if arg.TransferID.Valid {
+		if _, ok := q.db.transfers[arg.TransferID.Int64]; !ok {
+			return ApprovalRequest{}, constraintError(ForeignKeyViolation, "approval_requests", "approval_requests_transfer_id_fkey")
+		}
+	}
+
+	request.Status = arg.Status
+	request.TransferID = arg.TransferID
+	put(q, q.db.requests, request.ID, request)
+	return request, nil
+}
+
+func (q *memQueries) CreateApprovalDecision(ctx context.Context, arg CreateApprovalDecisionParams) (ApprovalDecision, error) {
+	q, end := q.begin()
+	defer end()
+
+	if _, ok := q.db.requests[arg.RequestID]; !ok {
+		return ApprovalDecision{}, constraintError(ForeignKeyViolation, "approval_decisions", "approval_decisions_request_id_fkey")
+	}
+	for _, decision := range q.db.decisions {
+		if decision.RequestID == arg.RequestID && decision.Approver == arg.Approver {
+			return ApprovalDecision{}, constraintError(UniqueViolation, "approval_decisions", "approval_decisions_request_id_approver_idx")
+		}
+	}
+
+	decision := ApprovalDecision{
+		ID:        q.db.nextID("approval_decisions"),
+		RequestID: arg.RequestID,
+		Approver:  arg.Approver,
+		Approved:  arg.Approved,
+		CreatedAt: q.tx.timestamp(),
+	}
+	put(q, q.db.decisions, decision.ID, decision)
+	return decision, nil
+}
+
+func (q *memQueries) ListApprovalDecisions(ctx context.Context, requestID int64) ([]ApprovalDecision, error) {
+	q, end := q.begin()
+	defer end()
+
+	return selectRows(q.db.decisions, func(decision ApprovalDecision) bool {
+		return decision.RequestID == requestID
+	}, func(a, b ApprovalDecision) int {
+		return cmp.Compare(a.ID, b.ID)
+	}), nil
+}
+
+func (q *memQueries) CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) (Outbox, error) {
+	q, end := q.begin()
+	defer end()
+
+	event := Outbox{
+		ID:            q.db.nextID("outbox"),
+		Txid:          q.tx.id,
+		AggregateType: arg.AggregateType,
+		AggregateID:   arg.AggregateID,
+		EventType:     arg.EventType,
+		Payload:       slices.Clone(arg.Payload),
+		CreatedAt:     q.tx.timestamp(),
+	}
+	put(q, q.db.outbox, event.ID, event)
+	return event, nil
+}
+
+// ListOutboxEvents only returns events of committed transactions. As
+// transactions run one at a time, these are the ones before the current.
+func (q *memQueries) ListOutboxEvents(ctx context.Context, arg ListOutboxEventsParams) ([]Outbox, error) {
+	q, end := q.begin()
+	defer end()
+
+	events := selectRows(q.db.outbox, func(event Outbox) bool {
+		after := event.Txid > arg.Txid || (event.Txid == arg.Txid && event.ID > arg.EventID)
+		return after && event.Txid < q.tx.id
+	}, func(a, b Outbox) int {
+		return cmp.Or(cmp.Compare(a.Txid, b.Txid), cmp.Compare(a.ID, b.ID))
+	})
+	return page(events, arg.Limit, 0), nil
+}
+
+func (q *memQueries) GetOutboxOffset(ctx context.Context, consumer string) (OutboxOffset, error) {
+	q, end := q.begin()
+	defer end()
+
+	offset, ok := q.db.offsets[consumer]
+	if !ok {
+		return OutboxOffset{}, ErrRecordNotFound
+	}
+	return offset, nil
+}
+
+func (q *memQueries) UpsertOutboxOffset(ctx context.Context, arg UpsertOutboxOffsetParams) error {
+	q, end := q.begin()
+	defer end()
+
+	put(q, q.db.offsets, arg.Consumer, OutboxOffset{
+		Consumer:  arg.Consumer,
+		Txid:      arg.Txid,
+		EventID:   arg.EventID,
+		UpdatedAt: q.tx.timestamp(),
+	})
+	return nil
+}
+
+// NotifyAccountUpdate drops the notification, a MemoryStore has no
+// listeners.
+func (q *memQueries) NotifyAccountUpdate(ctx context.Context, payload string) error {
+	return nil
+}
+
+func (q *memQueries) CreateWebhook(ctx context.Context, arg CreateWebhookParams) (Webhook, error) {
+	q, end := q.begin()
+	defer end()
+
+	webhook := Webhook{
+		ID:         q.db.nextID("webhooks"),
+		Owner:      arg.Owner,
+		Url:        arg.Url,
+		EventTypes: slices.Clone(arg.EventTypes),
+		Secret:     arg.Secret,
+		CreatedAt:  q.tx.timestamp(),
+	}
+	put(q, q.db.webhooks, webhook.ID, webhook)
+	return webhook, nil
+}
+
+func (q *memQueries) DeleteWebhook(ctx context.Context, id int64) error {
+	q, end := q.begin()
+	defer end()
+
+	for key, delivery := range q.db.deliveries {
+		if delivery.WebhookID == id {
+			remove(q, q.db.deliveries, key)
+		}
+	}
+	remove(q, q.db.webhooks, id)
+	return nil
+}
+
+func (q *memQueries) GetWebhook(ctx context.Context, id int64) (Webhook, error) {
+	q, end := q.begin()
+	defer end()
+
+	webhook, ok := q.db.webhooks[id]
+	if !ok {
+		return Webhook{}, ErrRecordNotFound
+	}
+	return webhook, nil
+}
+
+func (q *memQueries) ListWebhooks(ctx context.Context, arg ListWebhooksParams) ([]Webhook, error) {
+	q, end := q.begin()
+	defer end()
+
+	webhooks := selectRows(q.db.webhooks, func(webhook Webhook) bool {
+		return webhook.Owner == arg.Owner
+	}, func(a, b Webhook) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return page(webhooks, arg.Limit, arg.Offset), nil
+}
+
+func (q *memQueries) ListWebhooksForEvent(ctx context.Context, arg ListWebhooksForEventParams) ([]Webhook, error) {
+	q, end := q.begin()
+	defer end()
+
+	return selectRows(q.db.webhooks, func(webhook Webhook) bool {
+		if webhook.Disabled || !slices.Contains(webhook.EventTypes, arg.EventType) {
+			return false
+		}
+		for _, accountID := range arg.AccountIds {
+			if _, ok := q.db.members[memberKey{accountID: accountID, username: webhook.Owner}]; ok {
+				return true
+			}
+		}
+		return false
+	}, func(a, b Webhook) int {
+		return cmp.Compare(a.ID, b.ID)
+	}), nil
+}
+
+func (q *memQueries) EnableWebhook(ctx context.Context, id int64) (Webhook, error) {
+	q, end := q.begin()
+	defer end()
+
+	webhook, ok := q.db.webhooks[id]
+	if !ok {
+		return Webhook{}, ErrRecordNotFound
+	}
+	webhook.Disabled = false
+	webhook.ConsecutiveFailures = 0
+	put(q, q.db.webhooks, webhook.ID, webhook)
+	return webhook, nil
+}
+
+func (q *memQueries) RecordWebhookFailure(ctx context.Context, arg RecordWebhookFailureParams) (Webhook, error) {
+	q, end := q.begin()
+	defer end()
+
+	webhook, ok := q.db.webhooks[arg.ID]
+	if !ok {
+		return Webhook{}, ErrRecordNotFound
+	}
+	webhook.ConsecutiveFailures++
+	webhook.Disabled = webhook.Disabled || webhook.ConsecutiveFailures >= arg.MaxFailures
+	put(q, q.db.webhooks, webhook.ID, webhook)
+	return webhook, nil
+}
+
+func (q *memQueries) RecordWebhookSuccess(ctx context.Context, id int64) error {
+	q, end := q.begin()
+	defer end()
+
+	webhook, ok := q.db.webhooks[id]
+	if !ok {
+		return nil
+	}
+	webhook.ConsecutiveFailures = 0
+	put(q, q.db.webhooks, webhook.ID, webhook)
+	return nil
+}
+
+func (q *memQueries) CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) error {
+	q, end := q.begin()
+	defer end()
+
+	if _, ok := q.db.webhooks[arg.WebhookID]; !ok {
+		return constraintError(ForeignKeyViolation, "webhook_deliveries", "webhook_deliveries_webhook_id_fkey")
+	}
+	for _, delivery := range q.db.deliveries {
+		if delivery.WebhookID == arg.WebhookID && delivery.EventID == arg.EventID {
+			return nil
+		}
+	}
+
+	delivery := WebhookDelivery{
+		ID:            q.db.nextID("webhook_deliveries"),
+		WebhookID:     arg.WebhookID,
+		EventID:       arg.EventID,
+		EventType:     arg.EventType,
+		Payload:       slices.Clone(arg.Payload),
+		Status:        "pending",
+		NextAttemptAt: q.tx.timestamp(),
+		CreatedAt:     q.tx.timestamp(),
+	}
+	put(q, q.db.deliveries, delivery.ID, delivery)
+	return nil
+}
+
+func (q *memQueries) GetWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error) {
+	q, end := q.begin()
+	defer end()
+
+	delivery, ok := q.db.deliveries[id]
+	if !ok {
+		return WebhookDelivery{}, ErrRecordNotFound
+	}
+	return delivery, nil
+}
+
+func (q *memQueries) ClaimDueWebhookDeliveries(ctx context.Context, arg ClaimDueWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	q, end := q.begin()
+	defer end()
+
+	deliveries := selectRows(q.db.deliveries, func(delivery WebhookDelivery) bool {
+		webhook, ok := q.db.webhooks[delivery.WebhookID]
+		return ok && !webhook.Disabled && delivery.Status == "pending" && !delivery.NextAttemptAt.Time.After(q.tx.now)
+	}, func(a, b WebhookDelivery) int {
+		return cmp.Or(a.NextAttemptAt.Time.Compare(b.NextAttemptAt.Time), cmp.Compare(a.ID, b.ID))
+	})
+	claimed := page(deliveries, arg.BatchSize, 0)
+	for i := range claimed {
+		claimed[i].NextAttemptAt = pgtype.Timestamp{
+			Time:  q.tx.now.Add(time.Duration(arg.LeaseSeconds) * time.Second),
+			Valid: true,
+		}
+		put(q, q.db.deliveries, claimed[i].ID, claimed[i])
+	}
+	return claimed, nil
+}
+
+func (q *memQueries) ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	q, end := q.begin()
+	defer end()
+
+	deliveries := selectRows(q.db.deliveries, func(delivery WebhookDelivery) bool {
+		return delivery.WebhookID == arg.WebhookID
+	}, func(a, b WebhookDelivery) int {
+		return cmp.Compare(b.ID, a.ID)
+	})
+	return page(deliveries, arg.Limit, arg.Offset), nil
+}
+
+func (q *memQueries) MarkWebhookDeliveryDelivered(ctx context.Context, id int64) error {
+	q, end := q.begin()
+	defer end()
+
+	delivery, ok := q.db.deliveries[id]
+	if !ok {
+		return nil
+	}
+	delivery.Status = "delivered"
+	delivery.Attempts++
+	delivery.LastError = pgtype.Text{}
+	delivery.DeliveredAt = q.tx.timestamp()
+	put(q, q.db.deliveries, delivery.ID, delivery)
+	return nil
+}
+
+func (q *memQueries) MarkWebhookDeliveryFailed(ctx context.Context, arg MarkWebhookDeliveryFailedParams) error {
+	q, end := q.begin()
+	defer end()
+
+	delivery, ok := q.db.deliveries[arg.ID]
+	if !ok {
+		return nil
+	}
+	delivery.Status = arg.Status
+	delivery.Attempts++
+	delivery.LastError = arg.LastError
+	delivery.NextAttemptAt = pgtype.Timestamp{
+		Time:  q.tx.now.Add(time.Duration(arg.BackoffSeconds) * time.Second),
+		Valid: true,
+	}
+	put(q, q.db.deliveries, delivery.ID, delivery)
+	return nil
+}
+
+func (q *memQueries) ReplayWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error) {
+	q, end := q.begin()
+	defer end()
+
+	delivery, ok := q.db.deliveries[id]
+	if !ok {
+		return WebhookDelivery{}, ErrRecordNotFound
+	}
+	delivery.Status = "pending"
+	delivery.NextAttemptAt = q.tx.timestamp()
+	put(q, q.db.deliveries, delivery.ID, delivery)
+	return delivery, nil
+}
+
+func (q *memQueries) TakeRateLimitToken(ctx context.Context, arg TakeRateLimitTokenParams) (RateLimitBucket, error) {
+	q, end := q.begin()
+	defer end()
+
+	bucket, ok := q.db.buckets[arg.Key]
+	if !ok {
+		bucket = RateLimitBucket{Key: arg.Key, Tokens: arg.Burst - 1, Allowed: true}
+	} else {
+		elapsed := q.tx.now.Sub(bucket.UpdatedAt.Time).Seconds()
+		tokens := min(arg.Burst, bucket.Tokens+arg.Rate*elapsed)
+		bucket.Allowed = tokens >= 1
+		if bucket.Allowed {
+			tokens--
+		}
+		bucket.Tokens = tokens
+	}
+	bucket.UpdatedAt = q.tx.timestamp()
+	put(q, q.db.buckets, bucket.Key, bucket)
+	return bucket, nil
+}
+
+func (q *memQueries) DeleteIdleRateLimitBuckets(ctx context.Context, maxIdleSeconds int32) error {
+	q, end := q.begin()
+	defer end()
+
+	idleSince := q.tx.now.Add(-time.Duration(maxIdleSeconds) * time.Second)
+	for key, bucket := range q.db.buckets {
+		if bucket.UpdatedAt.Time.Before(idleSince) {
+			remove(q, q.db.buckets, key)
+		}
+	}
+	return nil
+}
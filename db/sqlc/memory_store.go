@@ -0,0 +1,288 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// MemoryStore is a Store that keeps its data in memory, for tests and tools
+// that should not need Postgres. It enforces the constraints of the schema
+// with the same error codes and runs the same transaction steps as
+// SQLStore, so both behave alike; db/storetest checks that they do.
+//
+// Transactions hold one lock over the whole store instead of row locks, so
+// they never deadlock or need a retry, and a failed transaction undoes its
+// changes. Notifications on AccountUpdateChannel are dropped.
+type MemoryStore struct {
+	*memQueries
+	logger *slog.Logger
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		memQueries: &memQueries{db: newMemDB()},
+		logger:     slog.Default(),
+	}
+}
+
+// SetLogger makes the store log the steps of its transactions to logger.
+func (s *MemoryStore) SetLogger(logger *slog.Logger) {
+	s.logger = logger
+}
+
+// execTx runs fn within a transaction, which is rolled back when fn fails.
+func (s *MemoryStore) execTx(ctx context.Context, fn func(Querier) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	tx := s.db.newTx()
+	err := fn(&memQueries{db: s.db, tx: tx})
+	if err != nil {
+		tx.rollback()
+	}
+	return err
+}
+
+func (s *MemoryStore) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+
+	err := s.execTx(ctx, func(q Querier) error {
+		var err error
+		result, err = transfer(ctx, q, s.logger, arg)
+		if err != nil {
+			return err
+		}
+
+		return sweepRoundUp(ctx, q, s.logger, &result)
+	})
+
+	return result, err
+}
+
+func (s *MemoryStore) PotTransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+
+	err := s.execTx(ctx, func(q Querier) error {
+		var err error
+		result, err = transfer(ctx, q, s.logger, arg)
+		return err
+	})
+
+	return result, err
+}
+
+func (s *MemoryStore) CreateAccountTx(ctx context.Context, arg CreateAccountParams, opts ...CreateAccountOption) (CreateAccountTxResult, error) {
+	return createAccountAttempts(arg, opts, func(arg CreateAccountParams) (CreateAccountTxResult, error) {
+		var result CreateAccountTxResult
+
+		err := s.execTx(ctx, func(q Querier) error {
+			var err error
+			result, err = createAccountWithOwner(ctx, q, arg)
+			return err
+		})
+
+		return result, err
+	})
+}
+
+func (s *MemoryStore) ApprovalDecisionTx(ctx context.Context, arg ApprovalDecisionTxParams) (ApprovalDecisionTxResult, error) {
+	var result ApprovalDecisionTxResult
+
+	err := s.execTx(ctx, func(q Querier) error {
+		var err error
+		result, err = decideApproval(ctx, q, s.logger, arg)
+		return err
+	})
+
+	return result, err
+}
+
+func (s *MemoryStore) RemoveMemberTx(ctx context.Context, arg DeleteAccountMemberParams) error {
+	return s.execTx(ctx, func(q Querier) error {
+		return removeMember(ctx, q, arg)
+	})
+}
+
+// memberKey is the primary key of account_members.
+type memberKey struct {
+	accountID int64
+	username  string
+}
+
+// memDB holds the tables of a MemoryStore, keyed by their primary key.
+type memDB struct {
+	mu        sync.Mutex
+	sequences map[string]int64
+	lastTxID  int64
+
+	accounts      map[int64]Account
+	entries       map[int64]Entry
+	transfers     map[int64]Transfer
+	users         map[string]User
+	members       map[memberKey]AccountMember
+	freezes       map[int64]AccountFreeze
+	roundUpRules  map[int64]RoundUpRule
+	beneficiaries map[int64]Beneficiary
+	policies      map[int64]ApprovalPolicy
+	requests      map[int64]ApprovalRequest
+	decisions     map[int64]ApprovalDecision
+	outbox        map[int64]Outbox
+	offsets       map[string]OutboxOffset
+	webhooks      map[int64]Webhook
+	deliveries    map[int64]WebhookDelivery
+	buckets       map[string]RateLimitBucket
+}
+
+func newMemDB() *memDB {
+	return &memDB{
+		sequences:     make(map[string]int64),
+		accounts:      make(map[int64]Account),
+		entries:       make(map[int64]Entry),
+		transfers:     make(map[int64]Transfer),
+		users:         make(map[string]User),
+		members:       make(map[memberKey]AccountMember),
+		freezes:       make(map[int64]AccountFreeze),
+		roundUpRules:  make(map[int64]RoundUpRule),
+		beneficiaries: make(map[int64]Beneficiary),
+		policies:      make(map[int64]ApprovalPolicy),
+		requests:      make(map[int64]ApprovalRequest),
+		decisions:     make(map[int64]ApprovalDecision),
+		outbox:        make(map[int64]Outbox),
+		offsets:       make(map[string]OutboxOffset),
+		webhooks:      make(map[int64]Webhook),
+		deliveries:    make(map[int64]WebhookDelivery),
+		buckets:       make(map[string]RateLimitBucket),
+	}
+}
+
+// nextID returns the next value of the id sequence of table. Like in
+// Postgres, values taken by a rolled back transaction are not reused.
+func (db *memDB) nextID(table string) int64 {
+	db.sequences[table]++
+	return db.sequences[table]
+}
+
+// memTx is a transaction on a memDB. It holds the lock of the database, so
+// its changes are made in place and undone on rollback.
+type memTx struct {
+	id int64
+	// now is the start of the transaction, which now() returns in Postgres.
+	now  time.Time
+	undo []func()
+}
+
+func (db *memDB) newTx() *memTx {
+	db.lastTxID++
+	return &memTx{
+		id:  db.lastTxID,
+		now: time.Now().UTC().Truncate(time.Microsecond),
+	}
+}
+
+func (tx *memTx) rollback() {
+	for i := len(tx.undo) - 1; i >= 0; i-- {
+		tx.undo[i]()
+	}
+	tx.undo = nil
+}
+
+// timestamp returns the time at which the transaction started.
+func (tx *memTx) timestamp() pgtype.Timestamp {
+	return pgtype.Timestamp{Time: tx.now, Valid: true}
+}
+
+// memQueries runs the queries of Querier on a memDB.
+type memQueries struct {
+	db *memDB
+	tx *memTx // nil outside of a transaction
+}
+
+// begin returns the queries to run a statement with. Outside of a
+// transaction the statement gets a transaction of its own, which holds the
+// lock until end is called.
+func (q *memQueries) begin() (tq *memQueries, end func()) {
+	if q.tx != nil {
+		return q, func() {}
+	}
+
+	q.db.mu.Lock()
+	return &memQueries{db: q.db, tx: q.db.newTx()}, q.db.mu.Unlock
+}
+
+// put inserts or replaces the row of table at key.
+func put[K comparable, V any](q *memQueries, table map[K]V, key K, row V) {
+	old, existed := table[key]
+	q.tx.undo = append(q.tx.undo, func() {
+		if existed {
+			table[key] = old
+		} else {
+			delete(table, key)
+		}
+	})
+	table[key] = row
+}
+
+// remove deletes the row of table at key, if any.
+func remove[K comparable, V any](q *memQueries, table map[K]V, key K) {
+	old, existed := table[key]
+	if !existed {
+		return
+	}
+	q.tx.undo = append(q.tx.undo, func() {
+		table[key] = old
+	})
+	delete(table, key)
+}
+
+// selectRows returns the rows of table that match where, ordered by compare.
+func selectRows[K comparable, V any](table map[K]V, where func(V) bool, compare func(a, b V) int) []V {
+	rows := []V{}
+	for _, row := range table {
+		if where(row) {
+			rows = append(rows, row)
+		}
+	}
+	slices.SortFunc(rows, compare)
+	return rows
+}
+
+// page applies limit and offset to rows.
+func page[V any](rows []V, limit, offset int32) []V {
+	rows = rows[min(max(int(offset), 0), len(rows)):]
+	return rows[:min(max(int(limit), 0), len(rows))]
+}
+
+// constraintError is the error Postgres returns when a statement violates
+// a constraint of table.
+func constraintError(code, table, constraint string) error {
+	var kind string
+	switch code {
+	case UniqueViolation:
+		kind = "unique"
+	case ForeignKeyViolation:
+		kind = "foreign key"
+	case CheckViolation:
+		kind = "check"
+	}
+
+	return &pgconn.PgError{
+		Severity:       "ERROR",
+		Code:           code,
+		Message:        fmt.Sprintf("statement on table %q violates %s constraint %q", table, kind, constraint),
+		TableName:      table,
+		ConstraintName: constraint,
+	}
+}
@@ -14,6 +14,67 @@ type Account struct {
 	Balance   int64            `json:"balance"`
 	Currency  string           `json:"currency"`
 	CreatedAt pgtype.Timestamp `json:"created_at"`
+	// Set for savings pots, points to the parent account
+	ParentID pgtype.Int8 `json:"parent_id"`
+	// IBAN-style external identifier
+	AccountNumber string `json:"account_number"`
+}
+
+// Frozen accounts can neither send nor receive money
+type AccountFreeze struct {
+	AccountID int64            `json:"account_id"`
+	Reason    string           `json:"reason"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type AccountMember struct {
+	AccountID int64  `json:"account_id"`
+	Username  string `json:"username"`
+	// owner, co_owner, viewer or limited
+	Role string `json:"role"`
+	// Largest single transfer a limited member may make
+	TransferLimit int64            `json:"transfer_limit"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+}
+
+type ApprovalDecision struct {
+	ID        int64            `json:"id"`
+	RequestID int64            `json:"request_id"`
+	Approver  string           `json:"approver"`
+	Approved  bool             `json:"approved"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type ApprovalPolicy struct {
+	AccountID int64 `json:"account_id"`
+	// Transfers above this amount need approval
+	Threshold         int64            `json:"threshold"`
+	RequiredApprovals int32            `json:"required_approvals"`
+	Approvers         []string         `json:"approvers"`
+	CreatedAt         pgtype.Timestamp `json:"created_at"`
+}
+
+type ApprovalRequest struct {
+	ID            int64 `json:"id"`
+	FromAccountID int64 `json:"from_account_id"`
+	ToAccountID   int64 `json:"to_account_id"`
+	Amount        int64 `json:"amount"`
+	// Maker of the transfer, who may not approve it
+	RequestedBy string `json:"requested_by"`
+	// pending, approved, rejected or cancelled
+	Status     string           `json:"status"`
+	TransferID pgtype.Int8      `json:"transfer_id"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+}
+
+type Beneficiary struct {
+	ID        int64  `json:"id"`
+	Owner     string `json:"owner"`
+	Nickname  string `json:"nickname"`
+	AccountID int64  `json:"account_id"`
+	// An operator confirmed the payee with gobank beneficiary verify
+	Verified  bool             `json:"verified"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
 }
 
 type Entry struct {
@@ -22,6 +83,44 @@ type Entry struct {
 	// It can be negative or positive
 	Amount    int64            `json:"amount"`
 	CreatedAt pgtype.Timestamp `json:"created_at"`
+	// Opening entries book the balance an account was created with
+	Opening bool `json:"opening"`
+}
+
+type Outbox struct {
+	ID int64 `json:"id"`
+	// Transaction that wrote the event, events are relayed in (txid, id) order
+	Txid          int64            `json:"txid"`
+	AggregateType string           `json:"aggregate_type"`
+	AggregateID   int64            `json:"aggregate_id"`
+	EventType     string           `json:"event_type"`
+	Payload       []byte           `json:"payload"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+}
+
+type OutboxOffset struct {
+	Consumer string `json:"consumer"`
+	Txid     int64  `json:"txid"`
+	// Last event published to the consumer
+	EventID   int64            `json:"event_id"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
+type RateLimitBucket struct {
+	Key string `json:"key"`
+	// Tokens left in the bucket at updated_at
+	Tokens float64 `json:"tokens"`
+	// Whether the last request took a token
+	Allowed   bool             `json:"allowed"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
+type RoundUpRule struct {
+	AccountID int64 `json:"account_id"`
+	PotID     int64 `json:"pot_id"`
+	// Outgoing transfers are rounded up to a multiple of this amount
+	RoundTo   int64            `json:"round_to"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
 }
 
 type Transfer struct {
@@ -32,3 +131,39 @@ type Transfer struct {
 	Amount    int64            `json:"amount"`
 	CreatedAt pgtype.Timestamp `json:"created_at"`
 }
+
+// Account owners and members are referenced by username
+type User struct {
+	Username  string           `json:"username"`
+	FullName  string           `json:"full_name"`
+	Email     string           `json:"email"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type Webhook struct {
+	ID         int64    `json:"id"`
+	Owner      string   `json:"owner"`
+	Url        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	// Key of the HMAC-SHA256 signature sent with every delivery
+	Secret   string `json:"secret"`
+	Disabled bool   `json:"disabled"`
+	// Failed attempts since the last successful delivery
+	ConsecutiveFailures int32            `json:"consecutive_failures"`
+	CreatedAt           pgtype.Timestamp `json:"created_at"`
+}
+
+type WebhookDelivery struct {
+	ID        int64  `json:"id"`
+	WebhookID int64  `json:"webhook_id"`
+	EventID   int64  `json:"event_id"`
+	EventType string `json:"event_type"`
+	Payload   []byte `json:"payload"`
+	// pending, delivered or failed
+	Status        string           `json:"status"`
+	Attempts      int32            `json:"attempts"`
+	NextAttemptAt pgtype.Timestamp `json:"next_attempt_at"`
+	LastError     pgtype.Text      `json:"last_error"`
+	DeliveredAt   pgtype.Timestamp `json:"delivered_at"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+}
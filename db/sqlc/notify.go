@@ -0,0 +1,34 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// AccountUpdateChannel is the channel NotifyAccountUpdate notifies on.
+const AccountUpdateChannel = "account_updates"
+
+// AccountUpdate is the payload notified on AccountUpdateChannel whenever an
+// entry changes the balance of an account.
+type AccountUpdate struct {
+	AccountID int64 `json:"account_id"`
+	// Balance is the balance of the account right after the entry.
+	Balance int64 `json:"balance"`
+	Entry   Entry `json:"entry"`
+}
+
+// notifyEntry tells listeners about an entry made on account. Like
+// the rest of the transaction q runs in, the notification is only delivered
+// once it commits.
+func notifyEntry(ctx context.Context, q Querier, account Account, entry Entry) error {
+	payload, err := json.Marshal(AccountUpdate{
+		AccountID: account.ID,
+		Balance:   account.Balance,
+		Entry:     entry,
+	})
+	if err != nil {
+		return err
+	}
+
+	return q.NotifyAccountUpdate(ctx, string(payload))
+}
@@ -0,0 +1,39 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Aggregates and types of the domain events written to the outbox.
+const (
+	AggregateAccount  = "account"
+	AggregateTransfer = "transfer"
+
+	EventAccountCreated    = "account.created"
+	EventTransferCompleted = "transfer.completed"
+)
+
+// TransferCompletedEvent is the payload of a transfer.completed event.
+type TransferCompletedEvent struct {
+	Transfer           Transfer `json:"transfer"`
+	FromAccountBalance int64    `json:"from_account_balance"`
+	ToAccountBalance   int64    `json:"to_account_balance"`
+}
+
+// emitEvent writes a domain event to the outbox as part of the transaction
+// q runs in, so the event is published if and only if the transaction commits.
+func emitEvent(ctx context.Context, q Querier, aggregateType string, aggregateID int64, eventType string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CreateOutboxEvent(ctx, CreateOutboxEventParams{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       data,
+	})
+	return err
+}
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func getOutboxEvent(t *testing.T, aggregateType string, aggregateID int64) Outbox {
+	var event Outbox
+	err := testDB.QueryRow(context.Background(),
+		"SELECT id, txid, aggregate_type, aggregate_id, event_type, payload, created_at FROM outbox WHERE aggregate_type = $1 AND aggregate_id = $2",
+		aggregateType, aggregateID,
+	).Scan(
+		&event.ID,
+		&event.Txid,
+		&event.AggregateType,
+		&event.AggregateID,
+		&event.EventType,
+		&event.Payload,
+		&event.CreatedAt,
+	)
+	require.NoError(t, err)
+	return event
+}
+
+func TestTransferTxWritesOutbox(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	event := getOutboxEvent(t, AggregateTransfer, result.Transfer.ID)
+	require.Equal(t, EventTransferCompleted, event.EventType)
+
+	var payload TransferCompletedEvent
+	require.NoError(t, json.Unmarshal(event.Payload, &payload))
+	require.Equal(t, result.Transfer.ID, payload.Transfer.ID)
+	require.Equal(t, result.FromAccount.Balance, payload.FromAccountBalance)
+	require.Equal(t, result.ToAccount.Balance, payload.ToAccountBalance)
+}
+
+func TestOutboxOffset(t *testing.T) {
+	consumer := "test-" + createRandomAccount(t).Owner
+
+	_, err := testQueries.GetOutboxOffset(context.Background(), consumer)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	for _, eventID := range []int64{1, 2} {
+		err = testQueries.UpsertOutboxOffset(context.Background(), UpsertOutboxOffsetParams{
+			Consumer: consumer,
+			Txid:     eventID,
+			EventID:  eventID,
+		})
+		require.NoError(t, err)
+	}
+
+	offset, err := testQueries.GetOutboxOffset(context.Background(), consumer)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), offset.EventID)
+}
@@ -6,22 +6,106 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
+	CancelApprovalRequest(ctx context.Context, id int64) (ApprovalRequest, error)
+	// Claims due deliveries for a dispatcher by pushing their next attempt back
+	// by the lease. Rows claimed by a concurrent dispatcher are skipped, and the
+	// outcome of the attempt sets the next attempt again.
+	ClaimDueWebhookDeliveries(ctx context.Context, arg ClaimDueWebhookDeliveriesParams) ([]WebhookDelivery, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateAccountMember(ctx context.Context, arg CreateAccountMemberParams) (AccountMember, error)
+	CreateApprovalDecision(ctx context.Context, arg CreateApprovalDecisionParams) (ApprovalDecision, error)
+	CreateApprovalRequest(ctx context.Context, arg CreateApprovalRequestParams) (ApprovalRequest, error)
+	CreateBeneficiary(ctx context.Context, arg CreateBeneficiaryParams) (Beneficiary, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	// Books the balance an account was created with.
+	CreateOpeningEntry(ctx context.Context, arg CreateOpeningEntryParams) (Entry, error)
+	CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) (Outbox, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
+	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateWebhook(ctx context.Context, arg CreateWebhookParams) (Webhook, error)
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) error
+	DebitAccountBalanceByID(ctx context.Context, arg DebitAccountBalanceByIDParams) (Account, error)
 	DeleteAccountByID(ctx context.Context, id int64) error
+	DeleteAccountMember(ctx context.Context, arg DeleteAccountMemberParams) error
+	DeleteApprovalPolicy(ctx context.Context, accountID int64) error
+	DeleteBeneficiary(ctx context.Context, id int64) error
+	// Buckets idle for longer than it takes them to refill are full and can be
+	// recreated on the next request.
+	DeleteIdleRateLimitBuckets(ctx context.Context, maxIdleSeconds int32) error
+	DeleteRoundUpRule(ctx context.Context, accountID int64) error
+	DeleteWebhook(ctx context.Context, id int64) error
+	EnableWebhook(ctx context.Context, id int64) (Webhook, error)
+	FreezeAccount(ctx context.Context, arg FreezeAccountParams) (AccountFreeze, error)
 	GetAccountById(ctx context.Context, id int64) (Account, error)
+	GetAccountByNumber(ctx context.Context, accountNumber string) (Account, error)
 	GetAccountForUpdate(ctx context.Context, id int64) (Account, error)
+	GetAccountMember(ctx context.Context, arg GetAccountMemberParams) (AccountMember, error)
+	GetApprovalPolicy(ctx context.Context, accountID int64) (ApprovalPolicy, error)
+	GetApprovalRequest(ctx context.Context, id int64) (ApprovalRequest, error)
+	GetApprovalRequestForUpdate(ctx context.Context, id int64) (ApprovalRequest, error)
+	GetBeneficiary(ctx context.Context, id int64) (Beneficiary, error)
 	GetEntry(ctx context.Context, id int64) (Entry, error)
+	// Totals of the whole ledger for reconciliation. Every transfer books two
+	// entries that cancel out and every account books its opening balance, so
+	// entry_total must equal opening_total, entry_count must be twice
+	// transfer_count plus opening_count and no account may be unbalanced.
+	GetLedgerTotals(ctx context.Context) (GetLedgerTotalsRow, error)
+	GetOutboxOffset(ctx context.Context, consumer string) (OutboxOffset, error)
+	GetRoundUpRule(ctx context.Context, accountID int64) (RoundUpRule, error)
 	GetTransfer(ctx context.Context, id int64) (Transfer, error)
+	GetUser(ctx context.Context, username string) (User, error)
+	GetWebhook(ctx context.Context, id int64) (Webhook, error)
+	GetWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error)
+	// Entries of an account after the given entry, each with the balance the
+	// account had right after it.
+	ListAccountEntriesAfter(ctx context.Context, arg ListAccountEntriesAfterParams) ([]ListAccountEntriesAfterRow, error)
+	ListAccountFreezes(ctx context.Context, accountIds []int64) ([]AccountFreeze, error)
+	ListAccountMembers(ctx context.Context, accountID int64) ([]AccountMember, error)
+	ListAccountMembersForUpdate(ctx context.Context, accountID int64) ([]AccountMember, error)
+	ListAccountNumbers(ctx context.Context, ids []int64) ([]ListAccountNumbersRow, error)
+	ListAccountPots(ctx context.Context, parentID pgtype.Int8) ([]Account, error)
 	ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error)
+	ListApprovalDecisions(ctx context.Context, requestID int64) ([]ApprovalDecision, error)
+	ListBeneficiaries(ctx context.Context, arg ListBeneficiariesParams) ([]Beneficiary, error)
 	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error)
+	ListMemberAccounts(ctx context.Context, arg ListMemberAccountsParams) ([]Account, error)
+	// Only events of transactions older than every running transaction are
+	// returned, so that an event committed late can never fall behind the offset.
+	ListOutboxEvents(ctx context.Context, arg ListOutboxEventsParams) ([]Outbox, error)
 	ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error)
+	// Accounts whose balance differs from the sum of their entries.
+	ListUnbalancedAccounts(ctx context.Context, limit int32) ([]ListUnbalancedAccountsRow, error)
+	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ListWebhooks(ctx context.Context, arg ListWebhooksParams) ([]Webhook, error)
+	// Enabled webhooks subscribed to the event type whose owner is a member of
+	// one of the accounts.
+	ListWebhooksForEvent(ctx context.Context, arg ListWebhooksForEventParams) ([]Webhook, error)
+	MarkWebhookDeliveryDelivered(ctx context.Context, id int64) error
+	MarkWebhookDeliveryFailed(ctx context.Context, arg MarkWebhookDeliveryFailedParams) error
+	// Notifies listeners of the account_updates channel once the transaction commits.
+	NotifyAccountUpdate(ctx context.Context, payload string) error
+	RecordWebhookFailure(ctx context.Context, arg RecordWebhookFailureParams) (Webhook, error)
+	RecordWebhookSuccess(ctx context.Context, id int64) error
+	ReplayWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error)
+	// Refills the bucket of key at rate tokens per second up to burst and takes
+	// a token from it if one is left. The row lock of the upsert serializes
+	// concurrent requests for the same key.
+	TakeRateLimitToken(ctx context.Context, arg TakeRateLimitTokenParams) (RateLimitBucket, error)
+	UnfreezeAccount(ctx context.Context, accountID int64) error
 	UpdateAccountBalanceByID(ctx context.Context, arg UpdateAccountBalanceByIDParams) (Account, error)
 	UpdateAccountByID(ctx context.Context, arg UpdateAccountByIDParams) (Account, error)
+	UpdateApprovalRequestStatus(ctx context.Context, arg UpdateApprovalRequestStatusParams) (ApprovalRequest, error)
+	UpdateBeneficiaryNickname(ctx context.Context, arg UpdateBeneficiaryNicknameParams) (Beneficiary, error)
+	UpsertApprovalPolicy(ctx context.Context, arg UpsertApprovalPolicyParams) (ApprovalPolicy, error)
+	UpsertOutboxOffset(ctx context.Context, arg UpsertOutboxOffsetParams) error
+	UpsertRoundUpRule(ctx context.Context, arg UpsertRoundUpRuleParams) (RoundUpRule, error)
+	VerifyBeneficiary(ctx context.Context, id int64) (Beneficiary, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,35 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTakeRateLimitToken(t *testing.T) {
+	arg := TakeRateLimitTokenParams{
+		Key:   "test:" + utils.RandomString(),
+		Burst: 2,
+		// slow enough that the bucket does not refill during the test
+		Rate: 0.001,
+	}
+
+	for _, allowed := range []bool{true, true, false, false} {
+		bucket, err := testQueries.TakeRateLimitToken(context.Background(), arg)
+		require.NoError(t, err)
+		require.Equal(t, arg.Key, bucket.Key)
+		require.Equal(t, allowed, bucket.Allowed)
+		require.GreaterOrEqual(t, bucket.Tokens, 0.0)
+		require.Less(t, bucket.Tokens, arg.Burst)
+	}
+
+	// recently used buckets are kept
+	err := testQueries.DeleteIdleRateLimitBuckets(context.Background(), 3600)
+	require.NoError(t, err)
+
+	bucket, err := testQueries.TakeRateLimitToken(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, bucket.Allowed)
+}
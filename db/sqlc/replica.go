@@ -0,0 +1,49 @@
+package db
+
+import (
+	"context"
+)
+
+type primaryKey struct{}
+
+// WithPrimary makes every read of the store under ctx go to the primary, so
+// a caller reads its own writes even while the replica lags behind.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// UsesPrimary reports whether reads under ctx must go to the primary.
+func UsesPrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryKey{}).(bool)
+	return primary
+}
+
+// SetReplica routes the read-only queries of the store that tolerate lag to
+// replica. Transactions and every other query keep using the primary.
+func (s *SQLStore) SetReplica(replica DBTX) {
+	s.replica = New(replica)
+}
+
+// reader returns the queries reads under ctx run on.
+func (s *SQLStore) reader(ctx context.Context) *Queries {
+	if s.replica == nil || UsesPrimary(ctx) {
+		return s.Queries
+	}
+	return s.replica
+}
+
+func (s *SQLStore) GetAccountById(ctx context.Context, id int64) (Account, error) {
+	return s.reader(ctx).GetAccountById(ctx, id)
+}
+
+func (s *SQLStore) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error) {
+	return s.reader(ctx).ListAccounts(ctx, arg)
+}
+
+func (s *SQLStore) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error) {
+	return s.reader(ctx).ListEntries(ctx, arg)
+}
+
+func (s *SQLStore) ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error) {
+	return s.reader(ctx).ListTransfers(ctx, arg)
+}
@@ -0,0 +1,23 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplicaRouting(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(testDB)
+	require.Same(t, store.Queries, store.reader(ctx))
+
+	store.SetReplica(testDB)
+	require.Same(t, store.replica, store.reader(ctx))
+	require.Same(t, store.Queries, store.reader(WithPrimary(ctx)))
+
+	account := createRandomAccount(t)
+	got, err := store.GetAccountById(ctx, account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.ID, got.ID)
+}
@@ -2,36 +2,179 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/singhJasvinder101/go_bank/logging"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// ErrRecordNotFound is returned by queries that expect exactly one row.
+var ErrRecordNotFound = pgx.ErrNoRows
+
+// ErrAccountFrozen is returned by transfers from or to a frozen account.
+var ErrAccountFrozen = errors.New("account is frozen")
+
+// ErrInsufficientFunds is returned by transfers out of an account holding
+// less than the amount.
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// Postgres error codes handled by the store and its callers.
+const (
+	UniqueViolation      = "23505"
+	ForeignKeyViolation  = "23503"
+	CheckViolation       = "23514"
+	SerializationFailure = "40001"
+	DeadlockDetected     = "40P01"
+)
+
+// ErrorCode returns the Postgres error code carried by err, or an empty string.
+func ErrorCode(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+	return ""
+}
 
 // interface for all db function to make mock args by mockDB
-type Store interface{
+type Store interface {
 	TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
+	CreateAccountTx(ctx context.Context, arg CreateAccountParams, opts ...CreateAccountOption) (CreateAccountTxResult, error)
+	PotTransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
+	ApprovalDecisionTx(ctx context.Context, arg ApprovalDecisionTxParams) (ApprovalDecisionTxResult, error)
+	RemoveMemberTx(ctx context.Context, arg DeleteAccountMemberParams) error
 	Querier
 }
 
+// Conn is what the store runs on: a connection pool, or a transaction whose
+// changes the caller commits or rolls back as a whole. Within a transaction
+// the transactions of the store become savepoints.
+type Conn interface {
+	DBTX
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
 type SQLStore struct {
-	*Queries               // methods provided by sqlc generated Queries struct
-	db       *pgxpool.Pool // connection pool for PSQL to begin db.BeginTx
+	*Queries          // methods provided by sqlc generated Queries struct
+	db       Conn     // connection pool for PSQL to begin db.Begin
+	replica  *Queries // queries on the read replica, nil without one
+	observer TxObserver
+	logger   *slog.Logger
 }
 
-func NewStore(db *pgxpool.Pool) *SQLStore {
+func NewStore(db Conn) *SQLStore {
 	return &SQLStore{
 		db:      db,
 		Queries: New(db), // create Queries object to be used for testing in store_test
+		logger:  slog.Default(),
+	}
+}
+
+// SetLogger makes the store log the steps of its transactions to logger.
+func (s *SQLStore) SetLogger(logger *slog.Logger) {
+	s.logger = logger
+}
+
+// tracer starts a span for every transaction of the store. The queries run
+// within it get child spans from the pgx tracer set up by the caller.
+var tracer = otel.Tracer("github.com/singhJasvinder101/go_bank/db/sqlc")
+
+// maxTxAttempts bounds how often execTx runs a transaction that failed with
+// a deadlock or serialization failure.
+const maxTxAttempts = 3
+
+// Outcomes of a transaction reported to a TxObserver.
+const (
+	TxCommitted  = "committed"
+	TxRolledBack = "rolled_back"
+	TxFailed     = "failed"
+)
+
+// TxObserver is told about every transaction run by the store, e.g. to
+// record metrics.
+type TxObserver interface {
+	// ObserveTx is called once per attempt of the named transaction.
+	ObserveTx(name string, duration time.Duration, outcome string)
+	// ObserveTxRetry is called before an attempt is retried, with the
+	// Postgres error code it failed with: DeadlockDetected or
+	// SerializationFailure. Deadlocks are retried too, so counting them here
+	// is the way to notice them.
+	ObserveTxRetry(name string, code string)
+}
+
+// SetTxObserver makes the store report its transactions to observer.
+func (s *SQLStore) SetTxObserver(observer TxObserver) {
+	s.observer = observer
+}
+
+// retryable reports whether a transaction failed only because it ran
+// concurrently with another one and can be run again.
+func retryable(err error) bool {
+	switch ErrorCode(err) {
+	case SerializationFailure, DeadlockDetected:
+		return true
 	}
+	return false
 }
 
+// execTx runs fn within a database transaction, retrying it when it loses a
+// race against a concurrent transaction. fn may run more than once. Retries
+// and the final error are recorded on the span of ctx.
+func (s *SQLStore) execTx(ctx context.Context, name string, fn func(Querier) error) error {
+	span := trace.SpanFromContext(ctx)
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		var outcome string
+		outcome, err = s.runTx(ctx, fn)
+		if s.observer != nil {
+			s.observer.ObserveTx(name, time.Since(start), outcome)
+		}
+
+		if attempt == maxTxAttempts || !retryable(err) || ctx.Err() != nil {
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			return err
+		}
+		if s.observer != nil {
+			s.observer.ObserveTxRetry(name, ErrorCode(err))
+		}
+		s.logger.WarnContext(ctx, "retrying transaction",
+			slog.String("tx", name),
+			slog.Int("attempt", attempt),
+			slog.Any("error", err),
+		)
+		span.AddEvent("retry", trace.WithAttributes(
+			attribute.Int("tx.attempt", attempt),
+			attribute.String("db.error_code", ErrorCode(err)),
+		))
+	}
+}
+
+// transferAttributes describes a money transfer on a span.
+func transferAttributes(arg TransferTxParams) trace.SpanStartEventOption {
+	return trace.WithAttributes(
+		attribute.Int64("transfer.from_account_id", arg.FromAccountID),
+		attribute.Int64("transfer.to_account_id", arg.ToAccountID),
+		attribute.Int64("transfer.amount", arg.Amount),
+	)
+}
 
-func (s *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) error {
-	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+func (s *SQLStore) runTx(ctx context.Context, fn func(Querier) error) (string, error) {
+	tx, err := s.db.Begin(ctx)
 	if err != nil {
-		return err
+		return TxFailed, err
 	}
 
 	// new Queries object tied for each transaction (tx) to execute
@@ -41,12 +184,15 @@ func (s *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) error {
 	err = fn(q)
 	if err != nil {
 		if rbErr := tx.Rollback(ctx); rbErr != nil {
-			return fmt.Errorf("tx error: %v, rb error: %v", err, rbErr)
+			return TxFailed, fmt.Errorf("tx error: %w, rb error: %v", err, rbErr)
 		}
-		return err
+		return TxRolledBack, err
 	}
 
-	return tx.Commit(ctx)
+	if err := tx.Commit(ctx); err != nil {
+		return TxFailed, err
+	}
+	return TxCommitted, nil
 }
 
 type TransferTxParams struct {
@@ -61,90 +207,141 @@ type TransferTxResult struct {
 	ToAccount   Account  `json:"to_account"`
 	FromEntry   Entry    `json:"from_entry"`
 	ToEntry     Entry    `json:"to_entry"`
+	// RoundUp is the sweep into a savings pot made after the transfer, if any.
+	RoundUp *Transfer `json:"round_up,omitempty"`
 }
 
-var txKey = struct{}{}
-
 // TransferTx performs a money transfer from one account to the other.
 // It creates a transfer record, add account entries, and update accounts' balance within a single database transaction.
 func (store *SQLStore) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
+	ctx, span := tracer.Start(ctx, "SQLStore.TransferTx", transferAttributes(arg))
+	defer span.End()
+
 	var result TransferTxResult
 
-    err := store.execTx(ctx, func(q *Queries) error {
+	err := store.execTx(ctx, "transfer", func(q Querier) error {
 		var err error
-		txName := ctx.Value(txKey)
-
-		fmt.Println(txName, "create transfer")
-		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams(arg))
+		result, err = transfer(ctx, q, store.logger, arg)
 		if err != nil {
 			return err
 		}
 
-		fmt.Println(txName, "create entry 1")
-		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.FromAccountID,
-			Amount:    -arg.Amount,
-		})
-		if err != nil {
-			return err
-		}
+		return sweepRoundUp(ctx, q, store.logger, &result)
+	})
+	if err == nil {
+		span.SetAttributes(attribute.Int64("transfer.id", result.Transfer.ID))
+	}
 
-		fmt.Println(txName, "create entry 2")
-		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.ToAccountID,
-			Amount:    arg.Amount,
-		})
-		if err != nil {
-			return err
-		}
+	return result, err
+}
 
-		// fmt.Println(txName, "get account 1: for update")
-		// // Use FOR UPDATE to lock the rows
-		// account1, err := q.GetAccountForUpdate(ctx, arg.FromAccountID)
-		// if err != nil {
-		// 	return err
-		// }
-
-		// fmt.Println(txName, "get account 2: for update")
-		// account2, err := q.GetAccountForUpdate(ctx, arg.ToAccountID)
-		// if err != nil {
-		// 	return err
-		// }
-
-		fmt.Println(txName, "update account 1")
-		// result.FromAccount, err = q.UpdateAccountByID(ctx, UpdateAccountByIDParams{
-		// Balance: account2.Balance + arg.Amount,
-		if arg.FromAccountID < arg.ToAccountID {
-			result.FromAccount, result.ToAccount, err = addMoney(ctx, q, arg.FromAccountID, -arg.Amount, arg.ToAccountID, arg.Amount)
-		} else {
-			result.ToAccount, result.FromAccount, err = addMoney(ctx, q, arg.ToAccountID, arg.Amount, arg.FromAccountID, -arg.Amount)
-		}
+// transfer runs the steps of a money transfer on q, so that it can be
+// reused by other transactions that end up moving money, and by both
+// SQLStore and MemoryStore.
+func transfer(ctx context.Context, q Querier, logger *slog.Logger, arg TransferTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
 
-		return nil
+	freezes, err := q.ListAccountFreezes(ctx, []int64{arg.FromAccountID, arg.ToAccountID})
+	if err != nil {
+		return result, err
+	}
+	if len(freezes) > 0 {
+		return result, fmt.Errorf("%w: account [%d]: %s", ErrAccountFrozen, freezes[0].AccountID, freezes[0].Reason)
+	}
+
+	result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams(arg))
+	if err != nil {
+		return result, err
+	}
+
+	// the remaining steps and their queries are logged with the transfer id
+	ctx = logging.With(ctx, logging.TransferIDKey, result.Transfer.ID)
+	logger.DebugContext(ctx, "transfer created",
+		slog.Int64("from_account_id", arg.FromAccountID),
+		slog.Int64("to_account_id", arg.ToAccountID),
+		slog.Int64("amount", arg.Amount),
+	)
+
+	result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: arg.FromAccountID,
+		Amount:    -arg.Amount,
 	})
+	if err != nil {
+		return result, err
+	}
 
+	result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: arg.ToAccountID,
+		Amount:    arg.Amount,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	logger.DebugContext(ctx, "transfer entries created")
+
+	// always update the account with the smaller id first to avoid deadlocks
+	if arg.FromAccountID < arg.ToAccountID {
+		result.FromAccount, result.ToAccount, err = addMoney(ctx, q, arg.FromAccountID, -arg.Amount, arg.ToAccountID, arg.Amount)
+	} else {
+		result.ToAccount, result.FromAccount, err = addMoney(ctx, q, arg.ToAccountID, arg.Amount, arg.FromAccountID, -arg.Amount)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	logger.DebugContext(ctx, "transfer balances updated")
+
+	if err = notifyEntry(ctx, q, result.FromAccount, result.FromEntry); err != nil {
+		return result, err
+	}
+	if err = notifyEntry(ctx, q, result.ToAccount, result.ToEntry); err != nil {
+		return result, err
+	}
+
+	err = emitEvent(ctx, q, AggregateTransfer, result.Transfer.ID, EventTransferCompleted, TransferCompletedEvent{
+		Transfer:           result.Transfer,
+		FromAccountBalance: result.FromAccount.Balance,
+		ToAccountBalance:   result.ToAccount.Balance,
+	})
 	return result, err
 }
 
+// addMoney changes the balances of two accounts by amount1 and amount2, in
+// that order. A negative amount only applies when the account holds at
+// least as much, so that concurrent transfers cannot overdraw it.
 func addMoney(
 	ctx context.Context,
-	q *Queries,
+	q Querier,
 	accountID1 int64,
 	amount1 int64,
 	accountID2 int64,
 	amount2 int64,
 ) (account1 Account, account2 Account, err error) {
-	account1, err = q.UpdateAccountBalanceByID(ctx, UpdateAccountBalanceByIDParams{
-		AccountID: accountID1,
-		Amount:    amount1,
-	})
+	account1, err = changeBalance(ctx, q, accountID1, amount1)
 	if err != nil {
 		return
 	}
 
-	account2, err = q.UpdateAccountBalanceByID(ctx, UpdateAccountBalanceByIDParams{
-		AccountID: accountID2,
-		Amount:    amount2,
-	})
+	account2, err = changeBalance(ctx, q, accountID2, amount2)
 	return
 }
+
+func changeBalance(ctx context.Context, q Querier, accountID int64, amount int64) (Account, error) {
+	if amount >= 0 {
+		return q.UpdateAccountBalanceByID(ctx, UpdateAccountBalanceByIDParams{
+			AccountID: accountID,
+			Amount:    amount,
+		})
+	}
+
+	account, err := q.DebitAccountBalanceByID(ctx, DebitAccountBalanceByIDParams{
+		AccountID: accountID,
+		Amount:    -amount,
+	})
+	if errors.Is(err, ErrRecordNotFound) {
+		// the entries of the transfer already proved that the account exists
+		return account, fmt.Errorf("%w: account [%d]", ErrInsufficientFunds, accountID)
+	}
+	return account, err
+}
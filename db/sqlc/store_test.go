@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/singhJasvinder101/go_bank/logging"
 	"github.com/stretchr/testify/require"
 )
 func TestTransferTx(t *testing.T) {
@@ -28,7 +29,7 @@ func TestTransferTx(t *testing.T) {
 			txName := fmt.Sprintf("tx %d", i+1)
 
 			// ctxWithVal := context.WithValue(context.Background(), txKey, txName)
-			ctx := context.WithValue(context.Background(), txKey, txName)
+			ctx := logging.With(context.Background(), "tx", txName)
 			// ctx, cancel := context.WithTimeout(ctxWithVal, time.Second)
 			// defer cancel()
 
@@ -141,7 +142,7 @@ func TestTransferTxDeadlock(t *testing.T) {
 			txName := fmt.Sprintf("tx %d", i+1)
 
 			// ctxWithVal := context.WithValue(context.Background(), txKey, txName)
-			ctx := context.WithValue(context.Background(), txKey, txName)
+			ctx := logging.With(context.Background(), "tx", txName)
 			// ctx, cancel := context.WithTimeout(ctxWithVal, time.Second)
 			// defer cancel()
 
@@ -0,0 +1,59 @@
+package db_test
+
+import (
+	"context"
+	"flag"
+	"testing"
+	"time"
+
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/db/stress"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	stressDuration = flag.Duration("stress.duration", 5*time.Second, "how long TestTransferTxStress sends transfers")
+	stressSeed     = flag.Int64("stress.seed", 0, "seed of TestTransferTxStress, random if 0")
+)
+
+// TestTransferTxStress runs thousands of concurrent transfers, including
+// opposite-direction pairs and cycles, and checks the ledger afterwards.
+// With small balances, transfers race to drain the same accounts and many
+// are refused for insufficient funds. Rerun a failure with the logged seed:
+//
+//	go test ./db/sqlc -run TestTransferTxStress -stress.seed=SEED -stress.duration=1m
+func TestTransferTxStress(t *testing.T) {
+	if testing.Short() {
+		t.Skip("stress test skipped in short mode")
+	}
+
+	seed := *stressSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	t.Logf("seed %d", seed)
+
+	for _, tc := range []struct {
+		name    string
+		balance int64
+	}{
+		{"LargeBalances", 0},
+		{"SmallBalances", 200},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := stress.Run(context.Background(), db.NewStore(db.PoolForTests()), stress.Config{
+				Seed:      seed,
+				Duration:  *stressDuration,
+				Workers:   16,
+				Balance:   tc.balance,
+				MaxAmount: 100,
+			})
+			require.NoError(t, err)
+			t.Logf("%d transfers, %d rejected, %d pairs, %d cycles in %s, retries %v",
+				report.Transfers, report.Rejected, report.Pairs, report.Cycles, report.Duration, report.Retries)
+
+			require.Empty(t, report.Problems)
+			require.Positive(t, report.Transfers)
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ApprovalPending   = "pending"
+	ApprovalApproved  = "approved"
+	ApprovalRejected  = "rejected"
+	ApprovalCancelled = "cancelled"
+)
+
+var (
+	ErrApprovalNotPending  = errors.New("approval request is no longer pending")
+	ErrNotEligibleApprover = errors.New("user is not an eligible approver for this account")
+	ErrAlreadyDecided      = errors.New("user has already decided on this approval request")
+	ErrQuorumUnreachable   = errors.New("approval policy has fewer approvers besides the maker than it requires")
+)
+
+// NeedsApproval reports whether a transfer of amount out of the policy's
+// account must go through maker-checker approval.
+func (policy ApprovalPolicy) NeedsApproval(amount int64) bool {
+	return amount > policy.Threshold
+}
+
+// QuorumReachable reports whether the approvers of the policy other than
+// maker, who never checks their own transfers, can reach its quorum.
+func (policy ApprovalPolicy) QuorumReachable(maker string) bool {
+	approvers := 0
+	for _, approver := range policy.Approvers {
+		if approver != maker {
+			approvers++
+		}
+	}
+	return approvers >= int(policy.RequiredApprovals)
+}
+
+type ApprovalDecisionTxParams struct {
+	RequestID int64  `json:"request_id"`
+	Approver  string `json:"approver"`
+	Approved  bool   `json:"approved"`
+}
+
+type ApprovalDecisionTxResult struct {
+	Request  ApprovalRequest   `json:"request"`
+	Decision ApprovalDecision  `json:"decision"`
+	Transfer *TransferTxResult `json:"transfer,omitempty"`
+}
+
+// ApprovalDecisionTx records an approver's decision on a pending approval request.
+// A single rejection rejects the request; once the number of approvals reaches the
+// account policy's quorum the transfer is executed within the same database transaction.
+func (store *SQLStore) ApprovalDecisionTx(ctx context.Context, arg ApprovalDecisionTxParams) (ApprovalDecisionTxResult, error) {
+	ctx, span := tracer.Start(ctx, "SQLStore.ApprovalDecisionTx", trace.WithAttributes(
+		attribute.Int64("approval.request_id", arg.RequestID),
+	))
+	defer span.End()
+
+	var result ApprovalDecisionTxResult
+
+	err := store.execTx(ctx, "approval_decision", func(q Querier) error {
+		var err error
+		result, err = decideApproval(ctx, q, store.logger, arg)
+		return err
+	})
+
+	return result, err
+}
+
+// decideApproval runs the steps of ApprovalDecisionTx on q.
+func decideApproval(ctx context.Context, q Querier, logger *slog.Logger, arg ApprovalDecisionTxParams) (ApprovalDecisionTxResult, error) {
+	var result ApprovalDecisionTxResult
+
+	// lock the request so that concurrent decisions are counted one at a time
+	request, err := q.GetApprovalRequestForUpdate(ctx, arg.RequestID)
+	if err != nil {
+		return result, err
+	}
+	if request.Status != ApprovalPending {
+		return result, ErrApprovalNotPending
+	}
+
+	policy, err := q.GetApprovalPolicy(ctx, request.FromAccountID)
+	if err != nil {
+		return result, err
+	}
+	// the maker of a transfer never checks it, even when listed as an approver
+	if arg.Approver == request.RequestedBy || !slices.Contains(policy.Approvers, arg.Approver) {
+		return result, ErrNotEligibleApprover
+	}
+	// the policy may have changed since the request was made; such a request
+	// can still be rejected or cancelled
+	if arg.Approved && !policy.QuorumReachable(request.RequestedBy) {
+		return result, ErrQuorumUnreachable
+	}
+
+	decisions, err := q.ListApprovalDecisions(ctx, request.ID)
+	if err != nil {
+		return result, err
+	}
+
+	approvals := int32(0)
+	for _, decision := range decisions {
+		if decision.Approver == arg.Approver {
+			return result, ErrAlreadyDecided
+		}
+		if decision.Approved {
+			approvals++
+		}
+	}
+
+	result.Decision, err = q.CreateApprovalDecision(ctx, CreateApprovalDecisionParams(arg))
+	if err != nil {
+		return result, err
+	}
+
+	if !arg.Approved {
+		result.Request, err = q.UpdateApprovalRequestStatus(ctx, UpdateApprovalRequestStatusParams{
+			ID:     request.ID,
+			Status: ApprovalRejected,
+		})
+		return result, err
+	}
+
+	approvals++
+	if approvals < policy.RequiredApprovals {
+		result.Request = request
+		return result, nil
+	}
+
+	transferResult, err := transfer(ctx, q, logger, TransferTxParams{
+		FromAccountID: request.FromAccountID,
+		ToAccountID:   request.ToAccountID,
+		Amount:        request.Amount,
+	})
+	if err != nil {
+		return result, err
+	}
+	if err := sweepRoundUp(ctx, q, logger, &transferResult); err != nil {
+		return result, err
+	}
+	result.Transfer = &transferResult
+
+	result.Request, err = q.UpdateApprovalRequestStatus(ctx, UpdateApprovalRequestStatusParams{
+		ID:         request.ID,
+		Status:     ApprovalApproved,
+		TransferID: pgtype.Int8{Int64: transferResult.Transfer.ID, Valid: true},
+	})
+	return result, err
+}
@@ -0,0 +1,105 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomApprovalRequest(t *testing.T, approvers []string, required int32) (Account, Account, ApprovalRequest) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	_, err := testQueries.UpsertApprovalPolicy(context.Background(), UpsertApprovalPolicyParams{
+		AccountID:         account1.ID,
+		Threshold:         0,
+		RequiredApprovals: required,
+		Approvers:         approvers,
+	})
+	require.NoError(t, err)
+
+	request, err := testQueries.CreateApprovalRequest(context.Background(), CreateApprovalRequestParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		RequestedBy:   utils.RandomOwner(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, ApprovalPending, request.Status)
+	require.False(t, request.TransferID.Valid)
+
+	return account1, account2, request
+}
+
+func TestApprovalDecisionTxQuorum(t *testing.T) {
+	store := NewStore(testDB)
+	approvers := []string{utils.RandomOwner(), utils.RandomOwner()}
+	account1, account2, request := createRandomApprovalRequest(t, approvers, 2)
+
+	// first approval does not reach the quorum
+	result, err := store.ApprovalDecisionTx(context.Background(), ApprovalDecisionTxParams{
+		RequestID: request.ID,
+		Approver:  approvers[0],
+		Approved:  true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ApprovalPending, result.Request.Status)
+	require.Nil(t, result.Transfer)
+
+	// the same approver cannot approve twice
+	_, err = store.ApprovalDecisionTx(context.Background(), ApprovalDecisionTxParams{
+		RequestID: request.ID,
+		Approver:  approvers[0],
+		Approved:  true,
+	})
+	require.ErrorIs(t, err, ErrAlreadyDecided)
+
+	// second approval runs the transfer
+	result, err = store.ApprovalDecisionTx(context.Background(), ApprovalDecisionTxParams{
+		RequestID: request.ID,
+		Approver:  approvers[1],
+		Approved:  true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ApprovalApproved, result.Request.Status)
+	require.NotNil(t, result.Transfer)
+	require.True(t, result.Request.TransferID.Valid)
+	require.Equal(t, result.Transfer.Transfer.ID, result.Request.TransferID.Int64)
+	require.Equal(t, account1.Balance-request.Amount, result.Transfer.FromAccount.Balance)
+	require.Equal(t, account2.Balance+request.Amount, result.Transfer.ToAccount.Balance)
+}
+
+func TestApprovalDecisionTxReject(t *testing.T) {
+	store := NewStore(testDB)
+	approvers := []string{utils.RandomOwner(), utils.RandomOwner()}
+	account1, _, request := createRandomApprovalRequest(t, approvers, 1)
+
+	_, err := store.ApprovalDecisionTx(context.Background(), ApprovalDecisionTxParams{
+		RequestID: request.ID,
+		Approver:  utils.RandomOwner(),
+		Approved:  true,
+	})
+	require.ErrorIs(t, err, ErrNotEligibleApprover)
+
+	result, err := store.ApprovalDecisionTx(context.Background(), ApprovalDecisionTxParams{
+		RequestID: request.ID,
+		Approver:  approvers[0],
+		Approved:  false,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ApprovalRejected, result.Request.Status)
+	require.Nil(t, result.Transfer)
+
+	_, err = store.ApprovalDecisionTx(context.Background(), ApprovalDecisionTxParams{
+		RequestID: request.ID,
+		Approver:  approvers[1],
+		Approved:  true,
+	})
+	require.ErrorIs(t, err, ErrApprovalNotPending)
+
+	account, err := testQueries.GetAccountById(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, account.Balance)
+}
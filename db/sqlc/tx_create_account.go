@@ -0,0 +1,115 @@
+package db
+
+import (
+	"context"
+
+	"github.com/singhJasvinder101/go_bank/utils"
+)
+
+// accountNumberAttempts bounds how often a colliding generated account number
+// is regenerated.
+const accountNumberAttempts = 3
+
+type CreateAccountTxResult struct {
+	Account Account       `json:"account"`
+	Member  AccountMember `json:"member"`
+	// OpeningEntry books the opening balance, if the account has one.
+	OpeningEntry *Entry `json:"opening_entry,omitempty"`
+}
+
+// CreateAccountOption configures CreateAccountTx.
+type CreateAccountOption func(*createAccountOptions)
+
+type createAccountOptions struct {
+	newNumber func() string
+}
+
+// WithGeneratedNumber makes CreateAccountTx generate the account number in
+// place of arg.AccountNumber, for the bank given by its country and bank
+// code. A generated number that is already taken is regenerated a few times.
+func WithGeneratedNumber(countryCode, bankCode string) CreateAccountOption {
+	return func(options *createAccountOptions) {
+		options.newNumber = func() string {
+			return utils.NewAccountNumber(countryCode, bankCode)
+		}
+	}
+}
+
+// CreateAccountTx creates an account, registers its owner as the first member
+// and books its opening balance within a single database transaction.
+func (store *SQLStore) CreateAccountTx(ctx context.Context, arg CreateAccountParams, opts ...CreateAccountOption) (CreateAccountTxResult, error) {
+	ctx, span := tracer.Start(ctx, "SQLStore.CreateAccountTx")
+	defer span.End()
+
+	return createAccountAttempts(arg, opts, func(arg CreateAccountParams) (CreateAccountTxResult, error) {
+		var result CreateAccountTxResult
+
+		err := store.execTx(ctx, "create_account", func(q Querier) error {
+			var err error
+			result, err = createAccountWithOwner(ctx, q, arg)
+			return err
+		})
+
+		return result, err
+	})
+}
+
+// createAccountAttempts calls create with arg as opts configure it. Every
+// attempt runs in a transaction of its own, as a unique violation aborts the
+// transaction it happens in.
+func createAccountAttempts(arg CreateAccountParams, opts []CreateAccountOption, create func(CreateAccountParams) (CreateAccountTxResult, error)) (CreateAccountTxResult, error) {
+	var options createAccountOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.newNumber == nil {
+		return create(arg)
+	}
+
+	var result CreateAccountTxResult
+	var err error
+	for i := 0; i < accountNumberAttempts; i++ {
+		arg.AccountNumber = options.newNumber()
+		result, err = create(arg)
+		if ErrorCode(err) != UniqueViolation {
+			break
+		}
+	}
+	return result, err
+}
+
+// createAccountWithOwner runs the steps of CreateAccountTx on q.
+func createAccountWithOwner(ctx context.Context, q Querier, arg CreateAccountParams) (CreateAccountTxResult, error) {
+	var result CreateAccountTxResult
+	var err error
+
+	result.Account, err = q.CreateAccount(ctx, arg)
+	if err != nil {
+		return result, err
+	}
+
+	result.Member, err = q.CreateAccountMember(ctx, CreateAccountMemberParams{
+		AccountID: result.Account.ID,
+		Username:  arg.Owner,
+		Role:      RoleOwner,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	// the balance of every account is the sum of its entries, money does
+	// not appear without one
+	if arg.Balance != 0 {
+		entry, err := q.CreateOpeningEntry(ctx, CreateOpeningEntryParams{
+			AccountID: result.Account.ID,
+			Amount:    arg.Balance,
+		})
+		if err != nil {
+			return result, err
+		}
+		result.OpeningEntry = &entry
+	}
+
+	err = emitEvent(ctx, q, AggregateAccount, result.Account.ID, EventAccountCreated, result.Account)
+	return result, err
+}
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var ErrLastOwner = errors.New("cannot remove the last owner of the account")
+
+// RemoveMemberTx removes a member from an account, unless it is the last
+// owner. The members are counted and removed within one database
+// transaction, so concurrent removals cannot leave the account without one.
+func (store *SQLStore) RemoveMemberTx(ctx context.Context, arg DeleteAccountMemberParams) error {
+	ctx, span := tracer.Start(ctx, "SQLStore.RemoveMemberTx", trace.WithAttributes(
+		attribute.Int64("account.id", arg.AccountID),
+	))
+	defer span.End()
+
+	return store.execTx(ctx, "remove_member", func(q Querier) error {
+		return removeMember(ctx, q, arg)
+	})
+}
+
+// removeMember runs the steps of RemoveMemberTx on q. It returns
+// ErrRecordNotFound when the user is not a member of the account.
+func removeMember(ctx context.Context, q Querier, arg DeleteAccountMemberParams) error {
+	// lock the members so that concurrent removals are counted one at a time
+	members, err := q.ListAccountMembersForUpdate(ctx, arg.AccountID)
+	if err != nil {
+		return err
+	}
+
+	index := slices.IndexFunc(members, func(member AccountMember) bool {
+		return member.Username == arg.Username
+	})
+	if index < 0 {
+		return ErrRecordNotFound
+	}
+
+	if members[index].Role == RoleOwner {
+		owners := 0
+		for _, member := range members {
+			if member.Role == RoleOwner {
+				owners++
+			}
+		}
+		if owners == 1 {
+			return ErrLastOwner
+		}
+	}
+
+	return q.DeleteAccountMember(ctx, arg)
+}
@@ -0,0 +1,66 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// RoundUpAmount returns the spare change needed to round amount up to the
+// next multiple of the rule's round_to.
+func (rule RoundUpRule) RoundUpAmount(amount int64) int64 {
+	return (rule.RoundTo - amount%rule.RoundTo) % rule.RoundTo
+}
+
+// PotTransferTx moves money between a parent account and one of its pots.
+// Like TransferTx it fails with ErrInsufficientFunds when the source holds
+// less than the amount, but it never triggers a round-up sweep.
+func (store *SQLStore) PotTransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
+	ctx, span := tracer.Start(ctx, "SQLStore.PotTransferTx", transferAttributes(arg))
+	defer span.End()
+
+	var result TransferTxResult
+
+	err := store.execTx(ctx, "pot_transfer", func(q Querier) error {
+		var err error
+		result, err = transfer(ctx, q, store.logger, arg)
+		return err
+	})
+	if err == nil {
+		span.SetAttributes(attribute.Int64("transfer.id", result.Transfer.ID))
+	}
+
+	return result, err
+}
+
+// sweepRoundUp moves the spare change of an outgoing transfer into the pot set
+// by the source account's round-up rule, when it has one and can afford it.
+func sweepRoundUp(ctx context.Context, q Querier, logger *slog.Logger, result *TransferTxResult) error {
+	rule, err := q.GetRoundUpRule(ctx, result.Transfer.FromAccountID)
+	if err != nil {
+		if errors.Is(err, ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	spare := rule.RoundUpAmount(result.Transfer.Amount)
+	if spare == 0 || rule.PotID == result.Transfer.ToAccountID || result.FromAccount.Balance < spare {
+		return nil
+	}
+
+	sweep, err := transfer(ctx, q, logger, TransferTxParams{
+		FromAccountID: rule.AccountID,
+		ToAccountID:   rule.PotID,
+		Amount:        spare,
+	})
+	if err != nil {
+		return err
+	}
+
+	result.RoundUp = &sweep.Transfer
+	result.FromAccount = sweep.FromAccount
+	return nil
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundUpAmount(t *testing.T) {
+	rule := RoundUpRule{RoundTo: 100}
+	require.Equal(t, int64(55), rule.RoundUpAmount(345))
+	require.Equal(t, int64(0), rule.RoundUpAmount(300))
+	require.Equal(t, int64(99), rule.RoundUpAmount(1))
+}
+
+func TestTransferTxRoundUp(t *testing.T) {
+	store := NewStore(testDB)
+
+	parent, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:         utils.RandomOwner(),
+		Balance:       1000,
+		Currency:      utils.RandomCurrency(),
+		AccountNumber: utils.RandomAccountNumber(),
+	})
+	require.NoError(t, err)
+
+	pot, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:         parent.Owner,
+		Balance:       0,
+		Currency:      parent.Currency,
+		ParentID:      pgtype.Int8{Int64: parent.ID, Valid: true},
+		AccountNumber: utils.RandomAccountNumber(),
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.UpsertRoundUpRule(context.Background(), UpsertRoundUpRuleParams{
+		AccountID: parent.ID,
+		PotID:     pot.ID,
+		RoundTo:   100,
+	})
+	require.NoError(t, err)
+
+	other := createRandomAccount(t)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: parent.ID,
+		ToAccountID:   other.ID,
+		Amount:        345,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.RoundUp)
+	require.Equal(t, pot.ID, result.RoundUp.ToAccountID)
+	require.Equal(t, int64(55), result.RoundUp.Amount)
+	require.Equal(t, int64(1000-345-55), result.FromAccount.Balance)
+
+	// moving money into the pot itself does not sweep again
+	potResult, err := store.PotTransferTx(context.Background(), TransferTxParams{
+		FromAccountID: parent.ID,
+		ToAccountID:   pot.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+	require.Nil(t, potResult.RoundUp)
+
+	pots, err := testQueries.ListAccountPots(context.Background(), pgtype.Int8{Int64: parent.ID, Valid: true})
+	require.NoError(t, err)
+	require.Len(t, pots, 1)
+	require.Equal(t, int64(65), pots[0].Balance)
+}
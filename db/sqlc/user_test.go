@@ -0,0 +1,42 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomUser(t *testing.T) User {
+	username := utils.RandomOwner()
+	arg := CreateUserParams{
+		Username: username,
+		FullName: "Full " + username,
+		Email:    username + "@example.com",
+	}
+
+	user, err := testQueries.CreateUser(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Username, user.Username)
+	require.Equal(t, arg.FullName, user.FullName)
+	require.Equal(t, arg.Email, user.Email)
+	require.True(t, user.CreatedAt.Valid)
+
+	return user
+}
+
+func TestCreateUser(t *testing.T) {
+	user := createRandomUser(t)
+
+	got, err := testQueries.GetUser(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Equal(t, user, got)
+
+	_, err = testQueries.CreateUser(context.Background(), CreateUserParams{
+		Username: user.Username,
+		FullName: user.FullName,
+		Email:    utils.RandomOwner() + "@example.com",
+	})
+	require.Equal(t, UniqueViolation, ErrorCode(err))
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomWebhook(t *testing.T, owner string) Webhook {
+	arg := CreateWebhookParams{
+		Owner:      owner,
+		Url:        "https://example.com/hook",
+		EventTypes: []string{EventTransferCompleted},
+		Secret:     "whsec_test",
+	}
+
+	webhook, err := testQueries.CreateWebhook(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, webhook.ID)
+	require.Equal(t, arg.EventTypes, webhook.EventTypes)
+	require.False(t, webhook.Disabled)
+
+	return webhook
+}
+
+func TestListWebhooksForEvent(t *testing.T) {
+	account := createRandomAccount(t)
+	_, err := testQueries.CreateAccountMember(context.Background(), CreateAccountMemberParams{
+		AccountID: account.ID,
+		Username:  account.Owner,
+		Role:      RoleOwner,
+	})
+	require.NoError(t, err)
+
+	webhook := createRandomWebhook(t, account.Owner)
+
+	webhooks, err := testQueries.ListWebhooksForEvent(context.Background(), ListWebhooksForEventParams{
+		AccountIds: []int64{account.ID},
+		EventType:  EventTransferCompleted,
+	})
+	require.NoError(t, err)
+	require.Len(t, webhooks, 1)
+	require.Equal(t, webhook.ID, webhooks[0].ID)
+
+	webhooks, err = testQueries.ListWebhooksForEvent(context.Background(), ListWebhooksForEventParams{
+		AccountIds: []int64{account.ID},
+		EventType:  EventAccountCreated,
+	})
+	require.NoError(t, err)
+	require.Empty(t, webhooks)
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	webhook := createRandomWebhook(t, createRandomAccount(t).Owner)
+
+	arg := CreateWebhookDeliveryParams{
+		WebhookID: webhook.ID,
+		EventID:   1,
+		EventType: EventTransferCompleted,
+		Payload:   []byte(`{}`),
+	}
+	// the same event is only queued once per webhook
+	for i := 0; i < 2; i++ {
+		require.NoError(t, testQueries.CreateWebhookDelivery(context.Background(), arg))
+	}
+
+	deliveries, err := testQueries.ListWebhookDeliveries(context.Background(), ListWebhookDeliveriesParams{
+		WebhookID: webhook.ID,
+		Limit:     5,
+	})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, "pending", deliveries[0].Status)
+}
+
+func TestRecordWebhookFailureDisables(t *testing.T) {
+	webhook := createRandomWebhook(t, createRandomAccount(t).Owner)
+
+	for i := 0; i < 3; i++ {
+		var err error
+		webhook, err = testQueries.RecordWebhookFailure(context.Background(), RecordWebhookFailureParams{
+			MaxFailures: 3,
+			ID:          webhook.ID,
+		})
+		require.NoError(t, err)
+	}
+	require.True(t, webhook.Disabled)
+	require.Equal(t, int32(3), webhook.ConsecutiveFailures)
+
+	webhook, err := testQueries.EnableWebhook(context.Background(), webhook.ID)
+	require.NoError(t, err)
+	require.False(t, webhook.Disabled)
+	require.Zero(t, webhook.ConsecutiveFailures)
+}
@@ -0,0 +1,13 @@
+package storetest
+
+import (
+	"testing"
+
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+)
+
+func TestMemoryStore(t *testing.T) {
+	Run(t, func(t *testing.T) db.Store {
+		return db.NewMemoryStore()
+	})
+}
@@ -0,0 +1,865 @@
+// Package storetest is the conformance suite of db.Store. Every store runs
+// it, so that the in-memory store keeps behaving like the SQL one. The
+// suite only looks at the data it creates, so it can share a database with
+// other tests.
+package storetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Run runs the suite on the stores returned by newStore, which is called
+// once per test.
+func Run(t *testing.T, newStore func(t *testing.T) db.Store) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, store db.Store)
+	}{
+		{"Accounts", testAccounts},
+		{"Pots", testPots},
+		{"GeneratedAccountNumber", testGeneratedAccountNumber},
+		{"DeleteAccount", testDeleteAccount},
+		{"UnbalancedAccounts", testUnbalancedAccounts},
+		{"Users", testUsers},
+		{"Entries", testEntries},
+		{"TransferTx", testTransferTx},
+		{"TransferTxRollback", testTransferTxRollback},
+		{"TransferTxFrozenAccount", testTransferTxFrozenAccount},
+		{"TransferTxInsufficientFunds", testTransferTxInsufficientFunds},
+		{"TransferTxConcurrent", testTransferTxConcurrent},
+		{"TransferTxRoundUp", testTransferTxRoundUp},
+		{"ApprovalDecisionTx", testApprovalDecisionTx},
+		{"ApprovalQuorumUnreachable", testApprovalQuorumUnreachable},
+		{"CancelApprovalRequest", testCancelApprovalRequest},
+		{"RemoveMemberTx", testRemoveMemberTx},
+		{"ClaimDueWebhookDeliveries", testClaimDueWebhookDeliveries},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.test(t, newStore(t))
+		})
+	}
+}
+
+// missingID is an id no row has.
+const missingID = int64(-1)
+
+func createAccount(t *testing.T, store db.Store, balance int64) db.Account {
+	t.Helper()
+
+	result, err := store.CreateAccountTx(context.Background(), db.CreateAccountParams{
+		Owner:         utils.RandomOwner(),
+		Balance:       balance,
+		Currency:      "USD",
+		AccountNumber: utils.RandomAccountNumber(),
+	})
+	require.NoError(t, err)
+	return result.Account
+}
+
+func requireBalance(t *testing.T, store db.Store, accountID int64, balance int64) {
+	t.Helper()
+
+	account, err := store.GetAccountById(context.Background(), accountID)
+	require.NoError(t, err)
+	require.Equal(t, balance, account.Balance)
+}
+
+// requireOnlyOpeningEntry checks that no transfer booked an entry of the account.
+func requireOnlyOpeningEntry(t *testing.T, store db.Store, accountID int64) {
+	t.Helper()
+
+	entries, err := store.ListEntries(context.Background(), db.ListEntriesParams{AccountID: accountID, Limit: 5})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.True(t, entries[0].Opening)
+}
+
+func testAccounts(t *testing.T, store db.Store) {
+	ctx := context.Background()
+	arg := db.CreateAccountParams{
+		Owner:         utils.RandomOwner(),
+		Balance:       utils.RandomMoney(),
+		Currency:      utils.RandomCurrency(),
+		AccountNumber: utils.RandomAccountNumber(),
+	}
+
+	result, err := store.CreateAccountTx(ctx, arg)
+	require.NoError(t, err)
+	account := result.Account
+	require.NotZero(t, account.ID)
+	require.Equal(t, arg.Owner, account.Owner)
+	require.Equal(t, arg.Balance, account.Balance)
+	require.Equal(t, arg.Currency, account.Currency)
+	require.Equal(t, arg.AccountNumber, account.AccountNumber)
+	require.False(t, account.ParentID.Valid)
+	require.True(t, account.CreatedAt.Valid)
+
+	require.Equal(t, db.AccountMember{
+		AccountID: account.ID,
+		Username:  arg.Owner,
+		Role:      db.RoleOwner,
+		CreatedAt: result.Member.CreatedAt,
+	}, result.Member)
+
+	// the opening balance is booked as an entry
+	require.NotNil(t, result.OpeningEntry)
+	require.Equal(t, account.ID, result.OpeningEntry.AccountID)
+	require.Equal(t, arg.Balance, result.OpeningEntry.Amount)
+	require.True(t, result.OpeningEntry.Opening)
+	entries, err := store.ListEntries(ctx, db.ListEntriesParams{AccountID: account.ID, Limit: 5})
+	require.NoError(t, err)
+	require.Equal(t, []db.Entry{*result.OpeningEntry}, entries)
+
+	got, err := store.GetAccountById(ctx, account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account, got)
+
+	got, err = store.GetAccountByNumber(ctx, account.AccountNumber)
+	require.NoError(t, err)
+	require.Equal(t, account, got)
+
+	accounts, err := store.ListMemberAccounts(ctx, db.ListMemberAccountsParams{
+		Username: arg.Owner,
+		Limit:    5,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []db.Account{account}, accounts)
+
+	_, err = store.GetAccountById(ctx, missingID)
+	require.ErrorIs(t, err, db.ErrRecordNotFound)
+
+	_, err = store.UpdateAccountBalanceByID(ctx, db.UpdateAccountBalanceByIDParams{AccountID: missingID, Amount: 1})
+	require.ErrorIs(t, err, db.ErrRecordNotFound)
+
+	updated, err := store.UpdateAccountBalanceByID(ctx, db.UpdateAccountBalanceByIDParams{
+		AccountID: account.ID,
+		Amount:    -10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account.Balance-10, updated.Balance)
+
+	// account numbers are unique, and the failed account is rolled back
+	// with its member
+	other := arg
+	other.Owner = utils.RandomOwner()
+	_, err = store.CreateAccountTx(ctx, other)
+	require.Equal(t, db.UniqueViolation, db.ErrorCode(err))
+
+	accounts, err = store.ListMemberAccounts(ctx, db.ListMemberAccountsParams{
+		Username: other.Owner,
+		Limit:    5,
+	})
+	require.NoError(t, err)
+	require.Empty(t, accounts)
+}
+
+func testPots(t *testing.T, store db.Store) {
+	ctx := context.Background()
+	parent := createAccount(t, store, 100)
+
+	var pots []db.Account
+	for i := 0; i < 2; i++ {
+		pot, err := store.CreateAccount(ctx, db.CreateAccountParams{
+			Owner:         parent.Owner,
+			Currency:      parent.Currency,
+			ParentID:      pgtype.Int8{Int64: parent.ID, Valid: true},
+			AccountNumber: utils.RandomAccountNumber(),
+		})
+		require.NoError(t, err)
+		pots = append(pots, pot)
+	}
+
+	got, err := store.ListAccountPots(ctx, pgtype.Int8{Int64: parent.ID, Valid: true})
+	require.NoError(t, err)
+	require.Equal(t, pots, got)
+
+	_, err = store.CreateAccount(ctx, db.CreateAccountParams{
+		Owner:         parent.Owner,
+		Currency:      parent.Currency,
+		ParentID:      pgtype.Int8{Int64: missingID, Valid: true},
+		AccountNumber: utils.RandomAccountNumber(),
+	})
+	require.Equal(t, db.ForeignKeyViolation, db.ErrorCode(err))
+
+	// a parent cannot go before its pots
+	err = store.DeleteAccountByID(ctx, parent.ID)
+	require.Equal(t, db.ForeignKeyViolation, db.ErrorCode(err))
+}
+
+func testGeneratedAccountNumber(t *testing.T, store db.Store) {
+	ctx := context.Background()
+	arg := db.CreateAccountParams{
+		Owner:    utils.RandomOwner(),
+		Currency: "USD",
+	}
+
+	result, err := store.CreateAccountTx(ctx, arg, db.WithGeneratedNumber("GB", "GOBK"))
+	require.NoError(t, err)
+	require.NoError(t, utils.ValidateAccountNumber(result.Account.AccountNumber))
+	require.Equal(t, "GB", result.Account.AccountNumber[:2])
+
+	// without a balance there is nothing to book
+	require.Nil(t, result.OpeningEntry)
+
+	got, err := store.GetAccountByNumber(ctx, result.Account.AccountNumber)
+	require.NoError(t, err)
+	require.Equal(t, result.Account, got)
+}
+
+func testDeleteAccount(t *testing.T, store db.Store) {
+	ctx := context.Background()
+	account := createAccount(t, store, 0)
+
+	_, err := store.FreezeAccount(ctx, db.FreezeAccountParams{AccountID: account.ID, Reason: "closing"})
+	require.NoError(t, err)
+
+	// members and freezes go with the account
+	require.NoError(t, store.DeleteAccountByID(ctx, account.ID))
+
+	_, err = store.GetAccountById(ctx, account.ID)
+	require.ErrorIs(t, err, db.ErrRecordNotFound)
+	_, err = store.GetAccountMember(ctx, db.GetAccountMemberParams{AccountID: account.ID, Username: account.Owner})
+	require.ErrorIs(t, err, db.ErrRecordNotFound)
+	freezes, err := store.ListAccountFreezes(ctx, []int64{account.ID})
+	require.NoError(t, err)
+	require.Empty(t, freezes)
+
+	// deleting a missing account is not an error
+	require.NoError(t, store.DeleteAccountByID(ctx, account.ID))
+
+	// entries keep their account, including its opening entry
+	account = createAccount(t, store, 100)
+	err = store.DeleteAccountByID(ctx, account.ID)
+	require.Equal(t, db.ForeignKeyViolation, db.ErrorCode(err))
+}
+
+func testUnbalancedAccounts(t *testing.T, store db.Store) {
+	ctx := context.Background()
+	balanced := createAccount(t, store, 100)
+
+	// an account created without its opening entry holds money no entry
+	// accounts for
+	account, err := store.CreateAccount(ctx, db.CreateAccountParams{
+		Owner:         utils.RandomOwner(),
+		Balance:       50,
+		Currency:      "USD",
+		AccountNumber: utils.RandomAccountNumber(),
+	})
+	require.NoError(t, err)
+
+	totals, err := store.GetLedgerTotals(ctx)
+	require.NoError(t, err)
+	require.NotZero(t, totals.UnbalancedAccounts)
+
+	unbalanced := listUnbalanced(t, store)
+	require.Contains(t, unbalanced, db.ListUnbalancedAccountsRow{
+		ID:            account.ID,
+		AccountNumber: account.AccountNumber,
+		Balance:       50,
+		EntryTotal:    0,
+	})
+	for _, row := range unbalanced {
+		require.NotEqual(t, balanced.ID, row.ID)
+	}
+
+	_, err = store.CreateOpeningEntry(ctx, db.CreateOpeningEntryParams{AccountID: account.ID, Amount: 50})
+	require.NoError(t, err)
+	for _, row := range listUnbalanced(t, store) {
+		require.NotEqual(t, account.ID, row.ID)
+	}
+
+	after, err := store.GetLedgerTotals(ctx)
+	require.NoError(t, err)
+	require.Equal(t, totals.UnbalancedAccounts-1, after.UnbalancedAccounts)
+	require.Equal(t, totals.OpeningCount+1, after.OpeningCount)
+	require.Equal(t, totals.OpeningTotal+50, after.OpeningTotal)
+}
+
+func listUnbalanced(t *testing.T, store db.Store) []db.ListUnbalancedAccountsRow {
+	t.Helper()
+
+	unbalanced, err := store.ListUnbalancedAccounts(context.Background(), 1000)
+	require.NoError(t, err)
+	return unbalanced
+}
+
+func testUsers(t *testing.T, store db.Store) {
+	ctx := context.Background()
+	arg := db.CreateUserParams{
+		Username: utils.RandomOwner(),
+		FullName: "Ada Lovelace",
+		Email:    utils.RandomOwner() + "@example.com",
+	}
+
+	user, err := store.CreateUser(ctx, arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Username, user.Username)
+	require.True(t, user.CreatedAt.Valid)
+
+	got, err := store.GetUser(ctx, user.Username)
+	require.NoError(t, err)
+	require.Equal(t, user, got)
+
+	_, err = store.GetUser(ctx, utils.RandomOwner())
+	require.ErrorIs(t, err, db.ErrRecordNotFound)
+
+	sameName := arg
+	sameName.Email = utils.RandomOwner() + "@example.com"
+	_, err = store.CreateUser(ctx, sameName)
+	require.Equal(t, db.UniqueViolation, db.ErrorCode(err))
+
+	sameEmail := arg
+	sameEmail.Username = utils.RandomOwner()
+	_, err = store.CreateUser(ctx, sameEmail)
+	require.Equal(t, db.UniqueViolation, db.ErrorCode(err))
+}
+
+func testEntries(t *testing.T, store db.Store) {
+	ctx := context.Background()
+	account := createAccount(t, store, 0)
+
+	_, err := store.CreateEntry(ctx, db.CreateEntryParams{AccountID: missingID, Amount: 10})
+	require.Equal(t, db.ForeignKeyViolation, db.ErrorCode(err))
+
+	var entries []db.Entry
+	for i := 1; i <= 6; i++ {
+		entry, err := store.CreateEntry(ctx, db.CreateEntryParams{AccountID: account.ID, Amount: int64(i)})
+		require.NoError(t, err)
+		require.Equal(t, account.ID, entry.AccountID)
+		require.True(t, entry.CreatedAt.Valid)
+		entries = append(entries, entry)
+
+		_, err = store.UpdateAccountBalanceByID(ctx, db.UpdateAccountBalanceByIDParams{AccountID: account.ID, Amount: int64(i)})
+		require.NoError(t, err)
+	}
+
+	got, err := store.GetEntry(ctx, entries[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, entries[0], got)
+
+	_, err = store.GetEntry(ctx, missingID)
+	require.ErrorIs(t, err, db.ErrRecordNotFound)
+
+	page, err := store.ListEntries(ctx, db.ListEntriesParams{AccountID: account.ID, Limit: 4, Offset: 2})
+	require.NoError(t, err)
+	require.Equal(t, entries[2:], page)
+
+	page, err = store.ListEntries(ctx, db.ListEntriesParams{AccountID: account.ID, Limit: 5, Offset: 10})
+	require.NoError(t, err)
+	require.NotNil(t, page)
+	require.Empty(t, page)
+
+	// the balance after each entry: 1, 3, 6, 10, 15, 21
+	after, err := store.ListAccountEntriesAfter(ctx, db.ListAccountEntriesAfterParams{
+		AccountID: account.ID,
+		AfterID:   entries[3].ID,
+	})
+	require.NoError(t, err)
+	require.Len(t, after, 2)
+	require.Equal(t, entries[4].ID, after[0].ID)
+	require.Equal(t, int64(15), after[0].Balance)
+	require.Equal(t, int64(21), after[1].Balance)
+}
+
+func testTransferTx(t *testing.T, store db.Store) {
+	ctx := context.Background()
+	account1 := createAccount(t, store, 100)
+	account2 := createAccount(t, store, 100)
+
+	before, err := store.GetLedgerTotals(ctx)
+	require.NoError(t, err)
+
+	result, err := store.TransferTx(ctx, db.TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        30,
+	})
+	require.NoError(t, err)
+
+	transfer := result.Transfer
+	require.NotZero(t, transfer.ID)
+	require.Equal(t, account1.ID, transfer.FromAccountID)
+	require.Equal(t, account2.ID, transfer.ToAccountID)
+	require.Equal(t, int64(30), transfer.Amount)
+	require.Nil(t, result.RoundUp)
+
+	require.Equal(t, account1.ID, result.FromEntry.AccountID)
+	require.Equal(t, int64(-30), result.FromEntry.Amount)
+	require.Equal(t, account2.ID, result.ToEntry.AccountID)
+	require.Equal(t, int64(30), result.ToEntry.Amount)
+
+	require.Equal(t, account1.ID, result.FromAccount.ID)
+	require.Equal(t, int64(70), result.FromAccount.Balance)
+	require.Equal(t, account2.ID, result.ToAccount.ID)
+	require.Equal(t, int64(130), result.ToAccount.Balance)
+
+	gotTransfer, err := store.GetTransfer(ctx, transfer.ID)
+	require.NoError(t, err)
+	require.Equal(t, transfer, gotTransfer)
+
+	gotEntry, err := store.GetEntry(ctx, result.FromEntry.ID)
+	require.NoError(t, err)
+	require.Equal(t, result.FromEntry, gotEntry)
+
+	requireBalance(t, store, account1.ID, 70)
+	requireBalance(t, store, account2.ID, 130)
+
+	for _, arg := range []db.ListTransfersParams{
+		{FromAccountID: account1.ID, ToAccountID: account1.ID, Limit: 5},
+		{FromAccountID: account2.ID, ToAccountID: account2.ID, Limit: 5},
+	} {
+		transfers, err := store.ListTransfers(ctx, arg)
+		require.NoError(t, err)
+		require.Equal(t, []db.Transfer{transfer}, transfers)
+	}
+
+	_, err = store.GetTransfer(ctx, missingID)
+	require.ErrorIs(t, err, db.ErrRecordNotFound)
+
+	after, err := store.GetLedgerTotals(ctx)
+	require.NoError(t, err)
+	require.Equal(t, before.EntryCount+2, after.EntryCount)
+	require.Equal(t, before.EntryTotal, after.EntryTotal)
+	require.Equal(t, before.TransferCount+1, after.TransferCount)
+	require.Equal(t, before.TransferTotal+30, after.TransferTotal)
+}
+
+func testTransferTxRollback(t *testing.T, store db.Store) {
+	ctx := context.Background()
+	account := createAccount(t, store, 100)
+
+	before, err := store.GetLedgerTotals(ctx)
+	require.NoError(t, err)
+
+	for _, arg := range []db.TransferTxParams{
+		{FromAccountID: account.ID, ToAccountID: missingID, Amount: 10},
+		{FromAccountID: missingID, ToAccountID: account.ID, Amount: 10},
+	} {
+		_, err := store.TransferTx(ctx, arg)
+		require.Equal(t, db.ForeignKeyViolation, db.ErrorCode(err))
+	}
+
+	requireBalance(t, store, account.ID, 100)
+
+	requireOnlyOpeningEntry(t, store, account.ID)
+
+	after, err := store.GetLedgerTotals(ctx)
+	require.NoError(t, err)
+	require.Equal(t, before, after)
+}
+
+func testTransferTxFrozenAccount(t *testing.T, store db.Store) {
+	ctx := context.Background()
+	account1 := createAccount(t, store, 100)
+	account2 := createAccount(t, store, 100)
+
+	freeze, err := store.FreezeAccount(ctx, db.FreezeAccountParams{AccountID: account2.ID, Reason: "fraud review"})
+	require.NoError(t, err)
+	require.Equal(t, "fraud review", freeze.Reason)
+
+	_, err = store.FreezeAccount(ctx, db.FreezeAccountParams{AccountID: missingID, Reason: "fraud review"})
+	require.Equal(t, db.ForeignKeyViolation, db.ErrorCode(err))
+
+	for _, arg := range []db.TransferTxParams{
+		{FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 10},
+		{FromAccountID: account2.ID, ToAccountID: account1.ID, Amount: 10},
+	} {
+		_, err := store.TransferTx(ctx, arg)
+		require.ErrorIs(t, err, db.ErrAccountFrozen)
+		require.ErrorContains(t, err, "fraud review")
+	}
+	requireBalance(t, store, account1.ID, 100)
+	requireBalance(t, store, account2.ID, 100)
+
+	require.NoError(t, store.UnfreezeAccount(ctx, account2.ID))
+	_, err = store.TransferTx(ctx, db.TransferTxParams{FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 10})
+	require.NoError(t, err)
+	requireBalance(t, store, account2.ID, 110)
+}
+
+// testTransferTxInsufficientFunds overdraws an account, alone and with
+// concurrent transfers that only fit its balance one at a time.
+func testTransferTxInsufficientFunds(t *testing.T, store db.Store) {
+	ctx := context.Background()
+	account1 := createAccount(t, store, 100)
+	account2 := createAccount(t, store, 0)
+
+	_, err := store.TransferTx(ctx, db.TransferTxParams{FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 101})
+	require.ErrorIs(t, err, db.ErrInsufficientFunds)
+	requireBalance(t, store, account1.ID, 100)
+	requireBalance(t, store, account2.ID, 0)
+
+	requireOnlyOpeningEntry(t, store, account1.ID)
+
+	n := 5
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.TransferTx(ctx, db.TransferTxParams{FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 40})
+			errs <- err
+		}()
+	}
+
+	var succeeded int
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if !errors.Is(err, db.ErrInsufficientFunds) {
+			require.NoError(t, err)
+			succeeded++
+		}
+	}
+	require.Equal(t, 2, succeeded)
+	requireBalance(t, store, account1.ID, 20)
+	requireBalance(t, store, account2.ID, 80)
+}
+
+// testTransferTxConcurrent runs transfers in opposite directions and around
+// a cycle at once. With SQLStore they must neither deadlock nor lose an
+// update.
+func testTransferTxConcurrent(t *testing.T, store db.Store) {
+	ctx := context.Background()
+	accounts := []db.Account{
+		createAccount(t, store, 1000),
+		createAccount(t, store, 1000),
+		createAccount(t, store, 1000),
+	}
+
+	n := 30
+	amount := int64(10)
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		// A→B, B→A, then B→C, C→A and A→B around the cycle
+		var from, to db.Account
+		switch i % 5 {
+		case 0:
+			from, to = accounts[0], accounts[1]
+		case 1:
+			from, to = accounts[1], accounts[0]
+		case 2:
+			from, to = accounts[1], accounts[2]
+		case 3:
+			from, to = accounts[2], accounts[0]
+		case 4:
+			from, to = accounts[0], accounts[1]
+		}
+
+		go func() {
+			_, err := store.TransferTx(ctx, db.TransferTxParams{
+				FromAccountID: from.ID,
+				ToAccountID:   to.ID,
+				Amount:        amount,
+			})
+			if err != nil {
+				err = fmt.Errorf("transfer from [%d] to [%d]: %w", from.ID, to.ID, err)
+			}
+			errs <- err
+		}()
+	}
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+	}
+
+	// every account received as much as it sent
+	for _, account := range accounts {
+		requireBalance(t, store, account.ID, 1000)
+	}
+
+	var total int64
+	for _, account := range accounts {
+		entries, err := store.ListEntries(ctx, db.ListEntriesParams{AccountID: account.ID, Limit: int32(2*n + 1)})
+		require.NoError(t, err)
+		for _, entry := range entries {
+			if !entry.Opening {
+				total += entry.Amount
+			}
+		}
+	}
+	require.Zero(t, total)
+}
+
+func testTransferTxRoundUp(t *testing.T, store db.Store) {
+	ctx := context.Background()
+	account := createAccount(t, store, 100)
+	other := createAccount(t, store, 0)
+	pot, err := store.CreateAccount(ctx, db.CreateAccountParams{
+		Owner:         account.Owner,
+		Currency:      account.Currency,
+		ParentID:      pgtype.Int8{Int64: account.ID, Valid: true},
+		AccountNumber: utils.RandomAccountNumber(),
+	})
+	require.NoError(t, err)
+
+	_, err = store.UpsertRoundUpRule(ctx, db.UpsertRoundUpRuleParams{AccountID: account.ID, PotID: pot.ID, RoundTo: 1})
+	require.Equal(t, db.CheckViolation, db.ErrorCode(err))
+
+	_, err = store.UpsertRoundUpRule(ctx, db.UpsertRoundUpRuleParams{AccountID: account.ID, PotID: pot.ID, RoundTo: 10})
+	require.NoError(t, err)
+
+	result, err := store.TransferTx(ctx, db.TransferTxParams{
+		FromAccountID: account.ID,
+		ToAccountID:   other.ID,
+		Amount:        23,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.RoundUp)
+	require.Equal(t, pot.ID, result.RoundUp.ToAccountID)
+	require.Equal(t, int64(7), result.RoundUp.Amount)
+	require.Equal(t, int64(70), result.FromAccount.Balance)
+	requireBalance(t, store, pot.ID, 7)
+
+	// moving money into the pot is not rounded up
+	result, err = store.PotTransferTx(ctx, db.TransferTxParams{
+		FromAccountID: account.ID,
+		ToAccountID:   pot.ID,
+		Amount:        3,
+	})
+	require.NoError(t, err)
+	require.Nil(t, result.RoundUp)
+	requireBalance(t, store, pot.ID, 10)
+
+	// nor can a pot be emptied beyond its balance
+	_, err = store.PotTransferTx(ctx, db.TransferTxParams{
+		FromAccountID: pot.ID,
+		ToAccountID:   account.ID,
+		Amount:        11,
+	})
+	require.ErrorIs(t, err, db.ErrInsufficientFunds)
+	requireBalance(t, store, pot.ID, 10)
+}
+
+func testApprovalDecisionTx(t *testing.T, store db.Store) {
+	ctx := context.Background()
+	from := createAccount(t, store, 1000)
+	to := createAccount(t, store, 0)
+	approvers := []string{utils.RandomOwner(), utils.RandomOwner(), utils.RandomOwner()}
+
+	_, err := store.UpsertApprovalPolicy(ctx, db.UpsertApprovalPolicyParams{
+		AccountID:         from.ID,
+		Threshold:         100,
+		RequiredApprovals: 2,
+		Approvers:         approvers,
+	})
+	require.NoError(t, err)
+
+	request, err := store.CreateApprovalRequest(ctx, db.CreateApprovalRequestParams{
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        500,
+		RequestedBy:   approvers[2],
+	})
+	require.NoError(t, err)
+	require.Equal(t, db.ApprovalPending, request.Status)
+	require.Equal(t, approvers[2], request.RequestedBy)
+
+	decide := func(approver string, approved bool) (db.ApprovalDecisionTxResult, error) {
+		return store.ApprovalDecisionTx(ctx, db.ApprovalDecisionTxParams{
+			RequestID: request.ID,
+			Approver:  approver,
+			Approved:  approved,
+		})
+	}
+
+	_, err = decide(utils.RandomOwner(), true)
+	require.ErrorIs(t, err, db.ErrNotEligibleApprover)
+
+	// the maker cannot approve their own transfer
+	_, err = decide(approvers[2], true)
+	require.ErrorIs(t, err, db.ErrNotEligibleApprover)
+
+	result, err := decide(approvers[0], true)
+	require.NoError(t, err)
+	require.Equal(t, db.ApprovalPending, result.Request.Status)
+	require.Nil(t, result.Transfer)
+
+	_, err = decide(approvers[0], true)
+	require.ErrorIs(t, err, db.ErrAlreadyDecided)
+
+	result, err = decide(approvers[1], true)
+	require.NoError(t, err)
+	require.Equal(t, db.ApprovalApproved, result.Request.Status)
+	require.NotNil(t, result.Transfer)
+	require.Equal(t, pgtype.Int8{Int64: result.Transfer.Transfer.ID, Valid: true}, result.Request.TransferID)
+	requireBalance(t, store, from.ID, 500)
+	requireBalance(t, store, to.ID, 500)
+
+	_, err = decide(approvers[2], false)
+	require.ErrorIs(t, err, db.ErrApprovalNotPending)
+
+	decisions, err := store.ListApprovalDecisions(ctx, request.ID)
+	require.NoError(t, err)
+	require.Len(t, decisions, 2)
+
+	_, err = store.ApprovalDecisionTx(ctx, db.ApprovalDecisionTxParams{RequestID: missingID, Approver: approvers[0]})
+	require.ErrorIs(t, err, db.ErrRecordNotFound)
+}
+
+func testApprovalQuorumUnreachable(t *testing.T, store db.Store) {
+	ctx := context.Background()
+	from := createAccount(t, store, 1000)
+	to := createAccount(t, store, 0)
+	approvers := []string{utils.RandomOwner(), utils.RandomOwner()}
+
+	policy := db.UpsertApprovalPolicyParams{
+		AccountID:         from.ID,
+		Threshold:         100,
+		RequiredApprovals: 2,
+		Approvers:         approvers,
+	}
+	_, err := store.UpsertApprovalPolicy(ctx, policy)
+	require.NoError(t, err)
+
+	request, err := store.CreateApprovalRequest(ctx, db.CreateApprovalRequestParams{
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        500,
+		RequestedBy:   approvers[1],
+	})
+	require.NoError(t, err)
+
+	// the maker is one of the two approvers the quorum needs
+	_, err = store.ApprovalDecisionTx(ctx, db.ApprovalDecisionTxParams{RequestID: request.ID, Approver: approvers[0], Approved: true})
+	require.ErrorIs(t, err, db.ErrQuorumUnreachable)
+
+	result, err := store.ApprovalDecisionTx(ctx, db.ApprovalDecisionTxParams{RequestID: request.ID, Approver: approvers[0]})
+	require.NoError(t, err)
+	require.Equal(t, db.ApprovalRejected, result.Request.Status)
+	requireBalance(t, store, from.ID, 1000)
+}
+
+func testCancelApprovalRequest(t *testing.T, store db.Store) {
+	ctx := context.Background()
+	from := createAccount(t, store, 1000)
+	to := createAccount(t, store, 0)
+	approver := utils.RandomOwner()
+
+	_, err := store.UpsertApprovalPolicy(ctx, db.UpsertApprovalPolicyParams{
+		AccountID:         from.ID,
+		Threshold:         100,
+		RequiredApprovals: 1,
+		Approvers:         []string{approver},
+	})
+	require.NoError(t, err)
+
+	request, err := store.CreateApprovalRequest(ctx, db.CreateApprovalRequestParams{
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        500,
+		RequestedBy:   from.Owner,
+	})
+	require.NoError(t, err)
+
+	cancelled, err := store.CancelApprovalRequest(ctx, request.ID)
+	require.NoError(t, err)
+	require.Equal(t, db.ApprovalCancelled, cancelled.Status)
+
+	// only pending requests are cancelled or decided
+	_, err = store.CancelApprovalRequest(ctx, request.ID)
+	require.ErrorIs(t, err, db.ErrRecordNotFound)
+	_, err = store.ApprovalDecisionTx(ctx, db.ApprovalDecisionTxParams{RequestID: request.ID, Approver: approver, Approved: true})
+	require.ErrorIs(t, err, db.ErrApprovalNotPending)
+	requireBalance(t, store, from.ID, 1000)
+
+	_, err = store.CancelApprovalRequest(ctx, missingID)
+	require.ErrorIs(t, err, db.ErrRecordNotFound)
+}
+
+func testRemoveMemberTx(t *testing.T, store db.Store) {
+	ctx := context.Background()
+	account := createAccount(t, store, 100)
+
+	coOwner := utils.RandomOwner()
+	_, err := store.CreateAccountMember(ctx, db.CreateAccountMemberParams{
+		AccountID: account.ID,
+		Username:  coOwner,
+		Role:      db.RoleOwner,
+	})
+	require.NoError(t, err)
+
+	err = store.RemoveMemberTx(ctx, db.DeleteAccountMemberParams{AccountID: account.ID, Username: utils.RandomOwner()})
+	require.ErrorIs(t, err, db.ErrRecordNotFound)
+
+	// of two owners removing each other at once, only one succeeds
+	errs := make(chan error, 2)
+	for _, username := range []string{account.Owner, coOwner} {
+		go func() {
+			errs <- store.RemoveMemberTx(ctx, db.DeleteAccountMemberParams{AccountID: account.ID, Username: username})
+		}()
+	}
+
+	var removed, refused int
+	for range 2 {
+		err := <-errs
+		if errors.Is(err, db.ErrLastOwner) {
+			refused++
+		} else {
+			require.NoError(t, err)
+			removed++
+		}
+	}
+	require.Equal(t, 1, removed)
+	require.Equal(t, 1, refused)
+
+	members, err := store.ListAccountMembers(ctx, account.ID)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	require.Equal(t, db.RoleOwner, members[0].Role)
+}
+
+func testClaimDueWebhookDeliveries(t *testing.T, store db.Store) {
+	ctx := context.Background()
+	account := createAccount(t, store, 0)
+
+	webhook, err := store.CreateWebhook(ctx, db.CreateWebhookParams{
+		Owner:      account.Owner,
+		Url:        "https://example.com/hook",
+		EventTypes: []string{db.EventTransferCompleted},
+		Secret:     "whsec_test",
+	})
+	require.NoError(t, err)
+
+	err = store.CreateWebhookDelivery(ctx, db.CreateWebhookDeliveryParams{
+		WebhookID: webhook.ID,
+		EventID:   1,
+		EventType: db.EventTransferCompleted,
+		Payload:   []byte(`{}`),
+	})
+	require.NoError(t, err)
+
+	arg := db.ClaimDueWebhookDeliveriesParams{BatchSize: 1000, LeaseSeconds: 60}
+	claimed := func(deliveries []db.WebhookDelivery) int {
+		n := 0
+		for _, delivery := range deliveries {
+			if delivery.WebhookID == webhook.ID {
+				n++
+			}
+		}
+		return n
+	}
+
+	// of two dispatchers claiming at once, only one gets the delivery
+	claims := make(chan int, 2)
+	for range 2 {
+		go func() {
+			deliveries, err := store.ClaimDueWebhookDeliveries(ctx, arg)
+			assert.NoError(t, err)
+			claims <- claimed(deliveries)
+		}()
+	}
+	require.Equal(t, 1, <-claims+<-claims)
+
+	// and it stays claimed for the lease
+	deliveries, err := store.ClaimDueWebhookDeliveries(ctx, arg)
+	require.NoError(t, err)
+	require.Zero(t, claimed(deliveries))
+}
@@ -0,0 +1,359 @@
+// Package stress runs randomized concurrent transfers against a db.Store
+// and then checks that the ledger is still consistent: no money was created
+// or lost, no account is overdrawn, every transfer booked its two entries
+// and no transfer failed with a deadlock or serialization failure that the
+// store should have resolved. Transfers refused for insufficient funds are
+// expected when the accounts run low.
+package stress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/utils"
+)
+
+// Config describes a stress run. Zero fields take the defaults below.
+type Config struct {
+	// Seed makes the choice of accounts, amounts and patterns reproducible.
+	// The interleaving of the transfers is up to the scheduler.
+	Seed int64
+	// Duration is how long workers keep starting transfers.
+	Duration time.Duration
+	// Transfers stops the run early after this many transfers, if set.
+	Transfers int
+	Workers   int
+	Accounts  int
+	// Balance is the opening balance of every account. The default is large
+	// enough for the random walk of the run never to run an account dry; a
+	// balance of a few MaxAmount makes many transfers be refused for
+	// insufficient funds, racing the ones that drain the same account.
+	Balance int64
+	// MaxAmount bounds the amount of a single transfer.
+	MaxAmount int64
+}
+
+func (c Config) withDefaults() Config {
+	if c.Duration == 0 {
+		c.Duration = 5 * time.Second
+	}
+	if c.Workers == 0 {
+		c.Workers = 8
+	}
+	if c.Accounts == 0 {
+		c.Accounts = 10
+	}
+	if c.Balance == 0 {
+		c.Balance = 1_000_000
+	}
+	if c.MaxAmount == 0 {
+		c.MaxAmount = 100
+	}
+	return c
+}
+
+// Report is the outcome of a run. Problems lists every broken invariant and
+// is empty when the store passed.
+type Report struct {
+	Seed      int64         `json:"seed"`
+	Duration  time.Duration `json:"duration"`
+	Transfers int           `json:"transfers"`
+	// Pairs and Cycles count the opposite-direction pairs A→B, B→A and the
+	// cycles A→B→C→A run at once.
+	Pairs  int `json:"pairs"`
+	Cycles int `json:"cycles"`
+	// Rejected counts the transfers refused for insufficient funds, which
+	// are expected and not failures.
+	Rejected int `json:"rejected"`
+	Failures int `json:"failures"`
+	// Errors counts failed transfers by kind, see errorKind.
+	Errors map[string]int `json:"errors"`
+	// Retries counts the transactions the store retried by kind, if it
+	// reports them through a db.TxObserver. Deadlocks are problems even when
+	// retried.
+	Retries  map[string]int `json:"retries"`
+	Problems []string       `json:"problems"`
+}
+
+// run is the state shared by the workers of a run.
+type run struct {
+	store    db.Store
+	config   Config
+	accounts []db.Account
+
+	mu        sync.Mutex
+	report    Report
+	firstErrs map[string]error
+}
+
+// txObservable is a store that reports its transactions, like db.SQLStore.
+type txObservable interface {
+	SetTxObserver(observer db.TxObserver)
+}
+
+// Run creates the accounts of the run, sends transfers between them from
+// cfg.Workers goroutines for cfg.Duration, then checks the ledger of these
+// accounts. It only fails when the run cannot be set up or checked. A store
+// that reports its transactions reports them to the run instead, so that
+// its retries are counted.
+func Run(ctx context.Context, store db.Store, cfg Config) (Report, error) {
+	cfg = cfg.withDefaults()
+	if cfg.Accounts < 3 {
+		return Report{}, fmt.Errorf("need at least 3 accounts for cycles, got %d", cfg.Accounts)
+	}
+
+	r := &run{
+		store:     store,
+		config:    cfg,
+		report:    Report{Seed: cfg.Seed, Errors: map[string]int{}, Retries: map[string]int{}, Problems: []string{}},
+		firstErrs: map[string]error{},
+	}
+	if observable, ok := store.(txObservable); ok {
+		observable.SetTxObserver(r)
+	}
+
+	for i := 0; i < cfg.Accounts; i++ {
+		result, err := store.CreateAccountTx(ctx, db.CreateAccountParams{
+			Owner:         utils.RandomOwner(),
+			Balance:       cfg.Balance,
+			Currency:      "USD",
+			AccountNumber: utils.RandomAccountNumber(),
+		})
+		if err != nil {
+			return r.report, fmt.Errorf("create account: %w", err)
+		}
+		r.accounts = append(r.accounts, result.Account)
+	}
+
+	start := time.Now()
+	deadline := start.Add(cfg.Duration)
+	var wg sync.WaitGroup
+	for i := 0; i < cfg.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.work(ctx, utils.NewRandom(cfg.Seed+int64(i)), deadline)
+		}()
+	}
+	wg.Wait()
+	r.report.Duration = time.Since(start)
+
+	// no transfer may fail: running out of money is a rejection, and
+	// deadlocks or serialization failures must be resolved by the store
+	for _, kind := range slices.Sorted(maps.Keys(r.firstErrs)) {
+		r.problem("%d transfers failed with %s, first: %v", r.report.Errors[kind], kind, r.firstErrs[kind])
+	}
+	// TransferTx locks its accounts in order and never deadlocks: a deadlock
+	// the store resolved by retrying still means the ordering broke
+	if n := r.report.Retries[codeKind(db.DeadlockDetected)]; n > 0 {
+		r.problem("%d transactions deadlocked and were retried", n)
+	}
+	if err := r.check(ctx); err != nil {
+		return r.report, err
+	}
+	return r.report, nil
+}
+
+// work starts transfers until the deadline, the context or the transfer
+// budget runs out. Transfers that were started are let finish, so that
+// none is cancelled half way.
+func (r *run) work(ctx context.Context, rand *utils.Random, deadline time.Time) {
+	for ctx.Err() == nil && time.Now().Before(deadline) && !r.budgetSpent() {
+		accounts := r.pick(rand, 3)
+		amount := rand.Int64(1, r.config.MaxAmount+1)
+
+		switch p := rand.Float64(); {
+		case p < 0.2:
+			r.concurrently(
+				transfer(accounts[0], accounts[1], amount),
+				transfer(accounts[1], accounts[0], rand.Int64(1, r.config.MaxAmount+1)),
+			)
+			r.count(func(report *Report) { report.Pairs++ })
+		case p < 0.4:
+			r.concurrently(
+				transfer(accounts[0], accounts[1], amount),
+				transfer(accounts[1], accounts[2], amount),
+				transfer(accounts[2], accounts[0], amount),
+			)
+			r.count(func(report *Report) { report.Cycles++ })
+		default:
+			r.concurrently(transfer(accounts[0], accounts[1], amount))
+		}
+	}
+}
+
+func transfer(from, to db.Account, amount int64) db.TransferTxParams {
+	return db.TransferTxParams{FromAccountID: from.ID, ToAccountID: to.ID, Amount: amount}
+}
+
+// pick returns n different accounts.
+func (r *run) pick(rand *utils.Random, n int) []db.Account {
+	accounts := make([]db.Account, len(r.accounts))
+	copy(accounts, r.accounts)
+	for i := 0; i < n; i++ {
+		j := rand.Int(i, len(accounts))
+		accounts[i], accounts[j] = accounts[j], accounts[i]
+	}
+	return accounts[:n]
+}
+
+// concurrently runs the transfers at once and waits for all of them. The
+// transfers do not use the run's context, which only bounds how long new
+// ones are started.
+func (r *run) concurrently(transfers ...db.TransferTxParams) {
+	var wg sync.WaitGroup
+	for _, arg := range transfers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := r.store.TransferTx(context.Background(), arg)
+			r.record(arg, err)
+		}()
+	}
+	wg.Wait()
+}
+
+func (r *run) record(arg db.TransferTxParams, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err == nil {
+		r.report.Transfers++
+		return
+	}
+	if errors.Is(err, db.ErrInsufficientFunds) {
+		r.report.Rejected++
+		return
+	}
+
+	r.report.Failures++
+	kind := errorKind(err)
+	r.report.Errors[kind]++
+	if _, ok := r.firstErrs[kind]; !ok {
+		r.firstErrs[kind] = fmt.Errorf("transfer from account [%d] to [%d]: %w", arg.FromAccountID, arg.ToAccountID, err)
+	}
+}
+
+// errorKind names the errors a transfer may fail with: deadlocks and
+// serialization failures, other Postgres errors by their code, and the
+// rest as other.
+func errorKind(err error) string {
+	return codeKind(db.ErrorCode(err))
+}
+
+// codeKind names a Postgres error code like errorKind.
+func codeKind(code string) string {
+	switch code {
+	case db.DeadlockDetected:
+		return "deadlock"
+	case db.SerializationFailure:
+		return "serialization_failure"
+	case "":
+		return "other"
+	default:
+		return code
+	}
+}
+
+func (r *run) count(fn func(report *Report)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fn(&r.report)
+}
+
+func (r *run) budgetSpent() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.config.Transfers > 0 && r.report.Transfers+r.report.Rejected+r.report.Failures >= r.config.Transfers
+}
+
+// ObserveTx implements db.TxObserver.
+func (r *run) ObserveTx(name string, duration time.Duration, outcome string) {}
+
+// ObserveTxRetry implements db.TxObserver and counts the retry.
+func (r *run) ObserveTxRetry(name string, code string) {
+	r.count(func(report *Report) { report.Retries[codeKind(code)]++ })
+}
+
+func (r *run) problem(format string, a ...any) {
+	r.report.Problems = append(r.report.Problems, fmt.Sprintf(format, a...))
+}
+
+// listPage is the page size used to read back transfers.
+const listPage = 1000
+
+// check reads back the accounts of the run with their entries and
+// transfers, and reports the invariants they break.
+func (r *run) check(ctx context.Context) error {
+	var total int64
+	transfers := make(map[int64]db.Transfer)
+
+	for _, account := range r.accounts {
+		current, err := r.store.GetAccountById(ctx, account.ID)
+		if err != nil {
+			return err
+		}
+		total += current.Balance
+		if current.Balance < 0 {
+			r.problem("account [%d] is overdrawn: %d", current.ID, current.Balance)
+		}
+
+		entries, err := r.store.ListAccountEntriesAfter(ctx, db.ListAccountEntriesAfterParams{AccountID: account.ID})
+		if err != nil {
+			return err
+		}
+		var booked int64
+		for _, entry := range entries {
+			booked += entry.Amount
+		}
+		if booked != current.Balance {
+			r.problem("account [%d] has balance %d, but its entries add up to %d", account.ID, current.Balance, booked)
+		}
+
+		accountTransfers, err := r.listTransfers(ctx, account.ID)
+		if err != nil {
+			return err
+		}
+		// one entry per transfer and one for the opening balance
+		if len(entries) != len(accountTransfers)+1 {
+			r.problem("account [%d] has %d entries for %d transfers and its opening balance", account.ID, len(entries), len(accountTransfers))
+		}
+		for _, transfer := range accountTransfers {
+			transfers[transfer.ID] = transfer
+		}
+	}
+
+	if opening := int64(len(r.accounts)) * r.config.Balance; total != opening {
+		r.problem("accounts hold %d in total instead of %d", total, opening)
+	}
+	if len(transfers) != r.report.Transfers {
+		r.problem("%d transfers were booked, but %d succeeded", len(transfers), r.report.Transfers)
+	}
+	return nil
+}
+
+// listTransfers returns all transfers from or to an account.
+func (r *run) listTransfers(ctx context.Context, accountID int64) ([]db.Transfer, error) {
+	var transfers []db.Transfer
+	for {
+		page, err := r.store.ListTransfers(ctx, db.ListTransfersParams{
+			FromAccountID: accountID,
+			ToAccountID:   accountID,
+			Limit:         listPage,
+			Offset:        int32(len(transfers)),
+		})
+		if err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, page...)
+		if len(page) < listPage {
+			return transfers, nil
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package stress
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunMemoryStore(t *testing.T) {
+	report, err := Run(context.Background(), db.NewMemoryStore(), Config{
+		Seed:      1,
+		Duration:  time.Second,
+		Transfers: 2000,
+	})
+	require.NoError(t, err)
+	require.Empty(t, report.Problems)
+	require.Zero(t, report.Failures)
+	require.GreaterOrEqual(t, report.Transfers, 2000)
+	require.Positive(t, report.Pairs)
+	require.Positive(t, report.Cycles)
+}
+
+func TestRunSmallBalances(t *testing.T) {
+	report, err := Run(context.Background(), db.NewMemoryStore(), Config{
+		Seed:      1,
+		Duration:  time.Second,
+		Transfers: 2000,
+		Balance:   150,
+		MaxAmount: 100,
+	})
+	require.NoError(t, err)
+	require.Empty(t, report.Problems)
+	require.Zero(t, report.Failures)
+	require.Positive(t, report.Transfers)
+	require.Positive(t, report.Rejected)
+}
+
+// retryingStore reports a retry with code for every fifth transfer, like a
+// SQL store whose transfers race each other.
+type retryingStore struct {
+	*db.MemoryStore
+	code      string
+	observer  db.TxObserver
+	transfers atomic.Int64
+}
+
+func (s *retryingStore) SetTxObserver(observer db.TxObserver) {
+	s.observer = observer
+}
+
+func (s *retryingStore) TransferTx(ctx context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+	if s.transfers.Add(1)%5 == 0 {
+		s.observer.ObserveTxRetry("transfer", s.code)
+	}
+	return s.MemoryStore.TransferTx(ctx, arg)
+}
+
+func TestRunCountsRetries(t *testing.T) {
+	store := &retryingStore{MemoryStore: db.NewMemoryStore(), code: db.SerializationFailure}
+	report, err := Run(context.Background(), store, Config{
+		Seed:      1,
+		Workers:   1,
+		Transfers: 100,
+	})
+	require.NoError(t, err)
+	require.Empty(t, report.Problems)
+	require.Equal(t, map[string]int{"serialization_failure": (report.Transfers + report.Rejected + report.Failures) / 5}, report.Retries)
+}
+
+func TestRunReportsDeadlocks(t *testing.T) {
+	store := &retryingStore{MemoryStore: db.NewMemoryStore(), code: db.DeadlockDetected}
+	report, err := Run(context.Background(), store, Config{
+		Seed:      1,
+		Workers:   1,
+		Transfers: 100,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"20 transactions deadlocked and were retried"}, report.Problems)
+	require.Equal(t, 20, report.Retries["deadlock"])
+}
+
+// brokenStore loses every tenth transfer's credit, like a store that
+// updates balances without locking.
+type brokenStore struct {
+	*db.MemoryStore
+	transfers atomic.Int64
+}
+
+func (s *brokenStore) TransferTx(ctx context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+	result, err := s.MemoryStore.TransferTx(ctx, arg)
+	if err != nil {
+		return result, err
+	}
+
+	if s.transfers.Add(1)%10 == 0 {
+		_, err = s.UpdateAccountBalanceByID(ctx, db.UpdateAccountBalanceByIDParams{
+			AccountID: arg.ToAccountID,
+			Amount:    -arg.Amount,
+		})
+	}
+	return result, err
+}
+
+func TestRunFindsProblems(t *testing.T) {
+	report, err := Run(context.Background(), &brokenStore{MemoryStore: db.NewMemoryStore()}, Config{
+		Seed:      1,
+		Workers:   1,
+		Transfers: 100,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, report.Problems)
+	require.Contains(t, report.Problems[0], "entries add up to")
+}
+
+func TestRunTooFewAccounts(t *testing.T) {
+	_, err := Run(context.Background(), db.NewMemoryStore(), Config{Accounts: 2})
+	require.Error(t, err)
+}
@@ -0,0 +1,33 @@
+package events
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryPublisher keeps published events in memory. It is meant for tests
+// and for wiring consumers that live in the same process.
+type MemoryPublisher struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+func (publisher *MemoryPublisher) Publish(ctx context.Context, event Event) error {
+	publisher.mu.Lock()
+	defer publisher.mu.Unlock()
+
+	publisher.events = append(publisher.events, event)
+	return nil
+}
+
+// Events returns the events published so far, oldest first.
+func (publisher *MemoryPublisher) Events() []Event {
+	publisher.mu.Lock()
+	defer publisher.mu.Unlock()
+
+	return append([]Event(nil), publisher.events...)
+}
@@ -0,0 +1,37 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+)
+
+// Event is a domain event as it is handed to publishers. Delivery is
+// at-least-once, so consumers should use ID to drop duplicates.
+type Event struct {
+	ID            int64           `json:"id"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   int64           `json:"aggregate_id"`
+	Type          string          `json:"type"`
+	Payload       json.RawMessage `json:"payload"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+func newEvent(outbox db.Outbox) Event {
+	return Event{
+		ID:            outbox.ID,
+		AggregateType: outbox.AggregateType,
+		AggregateID:   outbox.AggregateID,
+		Type:          outbox.EventType,
+		Payload:       outbox.Payload,
+		CreatedAt:     outbox.CreatedAt.Time,
+	}
+}
+
+// EventPublisher delivers events to the outside world. An event counts as
+// delivered once Publish returns nil; on error it is published again later.
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event) error
+}
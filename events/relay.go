@@ -0,0 +1,103 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+)
+
+const (
+	defaultBatchSize    = 100
+	defaultPollInterval = time.Second
+)
+
+// Relay moves events from the outbox to a publisher in order. The position of
+// the last published event is stored per consumer, so a restarted relay
+// resumes where it stopped and publishes at most the last event again.
+type Relay struct {
+	store     db.Store
+	publisher EventPublisher
+	consumer  string
+	logger    *slog.Logger
+
+	BatchSize    int32
+	PollInterval time.Duration
+}
+
+func NewRelay(store db.Store, publisher EventPublisher, consumer string) *Relay {
+	return &Relay{
+		store:        store,
+		publisher:    publisher,
+		consumer:     consumer,
+		logger:       slog.Default(),
+		BatchSize:    defaultBatchSize,
+		PollInterval: defaultPollInterval,
+	}
+}
+
+// SetLogger makes the relay log failed batches to logger.
+func (relay *Relay) SetLogger(logger *slog.Logger) {
+	relay.logger = logger
+}
+
+// Run relays events until ctx is cancelled. Failed batches are logged and
+// retried after the poll interval.
+func (relay *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(relay.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		n, err := relay.RelayBatch(ctx)
+		if err != nil && ctx.Err() == nil {
+			relay.logger.ErrorContext(ctx, "cannot relay events", slog.String("consumer", relay.consumer), slog.Any("error", err))
+		}
+		// a full batch means more events are probably waiting
+		if err == nil && n == int(relay.BatchSize) {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayBatch publishes the next batch of events after the consumer's offset
+// and returns how many of them were published.
+func (relay *Relay) RelayBatch(ctx context.Context) (int, error) {
+	offset, err := relay.store.GetOutboxOffset(ctx, relay.consumer)
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		return 0, err
+	}
+
+	outbox, err := relay.store.ListOutboxEvents(ctx, db.ListOutboxEventsParams{
+		Txid:    offset.Txid,
+		EventID: offset.EventID,
+		Limit:   relay.BatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for i, event := range outbox {
+		if err := relay.publisher.Publish(ctx, newEvent(event)); err != nil {
+			return i, err
+		}
+
+		err = relay.store.UpsertOutboxOffset(ctx, db.UpsertOutboxOffsetParams{
+			Consumer: relay.consumer,
+			Txid:     event.Txid,
+			EventID:  event.ID,
+		})
+		if err != nil {
+			return i + 1, err
+		}
+	}
+
+	return len(outbox), nil
+}
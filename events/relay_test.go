@@ -0,0 +1,119 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/singhJasvinder101/go_bank/db/mock"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+type failingPublisher struct {
+	published []Event
+	failAt    int64
+}
+
+func (publisher *failingPublisher) Publish(ctx context.Context, event Event) error {
+	if event.ID == publisher.failAt {
+		return errors.New("broker unavailable")
+	}
+	publisher.published = append(publisher.published, event)
+	return nil
+}
+
+func randomOutbox(txid int64, id int64) db.Outbox {
+	return db.Outbox{
+		ID:            id,
+		Txid:          txid,
+		AggregateType: db.AggregateTransfer,
+		AggregateID:   id,
+		EventType:     db.EventTransferCompleted,
+		Payload:       []byte(`{"amount":10}`),
+	}
+}
+
+func TestRelayBatch(t *testing.T) {
+	outbox := []db.Outbox{randomOutbox(7, 3), randomOutbox(8, 1), randomOutbox(8, 2)}
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	store := mockdb.NewMockStore(controller)
+
+	store.EXPECT().
+		GetOutboxOffset(gomock.Any(), gomock.Eq("test")).
+		Times(1).
+		Return(db.OutboxOffset{Consumer: "test", Txid: 5, EventID: 9}, nil)
+	store.EXPECT().
+		ListOutboxEvents(gomock.Any(), gomock.Eq(db.ListOutboxEventsParams{Txid: 5, EventID: 9, Limit: defaultBatchSize})).
+		Times(1).
+		Return(outbox, nil)
+	for _, event := range outbox {
+		store.EXPECT().
+			UpsertOutboxOffset(gomock.Any(), gomock.Eq(db.UpsertOutboxOffsetParams{Consumer: "test", Txid: event.Txid, EventID: event.ID})).
+			Times(1).
+			Return(nil)
+	}
+
+	publisher := NewMemoryPublisher()
+	n, err := NewRelay(store, publisher, "test").RelayBatch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, len(outbox), n)
+
+	published := publisher.Events()
+	require.Len(t, published, len(outbox))
+	for i, event := range published {
+		require.Equal(t, outbox[i].ID, event.ID)
+		require.Equal(t, outbox[i].EventType, event.Type)
+		require.JSONEq(t, string(outbox[i].Payload), string(event.Payload))
+	}
+}
+
+func TestRelayBatchPublishError(t *testing.T) {
+	outbox := []db.Outbox{randomOutbox(7, 1), randomOutbox(7, 2), randomOutbox(7, 3)}
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	store := mockdb.NewMockStore(controller)
+
+	store.EXPECT().
+		GetOutboxOffset(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.OutboxOffset{}, db.ErrRecordNotFound)
+	store.EXPECT().
+		ListOutboxEvents(gomock.Any(), gomock.Eq(db.ListOutboxEventsParams{Limit: defaultBatchSize})).
+		Times(1).
+		Return(outbox, nil)
+	// the offset only moves past events that were published
+	store.EXPECT().
+		UpsertOutboxOffset(gomock.Any(), gomock.Eq(db.UpsertOutboxOffsetParams{Consumer: "test", Txid: 7, EventID: 1})).
+		Times(1).
+		Return(nil)
+
+	publisher := &failingPublisher{failAt: 2}
+	n, err := NewRelay(store, publisher, "test").RelayBatch(context.Background())
+	require.Error(t, err)
+	require.Equal(t, 1, n)
+	require.Len(t, publisher.published, 1)
+}
+
+func TestStreamPublisher(t *testing.T) {
+	var buf bytes.Buffer
+	publisher := NewStreamPublisher(&buf)
+
+	for _, outbox := range []db.Outbox{randomOutbox(1, 1), randomOutbox(1, 2)} {
+		require.NoError(t, publisher.Publish(context.Background(), newEvent(outbox)))
+	}
+
+	decoder := json.NewDecoder(&buf)
+	for id := int64(1); id <= 2; id++ {
+		var event Event
+		require.NoError(t, decoder.Decode(&event))
+		require.Equal(t, id, event.ID)
+		require.Equal(t, db.EventTransferCompleted, event.Type)
+	}
+}
@@ -0,0 +1,48 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+// StreamPublisher writes every event as a line of JSON to a writer such as
+// stdout or an append-only file.
+type StreamPublisher struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	closer  io.Closer
+}
+
+func NewStreamPublisher(w io.Writer) *StreamPublisher {
+	return &StreamPublisher{encoder: json.NewEncoder(w)}
+}
+
+// NewFilePublisher appends events to the file at path, creating it if needed.
+func NewFilePublisher(path string) (*StreamPublisher, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	publisher := NewStreamPublisher(file)
+	publisher.closer = file
+	return publisher, nil
+}
+
+func (publisher *StreamPublisher) Publish(ctx context.Context, event Event) error {
+	publisher.mu.Lock()
+	defer publisher.mu.Unlock()
+
+	return publisher.encoder.Encode(event)
+}
+
+// Close closes the underlying file of a publisher made by NewFilePublisher.
+func (publisher *StreamPublisher) Close() error {
+	if publisher.closer == nil {
+		return nil
+	}
+	return publisher.closer.Close()
+}
@@ -0,0 +1,190 @@
+// Package fixture generates reproducible sample data for the bank: users
+// with realistic names, accounts in supported currencies and a history of
+// transfers that never overdraws an account. The same options always
+// generate the same data, so a seed reproduces a dataset in a database, a
+// test or a bug report.
+package fixture
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/utils"
+)
+
+// Options describe the dataset to generate.
+type Options struct {
+	Seed            int64
+	Users           int
+	AccountsPerUser int
+	Transfers       int
+	// CountryCode and BankCode make up the account numbers, GB and GOBK by
+	// default.
+	CountryCode string
+	BankCode    string
+}
+
+// Transfer moves Amount between two accounts of a Dataset, given by their
+// index in Dataset.Accounts.
+type Transfer struct {
+	From   int
+	To     int
+	Amount int64
+}
+
+// Dataset is the generated data, not yet stored.
+type Dataset struct {
+	Users     []db.CreateUserParams
+	Accounts  []db.CreateAccountParams
+	Transfers []Transfer
+}
+
+// Generate builds a dataset from opts. Every account is opened with a
+// balance, and transfers are only made between accounts of the same
+// currency and for at most the balance of the sender at that point.
+func Generate(opts Options) Dataset {
+	if opts.CountryCode == "" {
+		opts.CountryCode = "GB"
+	}
+	if opts.BankCode == "" {
+		opts.BankCode = "GOBK"
+	}
+
+	r := utils.NewRandom(opts.Seed)
+	var data Dataset
+
+	usernames := make(map[string]bool)
+	numbers := make(map[string]bool)
+	for i := 0; i < opts.Users; i++ {
+		first, last := r.Name()
+		var username string
+		for username == "" || usernames[username] {
+			username = fmt.Sprintf("%s%s%d", strings.ToLower(first), strings.ToLower(last), r.Int(10, 100))
+		}
+		usernames[username] = true
+
+		data.Users = append(data.Users, db.CreateUserParams{
+			Username: username,
+			FullName: first + " " + last,
+			Email:    username + "@example.com",
+		})
+
+		// a user rather opens accounts in different currencies
+		currencies := append([]string(nil), utils.SupportedCurrencies...)
+		r.Shuffle(len(currencies), func(i, j int) {
+			currencies[i], currencies[j] = currencies[j], currencies[i]
+		})
+		for j := 0; j < opts.AccountsPerUser; j++ {
+			var number string
+			for number == "" || numbers[number] {
+				number = r.AccountNumber(opts.CountryCode, opts.BankCode)
+			}
+			numbers[number] = true
+
+			data.Accounts = append(data.Accounts, db.CreateAccountParams{
+				Owner:         username,
+				Balance:       10 * r.Int64(10, 1000),
+				Currency:      currencies[j%len(currencies)],
+				AccountNumber: number,
+			})
+		}
+	}
+
+	data.Transfers = generateTransfers(r, data.Accounts, opts.Transfers)
+	return data
+}
+
+// generateTransfers makes up to n transfers, fewer when no account has a
+// counterpart in its currency or all senders run dry.
+func generateTransfers(r *utils.Random, accounts []db.CreateAccountParams, n int) []Transfer {
+	byCurrency := make(map[string][]int)
+	balances := make([]int64, len(accounts))
+	for i, account := range accounts {
+		byCurrency[account.Currency] = append(byCurrency[account.Currency], i)
+		balances[i] = account.Balance
+	}
+
+	var transfers []Transfer
+	for attempts := 0; len(transfers) < n && attempts < 10*n; attempts++ {
+		from := r.Int(0, len(accounts))
+		peers := byCurrency[accounts[from].Currency]
+		if balances[from] == 0 || len(peers) < 2 {
+			continue
+		}
+		to := peers[r.Int(0, len(peers))]
+		if to == from {
+			continue
+		}
+
+		// most payments are small, a few move a large part of the balance
+		f := r.Float64()
+		amount := 1 + int64(f*f*float64(balances[from]/2))
+		if amount >= 20 {
+			amount -= amount % 5
+		}
+
+		balances[from] -= amount
+		balances[to] += amount
+		transfers = append(transfers, Transfer{From: from, To: to, Amount: amount})
+	}
+	return transfers
+}
+
+// Balances returns the balance of every account after all transfers.
+func (d Dataset) Balances() []int64 {
+	balances := make([]int64, len(d.Accounts))
+	for i, account := range d.Accounts {
+		balances[i] = account.Balance
+	}
+	for _, transfer := range d.Transfers {
+		balances[transfer.From] -= transfer.Amount
+		balances[transfer.To] += transfer.Amount
+	}
+	return balances
+}
+
+// Loaded is a dataset as stored, in the order of the Dataset.
+type Loaded struct {
+	Users     []db.User
+	Accounts  []db.Account
+	Transfers []db.TransferTxResult
+}
+
+// Load stores data through store the way the API would: accounts with
+// their owner as member and opening entry, and every transfer with its two entries.
+func Load(ctx context.Context, store db.Store, data Dataset) (Loaded, error) {
+	var loaded Loaded
+
+	for _, arg := range data.Users {
+		user, err := store.CreateUser(ctx, arg)
+		if err != nil {
+			return loaded, fmt.Errorf("create user %s: %w", arg.Username, err)
+		}
+		loaded.Users = append(loaded.Users, user)
+	}
+
+	for _, arg := range data.Accounts {
+		result, err := store.CreateAccountTx(ctx, arg)
+		if err != nil {
+			return loaded, fmt.Errorf("create account %s: %w", arg.AccountNumber, err)
+		}
+		loaded.Accounts = append(loaded.Accounts, result.Account)
+	}
+
+	for _, transfer := range data.Transfers {
+		from, to := loaded.Accounts[transfer.From], loaded.Accounts[transfer.To]
+		result, err := store.TransferTx(ctx, db.TransferTxParams{
+			FromAccountID: from.ID,
+			ToAccountID:   to.ID,
+			Amount:        transfer.Amount,
+		})
+		if err != nil {
+			return loaded, fmt.Errorf("transfer from account [%d] to [%d]: %w", from.ID, to.ID, err)
+		}
+		loaded.Transfers = append(loaded.Transfers, result)
+	}
+
+	return loaded, nil
+}
@@ -0,0 +1,105 @@
+package fixture
+
+import (
+	"context"
+	"testing"
+
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/stretchr/testify/require"
+)
+
+var testOptions = Options{Seed: 42, Users: 20, AccountsPerUser: 3, Transfers: 200}
+
+func TestGenerateIsReproducible(t *testing.T) {
+	data := Generate(testOptions)
+	require.Equal(t, data, Generate(testOptions))
+
+	other := testOptions
+	other.Seed++
+	require.NotEqual(t, data, Generate(other))
+}
+
+func TestGenerate(t *testing.T) {
+	data := Generate(testOptions)
+	require.Len(t, data.Users, testOptions.Users)
+	require.Len(t, data.Accounts, testOptions.Users*testOptions.AccountsPerUser)
+	require.Len(t, data.Transfers, testOptions.Transfers)
+
+	usernames := make(map[string]bool)
+	for _, user := range data.Users {
+		require.False(t, usernames[user.Username], "duplicate username %s", user.Username)
+		usernames[user.Username] = true
+		require.Contains(t, user.FullName, " ")
+		require.Equal(t, user.Username+"@example.com", user.Email)
+	}
+
+	numbers := make(map[string]bool)
+	for _, account := range data.Accounts {
+		require.True(t, usernames[account.Owner])
+		require.Contains(t, utils.SupportedCurrencies, account.Currency)
+		require.Positive(t, account.Balance)
+		require.NoError(t, utils.ValidateAccountNumber(account.AccountNumber))
+		require.False(t, numbers[account.AccountNumber])
+		numbers[account.AccountNumber] = true
+	}
+
+	// replay the history to check that no account is ever overdrawn
+	balances := make([]int64, len(data.Accounts))
+	var total int64
+	for i, account := range data.Accounts {
+		balances[i] = account.Balance
+		total += account.Balance
+	}
+	for _, transfer := range data.Transfers {
+		from, to := data.Accounts[transfer.From], data.Accounts[transfer.To]
+		require.NotEqual(t, transfer.From, transfer.To)
+		require.Equal(t, from.Currency, to.Currency)
+		require.Positive(t, transfer.Amount)
+		require.LessOrEqual(t, transfer.Amount, balances[transfer.From])
+
+		balances[transfer.From] -= transfer.Amount
+		balances[transfer.To] += transfer.Amount
+	}
+	require.Equal(t, balances, data.Balances())
+
+	var after int64
+	for _, balance := range balances {
+		after += balance
+	}
+	require.Equal(t, total, after)
+}
+
+func TestGenerateWithoutCounterparts(t *testing.T) {
+	data := Generate(Options{Seed: 1, Users: 1, AccountsPerUser: 1, Transfers: 10})
+	require.Len(t, data.Accounts, 1)
+	require.Empty(t, data.Transfers)
+}
+
+func TestLoad(t *testing.T) {
+	data := Generate(testOptions)
+	store := db.NewMemoryStore()
+	loaded, err := Load(context.Background(), store, data)
+	require.NoError(t, err)
+
+	require.Len(t, loaded.Users, len(data.Users))
+	require.Len(t, loaded.Accounts, len(data.Accounts))
+	require.Len(t, loaded.Transfers, len(data.Transfers))
+
+	for i, balance := range data.Balances() {
+		account, err := store.GetAccountById(context.Background(), loaded.Accounts[i].ID)
+		require.NoError(t, err)
+		require.Equal(t, balance, account.Balance)
+	}
+
+	totals, err := store.GetLedgerTotals(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, totals.OpeningTotal, totals.EntryTotal)
+	require.Equal(t, 2*totals.TransferCount+totals.OpeningCount, totals.EntryCount)
+	require.Zero(t, totals.NegativeAccounts)
+	require.Zero(t, totals.UnbalancedAccounts)
+
+	// the same seed cannot be loaded twice into one store
+	_, err = Load(context.Background(), store, data)
+	require.Equal(t, db.UniqueViolation, db.ErrorCode(err))
+}
@@ -0,0 +1,22 @@
+// Package fixturetest loads fixture datasets in tests.
+package fixturetest
+
+import (
+	"context"
+	"testing"
+
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/fixture"
+)
+
+// Setup generates the dataset of opts and loads it into store, failing the
+// test on error.
+func Setup(t testing.TB, store db.Store, opts fixture.Options) fixture.Loaded {
+	t.Helper()
+
+	loaded, err := fixture.Load(context.Background(), store, fixture.Generate(opts))
+	if err != nil {
+		t.Fatalf("load fixture with seed %d: %v", opts.Seed, err)
+	}
+	return loaded
+}
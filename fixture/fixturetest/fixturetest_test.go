@@ -0,0 +1,19 @@
+package fixturetest
+
+import (
+	"testing"
+
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/fixture"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetup(t *testing.T) {
+	opts := fixture.Options{Seed: 7, Users: 3, AccountsPerUser: 2, Transfers: 10}
+	loaded := Setup(t, db.NewMemoryStore(), opts)
+
+	data := fixture.Generate(opts)
+	require.Len(t, loaded.Users, len(data.Users))
+	require.Len(t, loaded.Accounts, len(data.Accounts))
+	require.Len(t, loaded.Transfers, len(data.Transfers))
+}
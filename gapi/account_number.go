@@ -0,0 +1,20 @@
+package gapi
+
+import (
+	"context"
+	"strconv"
+)
+
+// accountRef resolves an account given either by its id or by its account
+// number. Exactly one of the two must be set. The gateway puts the account of
+// /v1/accounts/{account_number} paths into the number, where a plain number is
+// an account id as in the HTTP API.
+func (server *Server) accountRef(ctx context.Context, name string, id int64, number string) (int64, error) {
+	if number != "" && id == 0 {
+		if parsed, err := strconv.ParseInt(number, 10, 64); err == nil {
+			id, number = parsed, ""
+		}
+	}
+
+	return server.bank.AccountRef(ctx, name, id, number)
+}
@@ -0,0 +1,43 @@
+package gapi
+
+import (
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/pb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// The converters refer to accounts by their account number, numbers maps the
+// ids of the accounts involved to it, see db.AccountNumbers.
+
+func convertAccount(account db.Account, numbers map[int64]string) *pb.Account {
+	rsp := &pb.Account{
+		Owner:         account.Owner,
+		Balance:       account.Balance,
+		Currency:      account.Currency,
+		CreatedAt:     timestamppb.New(account.CreatedAt.Time),
+		AccountNumber: account.AccountNumber,
+	}
+	if account.ParentID.Valid {
+		rsp.ParentAccountNumber = numbers[account.ParentID.Int64]
+	}
+	return rsp
+}
+
+func convertEntry(entry db.Entry, numbers map[int64]string) *pb.Entry {
+	return &pb.Entry{
+		Id:            entry.ID,
+		AccountNumber: numbers[entry.AccountID],
+		Amount:        entry.Amount,
+		CreatedAt:     timestamppb.New(entry.CreatedAt.Time),
+	}
+}
+
+func convertTransfer(transfer db.Transfer, numbers map[int64]string) *pb.Transfer {
+	return &pb.Transfer{
+		Id:                transfer.ID,
+		FromAccountNumber: numbers[transfer.FromAccountID],
+		ToAccountNumber:   numbers[transfer.ToAccountID],
+		Amount:            transfer.Amount,
+		CreatedAt:         timestamppb.New(transfer.CreatedAt.Time),
+	}
+}
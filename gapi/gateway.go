@@ -0,0 +1,53 @@
+package gapi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/singhJasvinder101/go_bank/pb"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// NewGateway returns an HTTP handler translating JSON requests under /v1 into
+// calls on the gRPC server listening on grpcAddress. The Authorization,
+// X-Username and X-Consistency headers are forwarded as authorization,
+// x-username and x-consistency metadata; the gRPC server only trusts
+// x-username when the gateway's address is one of its auth proxies.
+func NewGateway(ctx context.Context, grpcAddress string) (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(func(key string) (string, bool) {
+			switch {
+			case strings.EqualFold(key, authorizationUserHeader):
+				return authorizationUserHeader, true
+			case strings.EqualFold(key, consistencyHeader):
+				return consistencyHeader, true
+			}
+			return runtime.DefaultHeaderMatcher(key)
+		}),
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions: protojson.MarshalOptions{
+				UseProtoNames:   true,
+				EmitUnpopulated: true,
+			},
+			UnmarshalOptions: protojson.UnmarshalOptions{
+				DiscardUnknown: true,
+			},
+		}),
+	)
+
+	// the client handler forwards the trace of the HTTP request to the server
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	}
+	if err := pb.RegisterGoBankHandlerFromEndpoint(ctx, mux, grpcAddress, opts); err != nil {
+		return nil, err
+	}
+
+	return mux, nil
+}
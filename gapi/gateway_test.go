@@ -0,0 +1,99 @@
+package gapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/singhJasvinder101/go_bank/db/mock"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestGateway serves server on a loopback listener behind a gateway.
+func newTestGateway(t *testing.T, server *Server) http.Handler {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	grpcServer := NewGRPCServer(server)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	gateway, err := NewGateway(ctx, listener.Addr().String())
+	require.NoError(t, err)
+	return gateway
+}
+
+func TestGateway(t *testing.T) {
+	user := utils.RandomOwner()
+	account := randomAccount(user)
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	store := mockdb.NewMockStore(controller)
+	store.EXPECT().
+		GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: user})).
+		Times(1).
+		Return(db.AccountMember{AccountID: account.ID, Username: user, Role: db.RoleOwner}, nil)
+	store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+
+	gateway := newTestGateway(t, newTestServer(t, store))
+
+	// requests without a user are refused before reaching the store
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/v1/accounts/%d", account.ID), nil)
+	gateway.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	// so are users named in X-Username, as the gateway is not an auth proxy
+	recorder = httptest.NewRecorder()
+	request = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/v1/accounts/%d", account.ID), nil)
+	request.Header.Set("X-Username", user)
+	gateway.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	request = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/v1/accounts/%d", account.ID), nil)
+	request.Header.Set("Authorization", "Bearer "+accessToken(t, user))
+	gateway.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	require.Equal(t, account.AccountNumber, got["account_number"])
+	require.Equal(t, account.Currency, got["currency"])
+}
+
+func TestGatewayAuthProxy(t *testing.T) {
+	user := utils.RandomOwner()
+	account := randomAccount(user)
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	store := mockdb.NewMockStore(controller)
+	store.EXPECT().
+		GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: user})).
+		Times(1).
+		Return(db.AccountMember{AccountID: account.ID, Username: user, Role: db.RoleOwner}, nil)
+	store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+
+	server := newTestServer(t, store)
+	server.SetAuth(testTokenMaker, []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")})
+	gateway := newTestGateway(t, server)
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/v1/accounts/%d", account.ID), nil)
+	request.Header.Set("X-Username", user)
+	gateway.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
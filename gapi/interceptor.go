@@ -0,0 +1,171 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/singhJasvinder101/go_bank/bank"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/pb"
+	"github.com/singhJasvinder101/go_bank/tracing"
+	"github.com/singhJasvinder101/go_bank/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// authorizationHeader is the metadata key carrying the bearer access token.
+// The gateway forwards the Authorization HTTP header under the same key.
+const (
+	authorizationHeader     = "authorization"
+	authorizationTypeBearer = "bearer"
+)
+
+// authorizationUserHeader is the metadata key an authenticating proxy names
+// the calling user with. The gateway forwards the X-Username HTTP header
+// under the same key.
+const authorizationUserHeader = "x-username"
+
+// consistencyHeader is the metadata key a read only call sets to strong to
+// read from the primary. The gateway forwards the X-Consistency HTTP header
+// under the same key.
+const (
+	consistencyHeader = "x-consistency"
+	strongConsistency = "strong"
+)
+
+// readOnlyMethods are the calls whose reads may be served by a replica.
+var readOnlyMethods = map[string]bool{
+	pb.GoBank_GetAccount_FullMethodName:   true,
+	pb.GoBank_ListAccounts_FullMethodName: true,
+	pb.GoBank_ListEntries_FullMethodName:  true,
+}
+
+// traceIDTrailer is the trailer carrying the trace id of a failed call.
+const traceIDTrailer = "x-trace-id"
+
+type authUserKey struct{}
+
+// AuthInterceptor identifies the user making the call by the bearer token in
+// the authorization metadata, mirroring the HTTP API's auth middleware. Calls
+// from an authenticating proxy may name the user in the x-username metadata
+// instead, which is ignored from every other peer.
+func (server *Server) AuthInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	username, err := server.authenticate(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	return handler(context.WithValue(ctx, authUserKey{}, username), req)
+}
+
+func (server *Server) authenticate(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(authorizationHeader); len(values) > 0 {
+		authType, accessToken, ok := strings.Cut(values[0], " ")
+		if !ok || !strings.EqualFold(authType, authorizationTypeBearer) {
+			return "", errors.New("invalid authorization metadata format")
+		}
+		if server.tokenMaker == nil {
+			return "", errors.New("access tokens are not accepted")
+		}
+		return server.tokenMaker.VerifyToken(accessToken)
+	}
+
+	if values := md.Get(authorizationUserHeader); len(values) > 0 && values[0] != "" && server.fromAuthProxy(ctx) {
+		return values[0], nil
+	}
+
+	return "", errors.New("authorization metadata is not provided")
+}
+
+// fromAuthProxy reports whether the call was made by an authenticating proxy.
+func (server *Server) fromAuthProxy(ctx context.Context) bool {
+	p, ok := peer.FromContext(ctx)
+	return ok && p.Addr != nil && utils.InNetworks(server.authProxies, p.Addr.String())
+}
+
+// authUser returns the username set by AuthInterceptor.
+func authUser(ctx context.Context) string {
+	username, _ := ctx.Value(authUserKey{}).(string)
+	return username
+}
+
+// ConsistencyInterceptor sends the reads of calls that change data, and of
+// calls asking for strong consistency, to the primary database, mirroring the
+// HTTP API's read-your-writes middleware.
+func ConsistencyInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(consistencyHeader)
+	strong := len(values) > 0 && strings.EqualFold(values[0], strongConsistency)
+	if !readOnlyMethods[info.FullMethod] || strong {
+		ctx = db.WithPrimary(ctx)
+	}
+	return handler(ctx, req)
+}
+
+// LoggerInterceptor logs every call with its status code and duration to the
+// logger of the server.
+func (server *Server) LoggerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	result, err := handler(ctx, req)
+
+	args := []any{
+		slog.String("method", info.FullMethod),
+		slog.String("code", status.Code(err).String()),
+		slog.Duration("duration", time.Since(start)),
+	}
+	if err != nil {
+		server.logger.WarnContext(ctx, "grpc call failed", append(args, slog.Any("error", err))...)
+	} else {
+		server.logger.InfoContext(ctx, "grpc call", args...)
+	}
+
+	return result, err
+}
+
+// ErrorInterceptor maps store errors returned by handlers to gRPC status
+// codes. Errors that already carry a status are passed through unchanged.
+// The trace id of a failed call is sent in the x-trace-id trailer.
+func ErrorInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	result, err := handler(ctx, req)
+	if err == nil {
+		return result, nil
+	}
+	if traceID := tracing.TraceID(ctx); traceID != "" {
+		grpc.SetTrailer(ctx, metadata.Pairs(traceIDTrailer, traceID))
+	}
+	return result, toStatusError(err)
+}
+
+func toStatusError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var invalid *bank.InvalidArgumentError
+	switch {
+	case errors.As(err, &invalid):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, bank.ErrAccessDenied), errors.Is(err, bank.ErrBeneficiaryNotAllowed):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, db.ErrRecordNotFound), errors.Is(err, bank.ErrBeneficiaryNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, db.ErrAccountFrozen), errors.Is(err, db.ErrInsufficientFunds),
+		errors.Is(err, bank.ErrCurrencyMismatch), errors.Is(err, bank.ErrPotTransfer), errors.Is(err, db.ErrQuorumUnreachable):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case db.ErrorCode(err) == db.UniqueViolation:
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,32 @@
+package gapi
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/singhJasvinder101/go_bank/db/mock"
+	"github.com/singhJasvinder101/go_bank/pb"
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLoggerInterceptor(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	var logs bytes.Buffer
+	server := newTestServer(t, mockdb.NewMockStore(controller))
+	server.SetLogger(slog.New(slog.NewTextHandler(&logs, nil)))
+	client := newTestServerClient(t, server)
+
+	_, err := client.GetAccount(withUser(t, utils.RandomOwner()), &pb.GetAccountRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	require.Contains(t, logs.String(), `msg="grpc call failed"`)
+	require.Contains(t, logs.String(), "method=/pb.GoBank/GetAccount")
+	require.Contains(t, logs.String(), "code=InvalidArgument")
+}
@@ -0,0 +1,88 @@
+package gapi
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/pb"
+	"github.com/singhJasvinder101/go_bank/token"
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestServer(t *testing.T, store db.Store) *Server {
+	config := utils.Config{
+		ACCOUNT_COUNTRY_CODE: "GB",
+		ACCOUNT_BANK_CODE:    "GOBK",
+	}
+
+	server := NewServer(config, store)
+	server.SetAuth(testTokenMaker, nil)
+	return server
+}
+
+// testTokenMaker signs the access tokens of test servers.
+var testTokenMaker = func() token.Maker {
+	maker, err := token.NewJWTMaker("12345678901234567890123456789012")
+	if err != nil {
+		panic(err)
+	}
+	return maker
+}()
+
+// accessToken returns an access token for username valid for a minute.
+func accessToken(t *testing.T, username string) string {
+	accessToken, err := testTokenMaker.CreateToken(username, time.Minute)
+	require.NoError(t, err)
+	return accessToken
+}
+
+// newTestClient serves the store over an in-memory gRPC connection with the
+// production interceptors installed.
+func newTestClient(t *testing.T, store db.Store) pb.GoBankClient {
+	return newTestServerClient(t, newTestServer(t, store))
+}
+
+// newTestServerClient serves server over an in-memory gRPC connection.
+func newTestServerClient(t *testing.T, server *Server) pb.GoBankClient {
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := NewGRPCServer(server)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewGoBankClient(conn)
+}
+
+func withUser(t *testing.T, username string) context.Context {
+	if username == "" {
+		return context.Background()
+	}
+	return metadata.AppendToOutgoingContext(context.Background(),
+		authorizationHeader, authorizationTypeBearer+" "+accessToken(t, username))
+}
+
+func randomAccount(owner string) db.Account {
+	return db.Account{
+		ID:            int64(utils.RandomInt(1, 10000)),
+		Owner:         owner,
+		Balance:       utils.RandomMoney(),
+		Currency:      utils.RandomCurrency(),
+		AccountNumber: utils.RandomAccountNumber(),
+	}
+}
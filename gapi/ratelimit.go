@@ -0,0 +1,108 @@
+package gapi
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net/netip"
+	"strconv"
+
+	"github.com/singhJasvinder101/go_bank/metrics"
+	"github.com/singhJasvinder101/go_bank/pb"
+	"github.com/singhJasvinder101/go_bank/ratelimit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// retryAfterHeader is the metadata key telling a limited caller how many
+// seconds to wait before calling again.
+const retryAfterHeader = "retry-after"
+
+// methodGroups puts calls into the route groups of the HTTP API, whose group
+// limits they share.
+var methodGroups = map[string]string{
+	pb.GoBank_CreateAccount_FullMethodName:  "accounts",
+	pb.GoBank_GetAccount_FullMethodName:     "accounts",
+	pb.GoBank_ListAccounts_FullMethodName:   "accounts",
+	pb.GoBank_ListEntries_FullMethodName:    "accounts",
+	pb.GoBank_CreateTransfer_FullMethodName: "transfers",
+}
+
+// SetRateLimits makes the server enforce limits with the buckets kept in
+// store. Calls are counted in buckets of their own, apart from those of the
+// HTTP API, so that gateway requests, which the HTTP API has limited
+// already, do not use up a limit twice.
+func (server *Server) SetRateLimits(store ratelimit.Store, limits ratelimit.Limits) {
+	server.rateLimiter = store
+	server.rateLimits = limits
+}
+
+// LimitIPInterceptor limits the calls of every peer IP. It runs before
+// AuthInterceptor so that unauthenticated calls are limited too. Loopback
+// peers are not limited: the gateway calls from there on behalf of every
+// client, whose IPs the HTTP API has limited already.
+func (server *Server) LimitIPInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return handler(ctx, req)
+	}
+	addrPort, err := netip.ParseAddrPort(p.Addr.String())
+	if err != nil || addrPort.Addr().IsLoopback() {
+		return handler(ctx, req)
+	}
+
+	if err := server.rateLimit(ctx, "ip", server.rateLimits.IP, addrPort.Addr().String()); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// LimitUserInterceptor limits the calls of the authenticated user, their
+// calls to each group and their transfers. It runs after AuthInterceptor.
+func (server *Server) LimitUserInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	user := authUser(ctx)
+	if err := server.rateLimit(ctx, "user", server.rateLimits.User, user); err != nil {
+		return nil, err
+	}
+
+	if group, ok := methodGroups[info.FullMethod]; ok {
+		if err := server.rateLimit(ctx, "group:"+group, server.rateLimits.Groups[group], user); err != nil {
+			return nil, err
+		}
+	}
+
+	if info.FullMethod == pb.GoBank_CreateTransfer_FullMethodName {
+		if err := server.rateLimit(ctx, "transfers", server.rateLimits.Transfers, user); err != nil {
+			return nil, err
+		}
+	}
+
+	return handler(ctx, req)
+}
+
+// rateLimit refuses the call with ResourceExhausted once the caller
+// identified by key has used up limit. Calls are let through when the store
+// fails, so that an outage of the limiter does not take the service down.
+func (server *Server) rateLimit(ctx context.Context, scope string, limit ratelimit.Limit, key string) error {
+	if !limit.Enabled() {
+		return nil
+	}
+
+	scope = "grpc:" + scope
+	result, err := server.rateLimiter.Take(ctx, scope+":"+key, limit)
+	if err != nil {
+		server.logger.ErrorContext(ctx, "cannot check rate limit", slog.String("scope", scope), slog.Any("error", err))
+		return nil
+	}
+	if result.Allowed {
+		return nil
+	}
+
+	metrics.RateLimited.WithLabelValues(scope).Inc()
+	retryAfter := int(math.Ceil(result.RetryAfter.Seconds()))
+	grpc.SetHeader(ctx, metadata.Pairs(retryAfterHeader, strconv.Itoa(retryAfter)))
+	return status.Errorf(codes.ResourceExhausted, "rate limit of %s requests exceeded", limit)
+}
@@ -0,0 +1,49 @@
+package gapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/singhJasvinder101/go_bank/db/mock"
+	"github.com/singhJasvinder101/go_bank/pb"
+	"github.com/singhJasvinder101/go_bank/ratelimit"
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestRateLimitInterceptors(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(controller))
+	server.SetRateLimits(ratelimit.NewMemoryStore(), ratelimit.Limits{
+		User:      ratelimit.Limit{Requests: 3, Per: time.Minute},
+		Transfers: ratelimit.Limit{Requests: 1, Per: time.Minute},
+	})
+	client := newTestServerClient(t, server)
+
+	// invalid calls are refused after the limiters, so they count
+	user := utils.RandomOwner()
+	transfer := &pb.CreateTransferRequest{Amount: -1, Currency: "USD"}
+	_, err := client.CreateTransfer(withUser(t, user), transfer)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	var header metadata.MD
+	_, err = client.CreateTransfer(withUser(t, user), transfer, grpc.Header(&header))
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	require.Equal(t, []string{"60"}, header.Get(retryAfterHeader))
+
+	_, err = client.GetAccount(withUser(t, user), &pb.GetAccountRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.GetAccount(withUser(t, user), &pb.GetAccountRequest{})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// the limits are per user
+	_, err = client.CreateTransfer(withUser(t, utils.RandomOwner()), transfer)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
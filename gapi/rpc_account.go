@@ -0,0 +1,75 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/singhJasvinder101/go_bank/bank"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/pb"
+)
+
+func (server *Server) CreateAccount(ctx context.Context, req *pb.CreateAccountRequest) (*pb.Account, error) {
+	if req.GetCurrency() == "" {
+		return nil, invalidArgument("currency is required")
+	}
+
+	result, err := server.bank.CreateAccount(ctx, db.CreateAccountParams{
+		Owner:    authUser(ctx),
+		Currency: req.GetCurrency(),
+		Balance:  0,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return convertAccount(result.Account, nil), nil
+}
+
+func (server *Server) GetAccount(ctx context.Context, req *pb.GetAccountRequest) (*pb.Account, error) {
+	accountID, err := server.accountRef(ctx, "account", req.GetId(), req.GetAccountNumber())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := server.authorizeAccount(ctx, accountID, bank.AnyMember); err != nil {
+		return nil, err
+	}
+
+	account, err := server.store.GetAccountById(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	numbers, err := db.AccountNumbers(ctx, server.store, []db.Account{account}, db.ParentIDs(account)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertAccount(account, numbers), nil
+}
+
+func (server *Server) ListAccounts(ctx context.Context, req *pb.ListAccountsRequest) (*pb.ListAccountsResponse, error) {
+	if err := validatePage(req.GetPageId(), req.GetPageSize()); err != nil {
+		return nil, err
+	}
+
+	accounts, err := server.store.ListMemberAccounts(ctx, db.ListMemberAccountsParams{
+		Username: authUser(ctx),
+		Limit:    req.GetPageSize(),
+		Offset:   (req.GetPageId() - 1) * req.GetPageSize(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	numbers, err := db.AccountNumbers(ctx, server.store, accounts, db.ParentIDs(accounts...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.ListAccountsResponse{}
+	for _, account := range accounts {
+		rsp.Accounts = append(rsp.Accounts, convertAccount(account, numbers))
+	}
+	return rsp, nil
+}
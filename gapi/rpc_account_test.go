@@ -0,0 +1,134 @@
+package gapi
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/singhJasvinder101/go_bank/db/mock"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/pb"
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetAccountRPC(t *testing.T) {
+	user := utils.RandomOwner()
+	account := randomAccount(user)
+	member := db.AccountMember{AccountID: account.ID, Username: user, Role: db.RoleViewer}
+
+	testCases := []struct {
+		name          string
+		username      string
+		req           *pb.GetAccountRequest
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, rsp *pb.Account, err error)
+	}{
+		{
+			name:     "OK",
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: user})).
+					Times(1).
+					Return(member, nil)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.Account, err error) {
+				require.NoError(t, err)
+				require.Equal(t, account.Balance, rsp.GetBalance())
+				require.Equal(t, account.AccountNumber, rsp.GetAccountNumber())
+			},
+		},
+		{
+			name:     "AccountNumber",
+			username: user,
+			req:      &pb.GetAccountRequest{AccountNumber: account.AccountNumber},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(account.AccountNumber)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(member, nil)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.Account, err error) {
+				require.NoError(t, err)
+				require.Equal(t, account.AccountNumber, rsp.GetAccountNumber())
+			},
+		},
+		{
+			name:     "IdAndAccountNumber",
+			username: user,
+			req:      &pb.GetAccountRequest{Id: account.ID, AccountNumber: account.AccountNumber},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.Account, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name:     "Unauthenticated",
+			username: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.Account, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name:     "NotMember",
+			username: utils.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.Account, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name:     "NotFound",
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(member, nil)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.Account, err error) {
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+		{
+			name:     "InternalError",
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(member, nil)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.Account, err error) {
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+
+			client := newTestClient(t, store)
+			req := testCase.req
+			if req == nil {
+				req = &pb.GetAccountRequest{Id: account.ID}
+			}
+			rsp, err := client.GetAccount(withUser(t, testCase.username), req)
+			testCase.checkResponse(t, rsp, err)
+		})
+	}
+}
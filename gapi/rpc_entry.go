@@ -0,0 +1,45 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/singhJasvinder101/go_bank/bank"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/pb"
+)
+
+func (server *Server) ListEntries(ctx context.Context, req *pb.ListEntriesRequest) (*pb.ListEntriesResponse, error) {
+	if err := validatePage(req.GetPageId(), req.GetPageSize()); err != nil {
+		return nil, err
+	}
+
+	accountID, err := server.accountRef(ctx, "account", req.GetAccountId(), req.GetAccountNumber())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := server.authorizeAccount(ctx, accountID, bank.AnyMember); err != nil {
+		return nil, err
+	}
+
+	account, err := server.store.GetAccountById(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	numbers := map[int64]string{account.ID: account.AccountNumber}
+
+	entries, err := server.store.ListEntries(ctx, db.ListEntriesParams{
+		AccountID: accountID,
+		Limit:     req.GetPageSize(),
+		Offset:    (req.GetPageId() - 1) * req.GetPageSize(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.ListEntriesResponse{}
+	for _, entry := range entries {
+		rsp.Entries = append(rsp.Entries, convertEntry(entry, numbers))
+	}
+	return rsp, nil
+}
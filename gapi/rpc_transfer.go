@@ -0,0 +1,44 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/singhJasvinder101/go_bank/bank"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/pb"
+)
+
+func (server *Server) CreateTransfer(ctx context.Context, req *pb.CreateTransferRequest) (*pb.CreateTransferResponse, error) {
+	result, err := server.bank.Transfer(ctx, bank.TransferParams{
+		User:              authUser(ctx),
+		FromAccountID:     req.GetFromAccountId(),
+		FromAccountNumber: req.GetFromAccountNumber(),
+		ToAccountID:       req.GetToAccountId(),
+		ToAccountNumber:   req.GetToAccountNumber(),
+		BeneficiaryID:     req.GetBeneficiaryId(),
+		Amount:            req.GetAmount(),
+		Currency:          req.GetCurrency(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.ApprovalRequest != nil {
+		return &pb.CreateTransferResponse{ApprovalRequestId: result.ApprovalRequest.ID}, nil
+	}
+
+	transfer := result.Transfer
+	known := []db.Account{transfer.FromAccount, transfer.ToAccount}
+	numbers, err := db.AccountNumbers(ctx, server.store, known, db.ParentIDs(known...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CreateTransferResponse{
+		Transfer:    convertTransfer(transfer.Transfer, numbers),
+		FromAccount: convertAccount(transfer.FromAccount, numbers),
+		ToAccount:   convertAccount(transfer.ToAccount, numbers),
+		FromEntry:   convertEntry(transfer.FromEntry, numbers),
+		ToEntry:     convertEntry(transfer.ToEntry, numbers),
+	}, nil
+}
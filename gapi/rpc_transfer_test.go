@@ -0,0 +1,259 @@
+package gapi
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/singhJasvinder101/go_bank/db/mock"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/pb"
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCreateTransferRPC(t *testing.T) {
+	user := utils.RandomOwner()
+	account1 := randomAccount(user)
+	account2 := randomAccount(utils.RandomOwner())
+	account1.Currency = "USD"
+	account1.Balance = 1000
+	account2.ID = account1.ID + 1
+	account2.Currency = account1.Currency
+
+	owner := db.AccountMember{AccountID: account1.ID, Username: user, Role: db.RoleOwner}
+
+	request := &pb.CreateTransferRequest{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        10,
+		Currency:      "USD",
+	}
+
+	transferResult := db.TransferTxResult{
+		Transfer:    db.Transfer{ID: 1, FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 10},
+		FromAccount: account1,
+		ToAccount:   account2,
+		FromEntry:   db.Entry{ID: 1, AccountID: account1.ID, Amount: -10},
+		ToEntry:     db.Entry{ID: 2, AccountID: account2.ID, Amount: 10},
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.CreateTransferRequest
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, rsp *pb.CreateTransferResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  request,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(owner, nil)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().GetApprovalPolicy(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(db.ApprovalPolicy{}, db.ErrRecordNotFound)
+				arg := db.TransferTxParams{
+					FromAccountID: account1.ID,
+					ToAccountID:   account2.ID,
+					Amount:        10,
+				}
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(transferResult, nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, int64(10), rsp.GetTransfer().GetAmount())
+				require.Equal(t, account1.AccountNumber, rsp.GetTransfer().GetFromAccountNumber())
+				require.Equal(t, account2.AccountNumber, rsp.GetTransfer().GetToAccountNumber())
+				require.Equal(t, account2.AccountNumber, rsp.GetToEntry().GetAccountNumber())
+				require.Zero(t, rsp.GetApprovalRequestId())
+			},
+		},
+		{
+			name: "AccountNumbers",
+			req: &pb.CreateTransferRequest{
+				FromAccountNumber: account1.AccountNumber,
+				ToAccountNumber:   account2.AccountNumber,
+				Amount:            10,
+				Currency:          "USD",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(account1.AccountNumber)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(account2.AccountNumber)).Times(1).Return(account2, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(owner, nil)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().GetApprovalPolicy(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(db.ApprovalPolicy{}, db.ErrRecordNotFound)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(transferResult, nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, account1.AccountNumber, rsp.GetFromAccount().GetAccountNumber())
+			},
+		},
+		{
+			name: "Beneficiary",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: account1.ID,
+				BeneficiaryId: 3,
+				Amount:        10,
+				Currency:      "USD",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				beneficiary := db.Beneficiary{ID: 3, Owner: user, AccountID: account2.ID, Verified: true}
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(owner, nil)
+				store.EXPECT().GetBeneficiary(gomock.Any(), gomock.Eq(beneficiary.ID)).Times(1).Return(beneficiary, nil)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().GetApprovalPolicy(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(db.ApprovalPolicy{}, db.ErrRecordNotFound)
+				arg := db.TransferTxParams{
+					FromAccountID: account1.ID,
+					ToAccountID:   account2.ID,
+					Amount:        10,
+				}
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(transferResult, nil)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, account2.AccountNumber, rsp.GetTransfer().GetToAccountNumber())
+			},
+		},
+		{
+			name: "BeneficiaryOfOtherUser",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: account1.ID,
+				BeneficiaryId: 3,
+				Amount:        10,
+				Currency:      "USD",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				beneficiary := db.Beneficiary{ID: 3, Owner: utils.RandomOwner(), AccountID: account2.ID}
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(owner, nil)
+				store.EXPECT().GetBeneficiary(gomock.Any(), gomock.Eq(beneficiary.ID)).Times(1).Return(beneficiary, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateTransferResponse, err error) {
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+		{
+			name: "BeneficiaryAndToAccount",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				BeneficiaryId: 3,
+				Amount:        10,
+				Currency:      "USD",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateTransferResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "NeedsApproval",
+			req:  request,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(owner, nil)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().
+					GetApprovalPolicy(gomock.Any(), gomock.Eq(account1.ID)).
+					Times(1).
+					Return(db.ApprovalPolicy{AccountID: account1.ID, Threshold: 5, RequiredApprovals: 1, Approvers: []string{utils.RandomOwner()}}, nil)
+				store.EXPECT().CreateApprovalRequest(gomock.Any(), gomock.Any()).Times(1).Return(db.ApprovalRequest{ID: 7}, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, int64(7), rsp.GetApprovalRequestId())
+				require.Nil(t, rsp.GetTransfer())
+			},
+		},
+		{
+			name: "CurrencyMismatch",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        10,
+				Currency:      "EUR",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(owner, nil)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateTransferResponse, err error) {
+				require.Equal(t, codes.FailedPrecondition, status.Code(err))
+			},
+		},
+		{
+			name: "InsufficientFunds",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        account1.Balance + 1,
+				Currency:      "USD",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(owner, nil)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountById(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().GetApprovalPolicy(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(db.ApprovalPolicy{}, db.ErrRecordNotFound)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, db.ErrInsufficientFunds)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateTransferResponse, err error) {
+				require.Equal(t, codes.FailedPrecondition, status.Code(err))
+			},
+		},
+		{
+			name: "NotAllowed",
+			req:  request,
+			buildStubs: func(store *mockdb.MockStore) {
+				viewer := owner
+				viewer.Role = db.RoleViewer
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(viewer, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateTransferResponse, err error) {
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name: "InvalidAmount",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        -1,
+				Currency:      "USD",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp *pb.CreateTransferResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+
+			client := newTestClient(t, store)
+			rsp, err := client.CreateTransfer(withUser(t, user), testCase.req)
+			testCase.checkResponse(t, rsp, err)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package gapi
+
+import (
+	"context"
+	"log/slog"
+	"net/netip"
+
+	"github.com/singhJasvinder101/go_bank/bank"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/pb"
+	"github.com/singhJasvinder101/go_bank/ratelimit"
+	"github.com/singhJasvinder101/go_bank/token"
+	"github.com/singhJasvinder101/go_bank/utils"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+)
+
+// Server serves gRPC requests for our banking service.
+type Server struct {
+	pb.UnimplementedGoBankServer
+	config      utils.Config
+	store       db.Store
+	bank        *bank.Service
+	tokenMaker  token.Maker
+	authProxies []netip.Prefix
+	// rateLimiter holds the buckets of the rateLimits
+	rateLimiter ratelimit.Store
+	rateLimits  ratelimit.Limits
+	logger      *slog.Logger
+}
+
+func NewServer(config utils.Config, store db.Store) *Server {
+	return &Server{
+		config:      config,
+		store:       store,
+		bank:        bank.NewService(config, store),
+		rateLimiter: ratelimit.NewMemoryStore(),
+		logger:      slog.Default(),
+	}
+}
+
+// SetAuth makes the server accept access tokens verified by tokenMaker, which
+// may be nil to accept none, and the x-username metadata of calls coming
+// from authProxies.
+func (server *Server) SetAuth(tokenMaker token.Maker, authProxies []netip.Prefix) {
+	server.tokenMaker = tokenMaker
+	server.authProxies = authProxies
+}
+
+// SetLogger makes the server log calls to logger.
+func (server *Server) SetLogger(logger *slog.Logger) {
+	server.logger = logger
+}
+
+// NewGRPCServer returns a grpc.Server with the GoBank service registered and
+// the auth, rate limit, logging, error mapping and consistency interceptors
+// installed.
+func NewGRPCServer(server *Server) *grpc.Server {
+	grpcServer := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(
+			server.LoggerInterceptor,
+			ErrorInterceptor,
+			server.LimitIPInterceptor,
+			server.AuthInterceptor,
+			server.LimitUserInterceptor,
+			ConsistencyInterceptor,
+		),
+	)
+	pb.RegisterGoBankServer(grpcServer, server)
+	return grpcServer
+}
+
+// authorizeAccount checks that the authenticated user is a member of the
+// account allowed to perform the call.
+func (server *Server) authorizeAccount(ctx context.Context, accountID int64, allowed func(db.AccountMember) bool) (db.AccountMember, error) {
+	return server.bank.AuthorizeAccount(ctx, authUser(ctx), accountID, allowed)
+}
@@ -0,0 +1,21 @@
+package gapi
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func invalidArgument(format string, a ...any) error {
+	return status.Errorf(codes.InvalidArgument, format, a...)
+}
+
+// validatePage applies the page limits of the HTTP API.
+func validatePage(pageID int32, pageSize int32) error {
+	if pageID < 1 {
+		return invalidArgument("page_id must be at least 1")
+	}
+	if pageSize < 5 || pageSize > 10 {
+		return invalidArgument("page_size must be between 5 and 10")
+	}
+	return nil
+}
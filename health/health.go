@@ -0,0 +1,94 @@
+// Package health reports whether the service and the dependencies it needs
+// to serve traffic are working.
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// DefaultCheckTimeout bounds how long a single check may take.
+const DefaultCheckTimeout = 2 * time.Second
+
+// Check returns nil when the dependency it checks is usable.
+type Check func(ctx context.Context) error
+
+// CheckResult is the outcome of a single check.
+type CheckResult struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Report is the outcome of every check. Its status is ok only when every
+// check passed.
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// OK reports whether every check passed.
+func (report Report) OK() bool {
+	return report.Status == StatusOK
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker runs a set of named checks.
+type Checker struct {
+	Timeout time.Duration
+	checks  []namedCheck
+}
+
+func NewChecker() *Checker {
+	return &Checker{Timeout: DefaultCheckTimeout}
+}
+
+// Add registers a check under name.
+func (checker *Checker) Add(name string, check Check) {
+	checker.checks = append(checker.checks, namedCheck{name: name, check: check})
+}
+
+// Run runs every check concurrently, each bounded by the checker timeout.
+func (checker *Checker) Run(ctx context.Context) Report {
+	report := Report{Status: StatusOK, Checks: make(map[string]CheckResult, len(checker.checks))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, c := range checker.checks {
+		wg.Add(1)
+		go func(c namedCheck) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, checker.Timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := c.check(checkCtx)
+			result := CheckResult{Status: StatusOK, Duration: time.Since(start).String()}
+			if err != nil {
+				result.Status = StatusFail
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[c.name] = result
+			if err != nil {
+				report.Status = StatusFail
+			}
+		}(c)
+	}
+	wg.Wait()
+
+	return report
+}
@@ -0,0 +1,118 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckerRun(t *testing.T) {
+	checker := NewChecker()
+	checker.Add("up", func(ctx context.Context) error { return nil })
+
+	report := checker.Run(context.Background())
+	require.True(t, report.OK())
+	require.Equal(t, StatusOK, report.Checks["up"].Status)
+
+	checker.Add("down", func(ctx context.Context) error { return errors.New("connection refused") })
+
+	report = checker.Run(context.Background())
+	require.False(t, report.OK())
+	require.Equal(t, StatusOK, report.Checks["up"].Status)
+	require.Equal(t, StatusFail, report.Checks["down"].Status)
+	require.Equal(t, "connection refused", report.Checks["down"].Error)
+}
+
+func TestCheckerTimeout(t *testing.T) {
+	checker := NewChecker()
+	checker.Timeout = 10 * time.Millisecond
+	checker.Add("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	report := checker.Run(context.Background())
+	require.False(t, report.OK())
+	require.Equal(t, context.DeadlineExceeded.Error(), report.Checks["slow"].Error)
+}
+
+func TestPoolSaturation(t *testing.T) {
+	usage := PoolUsage{Acquired: 9, Max: 10}
+	check := PoolSaturation(func() PoolUsage { return usage }, DefaultMaxPoolUtilization)
+	require.NoError(t, check(context.Background()))
+
+	usage.Acquired = 10
+	require.Error(t, check(context.Background()))
+}
+
+func TestLatestMigration(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000001_init_schema.up.sql":   {},
+		"000001_init_schema.down.sql": {},
+		"000012_add_outbox.up.sql":    {},
+		"000012_add_outbox.down.sql":  {},
+		"000002_add_pots.up.sql":      {},
+	}
+
+	latest, err := LatestMigration(fsys)
+	require.NoError(t, err)
+	require.Equal(t, uint(12), latest)
+
+	fsys["bad.up.sql"] = &fstest.MapFile{}
+	_, err = LatestMigration(fsys)
+	require.Error(t, err)
+}
+
+type fakeRow struct {
+	version int64
+	dirty   bool
+	err     error
+}
+
+func (row fakeRow) Scan(dest ...any) error {
+	if row.err != nil {
+		return row.err
+	}
+	*dest[0].(*int64) = row.version
+	*dest[1].(*bool) = row.dirty
+	return nil
+}
+
+type fakeQueryRower struct {
+	row fakeRow
+}
+
+func (db fakeQueryRower) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return db.row
+}
+
+func TestMigrations(t *testing.T) {
+	testCases := []struct {
+		name string
+		row  fakeRow
+		ok   bool
+	}{
+		{name: "UpToDate", row: fakeRow{version: 8}, ok: true},
+		{name: "Pending", row: fakeRow{version: 7}},
+		{name: "Dirty", row: fakeRow{version: 8, dirty: true}},
+		{name: "NoneApplied", row: fakeRow{err: pgx.ErrNoRows}},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			err := Migrations(fakeQueryRower{row: testCase.row}, 8)(context.Background())
+			if testCase.ok {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
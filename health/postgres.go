@@ -0,0 +1,107 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DefaultMaxPoolUtilization is the share of the connection pool that may be
+// in use before an instance stops taking more traffic.
+const DefaultMaxPoolUtilization = 0.9
+
+// Pinger is implemented by *pgxpool.Pool.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Database checks that the database accepts connections.
+func Database(db Pinger) Check {
+	return func(ctx context.Context) error {
+		return db.Ping(ctx)
+	}
+}
+
+// PoolUsage is how many connections of a pool are in use.
+type PoolUsage struct {
+	Acquired int32
+	Max      int32
+}
+
+// PgxPoolUsage reads the usage of a pgx connection pool.
+func PgxPoolUsage(pool *pgxpool.Pool) func() PoolUsage {
+	return func() PoolUsage {
+		stat := pool.Stat()
+		return PoolUsage{Acquired: stat.AcquiredConns(), Max: stat.MaxConns()}
+	}
+}
+
+// PoolSaturation fails when more than maxUtilization of the pool is in use.
+func PoolSaturation(usage func() PoolUsage, maxUtilization float64) Check {
+	return func(ctx context.Context) error {
+		u := usage()
+		if u.Max > 0 && float64(u.Acquired)/float64(u.Max) > maxUtilization {
+			return fmt.Errorf("%d of %d connections in use", u.Acquired, u.Max)
+		}
+		return nil
+	}
+}
+
+// QueryRower is implemented by *pgxpool.Pool.
+type QueryRower interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// Migrations checks that the schema_migrations table kept by golang-migrate
+// is clean and at version want.
+func Migrations(db QueryRower, want uint) Check {
+	return func(ctx context.Context) error {
+		var version int64
+		var dirty bool
+		err := db.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("no migrations applied, want version %d", want)
+			}
+			return err
+		}
+
+		if dirty {
+			return fmt.Errorf("migration %d failed and left the schema dirty", version)
+		}
+		if uint(version) < want {
+			return fmt.Errorf("migrations pending, at version %d, want %d", version, want)
+		}
+		return nil
+	}
+}
+
+// LatestMigration returns the highest version among the golang-migrate files
+// in fsys, named like 000001_init_schema.up.sql.
+func LatestMigration(fsys fs.FS) (uint, error) {
+	names, err := fs.Glob(fsys, "*.up.sql")
+	if err != nil {
+		return 0, err
+	}
+
+	var latest uint
+	for _, name := range names {
+		prefix, _, ok := strings.Cut(name, "_")
+		if !ok {
+			return 0, fmt.Errorf("migration %s has no version", name)
+		}
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("migration %s: %w", name, err)
+		}
+		latest = max(latest, uint(version))
+	}
+
+	return latest, nil
+}
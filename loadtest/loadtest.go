@@ -0,0 +1,435 @@
+// Package loadtest drives a running API server with a mix of transfers,
+// account reads and account listings at a fixed rate, and reports the
+// latency, errors and throughput of each kind of request.
+package loadtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/singhJasvinder101/go_bank/utils"
+)
+
+// Operations a load test can mix, the keys of Config.Mix.
+const (
+	OpTransfer     = "transfer"
+	OpGetAccount   = "get"
+	OpListAccounts = "list"
+)
+
+// MaxRate bounds Config.Rate: beyond it the ticker that paces the requests
+// cannot keep up.
+const MaxRate = 10_000
+
+// Config describes a load test. Zero fields take the defaults below.
+type Config struct {
+	// Target is the base URL of the server, such as http://localhost:3000.
+	Target   string
+	Rate     int // requests started per second, at most MaxRate
+	Duration time.Duration
+	// Concurrency bounds the requests in flight. A request that is due while
+	// all are busy is dropped rather than delayed, so that a slow server
+	// shows in Report.Dropped instead of lowering the rate unnoticed.
+	Concurrency int
+	// Mix weighs the operations against each other, e.g. transfer=6, get=3
+	// and list=1 make 60% of the requests transfers.
+	Mix map[string]int
+	// Seed makes the sequence of requests reproducible.
+	Seed int64
+	// MaxAmount bounds the amount of a transfer.
+	MaxAmount int64
+	Timeout   time.Duration
+}
+
+func (c Config) withDefaults() Config {
+	if c.Rate == 0 {
+		c.Rate = 50
+	}
+	if c.Duration == 0 {
+		c.Duration = 10 * time.Second
+	}
+	if c.Concurrency == 0 {
+		c.Concurrency = 64
+	}
+	if len(c.Mix) == 0 {
+		c.Mix = map[string]int{OpTransfer: 6, OpGetAccount: 3, OpListAccounts: 1}
+	}
+	if c.MaxAmount == 0 {
+		c.MaxAmount = 10
+	}
+	if c.Timeout == 0 {
+		c.Timeout = 5 * time.Second
+	}
+	return c
+}
+
+// Validate reports the first invalid setting of c, once zero fields took
+// their defaults.
+func (c Config) Validate() error {
+	c = c.withDefaults()
+	if c.Target == "" {
+		return errors.New("target is required")
+	}
+	if c.Rate < 0 || c.Duration < 0 || c.Concurrency < 0 {
+		return errors.New("rate, duration and concurrency cannot be negative")
+	}
+	if c.Rate > MaxRate {
+		return fmt.Errorf("rate cannot exceed %d requests per second", MaxRate)
+	}
+
+	total := 0
+	for _, op := range slices.Sorted(maps.Keys(c.Mix)) {
+		if op != OpTransfer && op != OpGetAccount && op != OpListAccounts {
+			return fmt.Errorf("unknown operation %q in mix: want %s, %s or %s", op, OpTransfer, OpGetAccount, OpListAccounts)
+		}
+		if c.Mix[op] < 0 {
+			return fmt.Errorf("weight of %s cannot be negative", op)
+		}
+		total += c.Mix[op]
+	}
+	if total == 0 {
+		return errors.New("mix has no operation with a positive weight")
+	}
+	return nil
+}
+
+// Report is the outcome of a load test.
+type Report struct {
+	Seed     int64         `json:"seed"`
+	Duration time.Duration `json:"duration"`
+	// Requests counts the requests sent, Errors those that failed or got an
+	// error status, and Dropped those not sent because all were busy.
+	Requests int `json:"requests"`
+	Errors   int `json:"errors"`
+	Dropped  int `json:"dropped"`
+	// Throughput is the number of successful requests per second.
+	Throughput float64 `json:"throughput"`
+	Latency    Latency `json:"latency"`
+	// Operations reports each operation of the mix, in the order of its name.
+	Operations []OperationReport `json:"operations"`
+}
+
+// OperationReport is the part of a Report about one operation.
+type OperationReport struct {
+	Name       string  `json:"name"`
+	Requests   int     `json:"requests"`
+	Errors     int     `json:"errors"`
+	Throughput float64 `json:"throughput"`
+	Latency    Latency `json:"latency"`
+	// ErrorKinds counts the errors by HTTP status, such as "429", or by
+	// "timeout" and "connection" when no response arrived.
+	ErrorKinds map[string]int `json:"error_kinds"`
+}
+
+// Latency summarizes the latencies of requests, failed ones included.
+type Latency struct {
+	P50 time.Duration `json:"p50"`
+	P90 time.Duration `json:"p90"`
+	P99 time.Duration `json:"p99"`
+	Max time.Duration `json:"max"`
+}
+
+// newLatency computes the percentiles of samples, which it sorts.
+func newLatency(samples []time.Duration) Latency {
+	if len(samples) == 0 {
+		return Latency{}
+	}
+	slices.Sort(samples)
+	return Latency{
+		P50: percentile(samples, 50),
+		P90: percentile(samples, 90),
+		P99: percentile(samples, 99),
+		Max: samples[len(samples)-1],
+	}
+}
+
+// percentile returns the nearest-rank percentile p of sorted samples.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
+
+// result is the outcome of one request.
+type result struct {
+	op      string
+	latency time.Duration
+	kind    string // empty on success
+}
+
+// Run sends requests for cfg.Duration at cfg.Rate, as the owners of
+// accounts, which must already exist on the server, see CreatePool.
+// Transfers go between accounts of the same currency, so at least two
+// accounts must share one.
+func Run(ctx context.Context, client *http.Client, cfg Config, accounts []Account) (Report, error) {
+	if err := cfg.Validate(); err != nil {
+		return Report{}, err
+	}
+	cfg = cfg.withDefaults()
+
+	g, err := newGenerator(cfg, accounts)
+	if err != nil {
+		return Report{}, err
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results []result
+		dropped int
+	)
+	busy := make(chan struct{}, cfg.Concurrency)
+	ticker := time.NewTicker(time.Second / time.Duration(cfg.Rate))
+	defer ticker.Stop()
+
+	start := time.Now()
+	deadline := time.NewTimer(cfg.Duration)
+	defer deadline.Stop()
+
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-deadline.C:
+			break loop
+		case <-ticker.C:
+		}
+
+		op, req := g.next()
+		select {
+		case busy <- struct{}{}:
+		default:
+			dropped++
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-busy }()
+
+			r := send(client, cfg.Timeout, op, req)
+			mu.Lock()
+			results = append(results, r)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	report := newReport(time.Since(start), results)
+	report.Seed = cfg.Seed
+	report.Dropped = dropped
+	return report, nil
+}
+
+// request is an HTTP request to build once it is sent.
+type request struct {
+	method string
+	path   string
+	token  string // access token of the user making the request
+	body   any
+}
+
+// newRequest builds req with its JSON body.
+func newRequest(ctx context.Context, req request) (*http.Request, error) {
+	var body io.Reader
+	if req.body != nil {
+		data, err := json.Marshal(req.body)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, req.path, body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+req.token)
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	return httpReq, nil
+}
+
+// send makes req and measures how long the response took to read.
+func send(client *http.Client, timeout time.Duration, op string, req request) result {
+	r := result{op: op}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	httpReq, err := newRequest(ctx, req)
+	if err != nil {
+		r.kind = "request"
+		return r
+	}
+
+	start := time.Now()
+	rsp, err := client.Do(httpReq)
+	if err == nil {
+		_, err = io.Copy(io.Discard, rsp.Body)
+		rsp.Body.Close()
+	}
+	r.latency = time.Since(start)
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		r.kind = "timeout"
+	case err != nil:
+		r.kind = "connection"
+	case rsp.StatusCode >= 400:
+		r.kind = fmt.Sprint(rsp.StatusCode)
+	}
+	return r
+}
+
+func newReport(duration time.Duration, results []result) Report {
+	report := Report{Duration: duration}
+	byOp := make(map[string][]result)
+	var all []time.Duration
+	for _, r := range results {
+		byOp[r.op] = append(byOp[r.op], r)
+		all = append(all, r.latency)
+	}
+
+	for _, op := range slices.Sorted(maps.Keys(byOp)) {
+		o := OperationReport{Name: op, ErrorKinds: map[string]int{}}
+		var latencies []time.Duration
+		for _, r := range byOp[op] {
+			o.Requests++
+			latencies = append(latencies, r.latency)
+			if r.kind != "" {
+				o.Errors++
+				o.ErrorKinds[r.kind]++
+			}
+		}
+		o.Latency = newLatency(latencies)
+		o.Throughput = perSecond(o.Requests-o.Errors, duration)
+
+		report.Requests += o.Requests
+		report.Errors += o.Errors
+		report.Operations = append(report.Operations, o)
+	}
+	report.Latency = newLatency(all)
+	report.Throughput = perSecond(report.Requests-report.Errors, duration)
+	return report
+}
+
+func perSecond(n int, duration time.Duration) float64 {
+	if duration <= 0 {
+		return 0
+	}
+	return float64(n) / duration.Seconds()
+}
+
+// generator picks the requests of a load test.
+type generator struct {
+	target string
+	rand   *utils.Random
+	config Config
+	ops    []string // in the order of their name, for reproducibility
+
+	accounts []Account
+	// counterparts maps the number of an account to the other accounts in
+	// its currency.
+	counterparts map[string][]Account
+	transferable []Account // accounts with a counterpart
+}
+
+func newGenerator(cfg Config, accounts []Account) (*generator, error) {
+	if len(accounts) == 0 {
+		return nil, errors.New("no accounts to run the load test with")
+	}
+
+	g := &generator{
+		target:       strings.TrimSuffix(cfg.Target, "/"),
+		rand:         utils.NewRandom(cfg.Seed),
+		config:       cfg,
+		accounts:     accounts,
+		counterparts: make(map[string][]Account),
+	}
+	for _, op := range slices.Sorted(maps.Keys(cfg.Mix)) {
+		if cfg.Mix[op] > 0 {
+			g.ops = append(g.ops, op)
+		}
+	}
+
+	for _, from := range accounts {
+		for _, to := range accounts {
+			if from.Number != to.Number && from.Currency == to.Currency {
+				g.counterparts[from.Number] = append(g.counterparts[from.Number], to)
+			}
+		}
+		if len(g.counterparts[from.Number]) > 0 {
+			g.transferable = append(g.transferable, from)
+		}
+	}
+	if cfg.Mix[OpTransfer] > 0 && len(g.transferable) == 0 {
+		return nil, errors.New("transfers need two accounts in the same currency")
+	}
+	return g, nil
+}
+
+// next returns the operation and request to send next.
+func (g *generator) next() (string, request) {
+	op := g.pickOp()
+	account := g.accounts[g.rand.Int(0, len(g.accounts))]
+
+	switch op {
+	case OpTransfer:
+		from := g.transferable[g.rand.Int(0, len(g.transferable))]
+		counterparts := g.counterparts[from.Number]
+		to := counterparts[g.rand.Int(0, len(counterparts))]
+		return op, request{
+			method: http.MethodPost,
+			path:   g.target + "/transfers",
+			token:  from.Token,
+			body: map[string]any{
+				"from_account_number": from.Number,
+				"to_account_number":   to.Number,
+				"amount":              g.rand.Int64(1, g.config.MaxAmount+1),
+				"currency":            from.Currency,
+			},
+		}
+	case OpGetAccount:
+		return op, request{
+			method: http.MethodGet,
+			path:   g.target + "/accounts/" + account.Number,
+			token:  account.Token,
+		}
+	default:
+		return op, request{
+			method: http.MethodGet,
+			path:   g.target + "/accounts?page_id=1&page_size=10",
+			token:  account.Token,
+		}
+	}
+}
+
+// pickOp picks an operation with the probability of its weight in the mix.
+func (g *generator) pickOp() string {
+	total := 0
+	for _, op := range g.ops {
+		total += g.config.Mix[op]
+	}
+
+	n := g.rand.Int(0, total)
+	for _, op := range g.ops {
+		n -= g.config.Mix[op]
+		if n < 0 {
+			return op
+		}
+	}
+	return g.ops[len(g.ops)-1]
+}
@@ -0,0 +1,106 @@
+package loadtest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testAccounts = []Account{
+	{Number: "GB29GOBK00000000000001", Owner: "alice", Currency: "USD", Token: "alice-token"},
+	{Number: "GB29GOBK00000000000002", Owner: "bob", Currency: "USD", Token: "bob-token"},
+	{Number: "GB29GOBK00000000000003", Owner: "carol", Currency: "EUR", Token: "carol-token"},
+}
+
+func TestRun(t *testing.T) {
+	var mu sync.Mutex
+	var transfers []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer "):
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.Method == http.MethodPost && r.URL.Path == "/transfers":
+			var body map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			mu.Lock()
+			transfers = append(transfers, body)
+			mu.Unlock()
+		case r.URL.Path == "/accounts":
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer server.Close()
+
+	report, err := Run(context.Background(), server.Client(), Config{
+		Target:   server.URL,
+		Rate:     200,
+		Duration: 500 * time.Millisecond,
+		Seed:     1,
+	}, testAccounts)
+	require.NoError(t, err)
+
+	require.Positive(t, report.Requests)
+	require.Len(t, report.Operations, 3)
+	require.Equal(t, []string{OpGetAccount, OpListAccounts, OpTransfer},
+		[]string{report.Operations[0].Name, report.Operations[1].Name, report.Operations[2].Name})
+
+	list := report.Operations[1]
+	require.Equal(t, list.Requests, list.Errors)
+	require.Equal(t, map[string]int{"429": list.Errors}, list.ErrorKinds)
+	require.Equal(t, list.Errors, report.Errors)
+	require.Positive(t, report.Throughput)
+	require.LessOrEqual(t, report.Latency.P50, report.Latency.P99)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, transfers, report.Operations[2].Requests)
+	for _, transfer := range transfers {
+		// only the USD accounts can send to each other
+		require.Contains(t, []any{testAccounts[0].Number, testAccounts[1].Number}, transfer["from_account_number"])
+		require.Contains(t, []any{testAccounts[0].Number, testAccounts[1].Number}, transfer["to_account_number"])
+		require.NotEqual(t, transfer["from_account_number"], transfer["to_account_number"])
+		require.Equal(t, "USD", transfer["currency"])
+	}
+}
+
+func TestRunConfig(t *testing.T) {
+	client := http.DefaultClient
+	ctx := context.Background()
+
+	_, err := Run(ctx, client, Config{}, testAccounts)
+	require.EqualError(t, err, "target is required")
+
+	_, err = Run(ctx, client, Config{Target: "http://localhost", Rate: MaxRate + 1}, testAccounts)
+	require.EqualError(t, err, "rate cannot exceed 10000 requests per second")
+
+	_, err = Run(ctx, client, Config{Target: "http://localhost", Mix: map[string]int{"delete": 1}}, testAccounts)
+	require.ErrorContains(t, err, `unknown operation "delete"`)
+
+	_, err = Run(ctx, client, Config{Target: "http://localhost", Mix: map[string]int{OpTransfer: 0}}, testAccounts)
+	require.EqualError(t, err, "mix has no operation with a positive weight")
+
+	_, err = Run(ctx, client, Config{Target: "http://localhost"}, testAccounts[2:])
+	require.EqualError(t, err, "transfers need two accounts in the same currency")
+}
+
+func TestNewLatency(t *testing.T) {
+	var samples []time.Duration
+	for i := 100; i >= 1; i-- {
+		samples = append(samples, time.Duration(i)*time.Millisecond)
+	}
+
+	require.Equal(t, Latency{
+		P50: 50 * time.Millisecond,
+		P90: 90 * time.Millisecond,
+		P99: 99 * time.Millisecond,
+		Max: 100 * time.Millisecond,
+	}, newLatency(samples))
+	require.Equal(t, Latency{}, newLatency(nil))
+}
@@ -0,0 +1,151 @@
+package loadtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// poolAttempts bounds how often a request creating the pool is retried
+// after the server refused it with 429.
+const poolAttempts = 5
+
+// User is a user of the load test with the access token it authenticates
+// with.
+type User struct {
+	Username string
+	Token    string
+}
+
+// Account is an account of the pool as the API returned it, with the token
+// of its owner.
+type Account struct {
+	Number   string `json:"account_number"`
+	Owner    string `json:"owner"`
+	Currency string `json:"currency"`
+	Token    string `json:"-"`
+}
+
+// PoolAccount is an account to open for the pool.
+type PoolAccount struct {
+	Owner    User
+	Currency string
+	// Balance is transferred to the account from the funding account of its
+	// currency.
+	Balance int64
+}
+
+// Pool describes the accounts a load test runs with.
+type Pool struct {
+	Accounts []PoolAccount
+	// Funder owns the funding accounts, whose numbers are given by currency.
+	Funder  User
+	Funding map[string]string
+}
+
+// CreatePool opens the accounts of pool through the API of the server at
+// target, each as its owner, and funds them with transfers from the
+// funding accounts. Requests refused by a rate limit are retried after the
+// time the server asks for.
+func CreatePool(ctx context.Context, client *http.Client, target string, pool Pool) ([]Account, error) {
+	target = strings.TrimSuffix(target, "/")
+
+	var accounts []Account
+	for _, arg := range pool.Accounts {
+		funding, ok := pool.Funding[arg.Currency]
+		if !ok {
+			return accounts, fmt.Errorf("no funding account in %s", arg.Currency)
+		}
+
+		var account Account
+		err := call(ctx, client, request{
+			method: http.MethodPost,
+			path:   target + "/accounts",
+			token:  arg.Owner.Token,
+			body:   map[string]any{"currency": arg.Currency},
+		}, &account)
+		if err != nil {
+			return accounts, fmt.Errorf("open account of %s: %w", arg.Owner.Username, err)
+		}
+		account.Token = arg.Owner.Token
+
+		if arg.Balance > 0 {
+			err = call(ctx, client, request{
+				method: http.MethodPost,
+				path:   target + "/transfers",
+				token:  pool.Funder.Token,
+				body: map[string]any{
+					"from_account_number": funding,
+					"to_account_number":   account.Number,
+					"amount":              arg.Balance,
+					"currency":            arg.Currency,
+				},
+			}, nil)
+			if err != nil {
+				return accounts, fmt.Errorf("fund account %s: %w", account.Number, err)
+			}
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, nil
+}
+
+// call makes req and decodes the response into out, if not nil. A response
+// with an error status is an error.
+func call(ctx context.Context, client *http.Client, req request, out any) error {
+	for attempt := 1; ; attempt++ {
+		httpReq, err := newRequest(ctx, req)
+		if err != nil {
+			return err
+		}
+		rsp, err := client.Do(httpReq)
+		if err != nil {
+			return err
+		}
+		body, err := io.ReadAll(rsp.Body)
+		rsp.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		if rsp.StatusCode == http.StatusTooManyRequests && attempt < poolAttempts {
+			if err := wait(ctx, retryAfter(rsp)); err != nil {
+				return err
+			}
+			continue
+		}
+		if rsp.StatusCode >= 400 {
+			return fmt.Errorf("%s %s: %s: %s", req.method, req.path, rsp.Status, strings.TrimSpace(string(body)))
+		}
+		if out == nil {
+			return nil
+		}
+		return json.Unmarshal(body, out)
+	}
+}
+
+// retryAfter is how long the server asks a refused request to wait.
+func retryAfter(rsp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(rsp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return time.Second
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// wait sleeps for d unless ctx is done first.
+func wait(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
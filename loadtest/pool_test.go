@@ -0,0 +1,102 @@
+package loadtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePool(t *testing.T) {
+	alice := User{Username: "alice", Token: "alice-token"}
+	bob := User{Username: "bob", Token: "bob-token"}
+	funder := User{Username: "funder", Token: "funder-token"}
+	owners := map[string]string{"Bearer alice-token": "alice", "Bearer bob-token": "bob", "Bearer funder-token": "funder"}
+
+	var (
+		mu        sync.Mutex
+		opened    int
+		refused   bool
+		transfers []map[string]any
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		owner, ok := owners[r.Header.Get("Authorization")]
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		switch r.URL.Path {
+		case "/accounts":
+			opened++
+			json.NewEncoder(w).Encode(map[string]any{
+				"account_number": fmt.Sprintf("GB29GOBK%014d", opened),
+				"owner":          owner,
+				"currency":       body["currency"],
+				"balance":        0,
+			})
+		case "/transfers":
+			// the first funding transfer hits the rate limit
+			if !refused {
+				refused = true
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			require.Equal(t, "funder", owner)
+			transfers = append(transfers, body)
+		}
+	}))
+	defer server.Close()
+
+	accounts, err := CreatePool(context.Background(), server.Client(), server.URL+"/", Pool{
+		Accounts: []PoolAccount{
+			{Owner: alice, Currency: "USD", Balance: 100},
+			{Owner: bob, Currency: "EUR", Balance: 50},
+			{Owner: bob, Currency: "USD"},
+		},
+		Funder:  funder,
+		Funding: map[string]string{"USD": "GB29GOBK00000000000098", "EUR": "GB29GOBK00000000000099"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []Account{
+		{Number: "GB29GOBK00000000000001", Owner: "alice", Currency: "USD", Token: alice.Token},
+		{Number: "GB29GOBK00000000000002", Owner: "bob", Currency: "EUR", Token: bob.Token},
+		{Number: "GB29GOBK00000000000003", Owner: "bob", Currency: "USD", Token: bob.Token},
+	}, accounts)
+
+	// an account without a balance is not funded
+	require.Equal(t, []map[string]any{
+		{"from_account_number": "GB29GOBK00000000000098", "to_account_number": "GB29GOBK00000000000001", "amount": 100.0, "currency": "USD"},
+		{"from_account_number": "GB29GOBK00000000000099", "to_account_number": "GB29GOBK00000000000002", "amount": 50.0, "currency": "EUR"},
+	}, transfers)
+}
+
+func TestCreatePoolError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error":"access tokens are not accepted"}` + "\n"))
+	}))
+	defer server.Close()
+
+	_, err := CreatePool(context.Background(), server.Client(), server.URL, Pool{
+		Accounts: []PoolAccount{{Owner: User{Username: "alice"}, Currency: "USD"}},
+		Funding:  map[string]string{"USD": "GB29GOBK00000000000098"},
+	})
+	require.ErrorContains(t, err, `open account of alice: POST `+server.URL+`/accounts: 403 Forbidden: {"error":"access tokens are not accepted"}`)
+
+	_, err = CreatePool(context.Background(), server.Client(), server.URL, Pool{
+		Accounts: []PoolAccount{{Owner: User{Username: "alice"}, Currency: "BTC"}},
+	})
+	require.EqualError(t, err, "no funding account in BTC")
+}
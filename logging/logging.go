@@ -0,0 +1,100 @@
+// Package logging builds the structured logger of the service and carries
+// request scoped attributes, such as the request id or the user, through
+// contexts so that every record logged with the context includes them.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/singhJasvinder101/go_bank/tracing"
+)
+
+// Formats accepted by New.
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Keys of the attributes carried by contexts.
+const (
+	RequestIDKey  = "request_id"
+	UserKey       = "user"
+	TransferIDKey = "transfer_id"
+	TraceIDKey    = "trace_id"
+)
+
+// New returns a logger writing records at or above level ("debug", "info",
+// "warn" or "error") to w in format. Records logged with a context carry the
+// attributes added to it by With and the id of its trace.
+func New(w io.Writer, level string, format string) (*slog.Logger, error) {
+	var minLevel slog.Level
+	if err := minLevel.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q: %w", level, err)
+	}
+	options := &slog.HandlerOptions{Level: minLevel}
+
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, options)
+	case FormatText:
+		handler = slog.NewTextHandler(w, options)
+	default:
+		return nil, fmt.Errorf("invalid log format %q", format)
+	}
+
+	return slog.New(contextHandler{handler}), nil
+}
+
+// Discard returns a logger that drops every record, e.g. for tests.
+func Discard() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+type attrsKey struct{}
+
+// With returns a copy of ctx that adds args, key-value pairs as accepted by
+// slog.Logger.Info, to every record logged with it.
+func With(ctx context.Context, args ...any) context.Context {
+	record := slog.Record{}
+	record.Add(args...)
+
+	attrs := append([]slog.Attr{}, Attrs(ctx)...)
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr)
+		return true
+	})
+	return context.WithValue(ctx, attrsKey{}, attrs)
+}
+
+// Attrs returns the attributes added to ctx by With.
+func Attrs(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	return attrs
+}
+
+// contextHandler adds the attributes and the trace id of the context of a
+// record before handing it on.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (handler contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	record.AddAttrs(Attrs(ctx)...)
+	if traceID := tracing.TraceID(ctx); traceID != "" {
+		record.AddAttrs(slog.String(TraceIDKey, traceID))
+	}
+	return handler.Handler.Handle(ctx, record)
+}
+
+func (handler contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{handler.Handler.WithAttrs(attrs)}
+}
+
+func (handler contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{handler.Handler.WithGroup(name)}
+}
@@ -0,0 +1,101 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeLines(t *testing.T, output *bytes.Buffer) []map[string]any {
+	var lines []map[string]any
+	for _, data := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+		if data == "" {
+			continue
+		}
+		var line map[string]any
+		require.NoError(t, json.Unmarshal([]byte(data), &line))
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(&bytes.Buffer{}, "verbose", FormatJSON)
+	require.ErrorContains(t, err, "invalid log level")
+
+	_, err = New(&bytes.Buffer{}, "info", "xml")
+	require.ErrorContains(t, err, "invalid log format")
+
+	var output bytes.Buffer
+	logger, err := New(&output, "warn", FormatText)
+	require.NoError(t, err)
+
+	logger.Info("dropped")
+	logger.Warn("kept")
+	require.NotContains(t, output.String(), "dropped")
+	require.Contains(t, output.String(), "msg=kept")
+}
+
+func TestContextAttrs(t *testing.T) {
+	var output bytes.Buffer
+	logger, err := New(&output, "debug", FormatJSON)
+	require.NoError(t, err)
+
+	ctx := With(context.Background(), RequestIDKey, "req-1", UserKey, "alice")
+	transferCtx := With(ctx, TransferIDKey, int64(7))
+
+	logger.InfoContext(transferCtx, "transfer created")
+	logger.With("component", "api").InfoContext(ctx, "request")
+
+	lines := decodeLines(t, &output)
+	require.Len(t, lines, 2)
+
+	require.Equal(t, "req-1", lines[0][RequestIDKey])
+	require.Equal(t, "alice", lines[0][UserKey])
+	require.EqualValues(t, 7, lines[0][TransferIDKey])
+
+	// attributes added to a derived context do not leak into its parent
+	require.Equal(t, "req-1", lines[1][RequestIDKey])
+	require.Equal(t, "api", lines[1]["component"])
+	require.NotContains(t, lines[1], TransferIDKey)
+}
+
+func TestQueryTracer(t *testing.T) {
+	var output bytes.Buffer
+	logger, err := New(&output, "debug", FormatJSON)
+	require.NoError(t, err)
+
+	tracer := NewQueryTracer(logger)
+	ctx := With(context.Background(), TransferIDKey, int64(7))
+
+	queryCtx := tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "-- name: CreateEntry :one\nINSERT INTO entries"})
+	tracer.TraceQueryEnd(queryCtx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("INSERT 0 1")})
+
+	queryCtx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "-- name: GetAccount :one\nSELECT"})
+	tracer.TraceQueryEnd(queryCtx, nil, pgx.TraceQueryEndData{Err: pgx.ErrNoRows})
+
+	queryCtx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "UPDATE accounts"})
+	tracer.TraceQueryEnd(queryCtx, nil, pgx.TraceQueryEndData{Err: errors.New("deadlock detected")})
+
+	lines := decodeLines(t, &output)
+	require.Len(t, lines, 3)
+
+	require.Equal(t, "DEBUG", lines[0]["level"])
+	require.Equal(t, "CreateEntry", lines[0]["query"])
+	require.EqualValues(t, 1, lines[0]["rows"])
+	require.EqualValues(t, 7, lines[0][TransferIDKey])
+
+	// a query finding no rows is not a failure
+	require.Equal(t, "DEBUG", lines[1]["level"])
+
+	require.Equal(t, "ERROR", lines[2]["level"])
+	require.Equal(t, "UPDATE", lines[2]["query"])
+	require.Equal(t, "deadlock detected", lines[2]["error"])
+}
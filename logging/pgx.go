@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/singhJasvinder101/go_bank/tracing"
+)
+
+// QueryTracer is a pgx.QueryTracer that logs every query at debug level, and
+// failed queries at error level.
+type QueryTracer struct {
+	logger *slog.Logger
+}
+
+// NewQueryTracer returns a tracer logging queries to logger.
+func NewQueryTracer(logger *slog.Logger) *QueryTracer {
+	return &QueryTracer{logger: logger}
+}
+
+type queryStartKey struct{}
+
+type queryStart struct {
+	name string
+	at   time.Time
+}
+
+func (tracer *QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, queryStart{
+		name: tracing.QueryName(data.SQL),
+		at:   time.Now(),
+	})
+}
+
+func (tracer *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+
+	args := []any{
+		slog.String("query", start.name),
+		slog.Duration("duration", time.Since(start.at)),
+		slog.Int64("rows", data.CommandTag.RowsAffected()),
+	}
+	if data.Err != nil && !errors.Is(data.Err, pgx.ErrNoRows) {
+		tracer.logger.ErrorContext(ctx, "query failed", append(args, slog.Any("error", data.Err))...)
+		return
+	}
+	tracer.logger.DebugContext(ctx, "query", args...)
+}
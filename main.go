@@ -2,36 +2,314 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"syscall"
 
+	"github.com/jackc/pgx/v5/multitracer"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
 	server "github.com/singhJasvinder101/go_bank/api"
+	"github.com/singhJasvinder101/go_bank/db/migrations"
 	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/events"
+	"github.com/singhJasvinder101/go_bank/gapi"
+	"github.com/singhJasvinder101/go_bank/health"
+	"github.com/singhJasvinder101/go_bank/logging"
+	"github.com/singhJasvinder101/go_bank/metrics"
+	"github.com/singhJasvinder101/go_bank/ratelimit"
+	"github.com/singhJasvinder101/go_bank/stream"
+	"github.com/singhJasvinder101/go_bank/token"
+	"github.com/singhJasvinder101/go_bank/tracing"
 	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/singhJasvinder101/go_bank/webhooks"
+	"google.golang.org/grpc"
 )
 
 func main() {
-    env_config, err := utils.LoadConfig([]string{".", "/app"})
+    // migrate only needs the database, not how the server authenticates
+    migrate := len(os.Args) > 1 && os.Args[1] == "migrate"
+    loadConfig := utils.LoadConfig
+    if migrate {
+        loadConfig = utils.LoadToolConfig
+    }
+
+    env_config, err := loadConfig([]string{".", "/app"})
     if err != nil {
         log.Fatal("cannot load config: ", err)
     }
 
+    logger, err := logging.New(os.Stdout, env_config.LOG_LEVEL, env_config.LOG_FORMAT)
+    if err != nil {
+        log.Fatal("cannot create logger: ", err)
+    }
+    // the log package and libraries logging through it write structured
+    // records too
+    slog.SetDefault(logger)
+
+    if migrate {
+        if err := runMigrate(env_config, logger, os.Args[2:]); err != nil {
+            log.Fatal("cannot migrate: ", err)
+        }
+        return
+    }
+
+    // SIGTERM from Docker and Ctrl-C start a graceful shutdown
+    ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+    defer stop()
 
-    config, err := pgxpool.ParseConfig(env_config.DB_SOURCE)
+    shutdownTracing, err := tracing.Setup(ctx, env_config.TRACE_EXPORTER, env_config.OTLP_ENDPOINT)
     if err != nil {
-        log.Fatal("cannot parse db config: ", err)
+        log.Fatal("cannot set up tracing: ", err)
+    }
+
+    if env_config.AUTO_MIGRATE {
+        if err := migrations.Up(ctx, env_config.DB_SOURCE); err != nil {
+            log.Fatal("cannot migrate db: ", err)
+        }
     }
 
-    conn, err := pgxpool.NewWithConfig(context.Background(), config)
+    conn, err := newPool(env_config, env_config.DB_SOURCE, logger)
     if err != nil {
         log.Fatal("cannot connect to db: ", err)
     }
 
-    store := db.NewStore(conn)
-    srv := server.NewServer(store)
+    prometheus.MustRegister(metrics.NewPoolCollector(conn))
+    sqlStore := db.NewStore(conn)
+    sqlStore.SetTxObserver(metrics.TxObserver{})
+    sqlStore.SetLogger(logger)
+
+    var replica *pgxpool.Pool
+    if env_config.DB_REPLICA_SOURCE != "" {
+        replica, err = newPool(env_config, env_config.DB_REPLICA_SOURCE, logger)
+        if err != nil {
+            log.Fatal("cannot connect to replica: ", err)
+        }
+        sqlStore.SetReplica(replica)
+    }
+    store := metrics.NewStore(sqlStore)
+
+
+    // background workers outlive the servers so that events of drained
+    // requests are still relayed
+    workerCtx, stopWorkers := context.WithCancel(context.Background())
+    var workers sync.WaitGroup
+    runWorker := func(run func(context.Context)) {
+        workers.Add(1)
+        go func() {
+            defer workers.Done()
+            run(workerCtx)
+        }()
+    }
+
+    // relays, dispatchers and listeners log to the logger of the requests
+    startRelay := func(publisher events.EventPublisher, consumer string) {
+        relay := events.NewRelay(store, publisher, consumer)
+        relay.SetLogger(logger)
+        runWorker(relay.Run)
+    }
+
+    // the event log has a file of its own, stdout carries the logs
+    var eventLog *events.StreamPublisher
+    if env_config.EVENT_LOG_PATH != "" {
+        eventLog, err = events.NewFilePublisher(env_config.EVENT_LOG_PATH)
+        if err != nil {
+            log.Fatal("cannot open event log: ", err)
+        }
+        startRelay(eventLog, "event_log")
+    }
+
+    broker := stream.NewBroker()
+    broker.SetLogger(logger)
+    if env_config.ENABLE_WEBHOOKS {
+        startRelay(webhooks.NewPublisher(store), "webhooks")
+        webhookNetworks, err := utils.ParseNetworks(env_config.WEBHOOK_ALLOWED_NETWORKS)
+        if err != nil {
+            log.Fatal("cannot parse webhook networks: ", err)
+        }
+        dispatcher := webhooks.NewDispatcher(store)
+        dispatcher.SetLogger(logger)
+        dispatcher.AllowedNetworks = webhookNetworks
+        runWorker(dispatcher.Run)
+    }
+    runWorker(func(ctx context.Context) { broker.Listen(ctx, conn) })
+
+    serveErr := make(chan error, 2)
+
+    var tokenMaker token.Maker
+    if env_config.TOKEN_SYMMETRIC_KEY != "" {
+        tokenMaker, err = token.NewJWTMaker(env_config.TOKEN_SYMMETRIC_KEY)
+        if err != nil {
+            log.Fatal("cannot create token maker: ", err)
+        }
+    }
+    authProxies, err := utils.ParseNetworks(env_config.AUTH_PROXIES)
+    if err != nil {
+        log.Fatal("cannot parse auth proxies: ", err)
+    }
+
+    trustedProxies, err := utils.ParseNetworks(env_config.TRUSTED_PROXIES)
+    if err != nil {
+        log.Fatal("cannot parse trusted proxies: ", err)
+    }
+
+    rateLimits, err := parseRateLimits(env_config)
+    if err != nil {
+        log.Fatal("cannot parse rate limits: ", err)
+    }
+    var buckets ratelimit.Store
+    switch env_config.RATE_LIMIT_BACKEND {
+    case "memory":
+        buckets = ratelimit.NewMemoryStore()
+    case "postgres":
+        postgresBuckets := ratelimit.NewPostgresStore(store)
+        postgresBuckets.SetLogger(logger)
+        runWorker(postgresBuckets.Run)
+        buckets = postgresBuckets
+    default:
+        log.Fatalf("unknown rate limit backend %q", env_config.RATE_LIMIT_BACKEND)
+    }
+
+    srv := server.NewServer(env_config, store, broker)
+    srv.SetLogger(logger)
+    srv.SetAuth(tokenMaker, authProxies)
+    srv.SetRateLimits(buckets, rateLimits)
+    if err := srv.SetTrustedProxies(trustedProxies); err != nil {
+        log.Fatal("cannot set trusted proxies: ", err)
+    }
+
+    var grpcServer *grpc.Server
+    if env_config.ENABLE_GRPC {
+        grpcService := gapi.NewServer(env_config, store)
+        grpcService.SetAuth(tokenMaker, authProxies)
+        grpcService.SetRateLimits(buckets, rateLimits)
+        grpcService.SetLogger(logger)
+        grpcServer = gapi.NewGRPCServer(grpcService)
+        listener, err := net.Listen("tcp", env_config.GRPC_ADDRESS)
+        if err != nil {
+            log.Fatal("cannot create grpc listener: ", err)
+        }
+        go func() {
+            log.Printf("start gRPC server at %s", listener.Addr())
+            serveErr <- grpcServer.Serve(listener)
+        }()
 
-    err = srv.Start(env_config.ADDRESS)
+        gateway, err := gapi.NewGateway(workerCtx, env_config.GRPC_ADDRESS)
+        if err != nil {
+            log.Fatal("cannot create grpc gateway: ", err)
+        }
+        srv.Mount("/v1", gateway)
+    }
+
+    latestMigration, err := health.LatestMigration(migrations.FS)
     if err != nil {
-        log.Fatal("cannot start server: ", err)
+        log.Fatal("cannot read migrations: ", err)
+    }
+    readiness := health.NewChecker()
+    readiness.Add("database", health.Database(conn))
+    readiness.Add("pool", health.PoolSaturation(health.PgxPoolUsage(conn), health.DefaultMaxPoolUtilization))
+    readiness.Add("migrations", health.Migrations(conn, latestMigration))
+    if replica != nil {
+        readiness.Add("replica", health.Database(replica))
+    }
+    srv.SetReadiness(readiness)
+
+    go func() {
+        log.Printf("start HTTP server at %s", env_config.ADDRESS)
+        serveErr <- srv.Start(env_config.ADDRESS)
+    }()
+
+    select {
+    case <-ctx.Done():
+        log.Print("shutting down")
+    case err := <-serveErr:
+        log.Print("server stopped, shutting down: ", err)
+    }
+    stop()
+
+    // drain in-flight requests on both servers, then stop the workers and
+    // close what they use
+    shutdownCtx, cancel := context.WithTimeout(context.Background(), env_config.SHUTDOWN_TIMEOUT)
+    defer cancel()
+
+    if err := srv.Shutdown(shutdownCtx); err != nil {
+        log.Print("cannot drain HTTP server: ", err)
+    }
+
+    if grpcServer != nil {
+        grpcStopped := make(chan struct{})
+        go func() {
+            grpcServer.GracefulStop()
+            close(grpcStopped)
+        }()
+        select {
+        case <-grpcStopped:
+        case <-shutdownCtx.Done():
+            log.Print("cannot drain gRPC server: ", shutdownCtx.Err())
+            grpcServer.Stop()
+        }
+    }
+
+    stopWorkers()
+    workers.Wait()
+
+    if eventLog != nil {
+        if err := eventLog.Close(); err != nil {
+            log.Print("cannot close event log: ", err)
+        }
+    }
+    conn.Close()
+    if replica != nil {
+        replica.Close()
+    }
+
+    if err := shutdownTracing(shutdownCtx); err != nil {
+        log.Print("cannot flush traces: ", err)
+    }
+    log.Print("shutdown complete")
+}
+
+// newPool connects a pool to the database at dsn, sized and traced as
+// configured.
+func newPool(env_config utils.Config, dsn string, logger *slog.Logger) (*pgxpool.Pool, error) {
+    config, err := pgxpool.ParseConfig(dsn)
+    if err != nil {
+        return nil, err
+    }
+    config.MaxConns = env_config.DB_MAX_CONNS
+    config.MinConns = env_config.DB_MIN_CONNS
+    config.MaxConnLifetime = env_config.DB_MAX_CONN_LIFETIME
+    config.MaxConnIdleTime = env_config.DB_MAX_CONN_IDLE_TIME
+    config.HealthCheckPeriod = env_config.DB_HEALTH_CHECK_PERIOD
+    config.ConnConfig.Tracer = multitracer.New(tracing.NewQueryTracer(), logging.NewQueryTracer(logger))
+
+    return pgxpool.NewWithConfig(context.Background(), config)
+}
+
+func parseRateLimits(config utils.Config) (limits ratelimit.Limits, err error) {
+    if limits.IP, err = ratelimit.ParseLimit(config.RATE_LIMIT_IP); err != nil {
+        return
+    }
+    if limits.User, err = ratelimit.ParseLimit(config.RATE_LIMIT_USER); err != nil {
+        return
+    }
+    if limits.Transfers, err = ratelimit.ParseLimit(config.RATE_LIMIT_TRANSFERS); err != nil {
+        return
+    }
+    if limits.Groups, err = ratelimit.ParseGroupLimits(config.RATE_LIMIT_GROUPS); err != nil {
+        return
+    }
+    for group := range limits.Groups {
+        if !slices.Contains(server.RateLimitGroups, group) {
+            err = fmt.Errorf("unknown route group %q in RATE_LIMIT_GROUPS, want one of %v", group, server.RateLimitGroups)
+            return
+        }
     }
-}
\ No newline at end of file
+    return
+}
@@ -0,0 +1,73 @@
+// Package metrics defines the Prometheus metrics of the service. They are
+// registered with the default registry and exposed on /metrics.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "gobank"
+
+// Reasons a transfer is rejected, used as the reason label of TransfersRejected.
+const (
+	RejectInsufficientFunds = "insufficient_funds"
+	RejectAccountFrozen     = "account_frozen"
+)
+
+var (
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of HTTP requests by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	TxDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "tx_duration_seconds",
+		Help:      "Duration of database transaction attempts by transaction and outcome.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"tx", "outcome"})
+
+	TxRollbacks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "tx_rollbacks_total",
+		Help:      "Database transaction attempts rolled back.",
+	}, []string{"tx"})
+
+	TxRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "tx_retries_total",
+		Help:      "Database transactions retried, by the deadlock or serialization failure that made them fail.",
+	}, []string{"tx", "reason"})
+
+	TransfersCreated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transfers_created_total",
+		Help:      "Transfers made, by kind and currency.",
+	}, []string{"kind", "currency"})
+
+	AmountTransferred = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transferred_amount_total",
+		Help:      "Money moved by transfers in the minor unit of each currency.",
+	}, []string{"currency"})
+
+	TransfersRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transfers_rejected_total",
+		Help:      "Transfers refused, by reason.",
+	}, []string{"reason"})
+
+	RateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "rate_limited_total",
+		Help:      "Requests refused by a rate limit, by limit.",
+	}, []string{"limit"})
+)
@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"context"
+
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+)
+
+// Kinds of transfer, used as the kind label of TransfersCreated.
+const (
+	transferKindTransfer = "transfer"
+	transferKindPot      = "pot"
+	transferKindApproval = "approval"
+	transferKindRoundUp  = "round_up"
+)
+
+// Store is a db.Store that records business metrics for the transfers made
+// through it.
+type Store struct {
+	db.Store
+}
+
+// NewStore wraps store so that its transfers are counted.
+func NewStore(store db.Store) *Store {
+	return &Store{Store: store}
+}
+
+func (store *Store) TransferTx(ctx context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+	result, err := store.Store.TransferTx(ctx, arg)
+	if err == nil {
+		recordTransfer(transferKindTransfer, result)
+	}
+	return result, err
+}
+
+func (store *Store) PotTransferTx(ctx context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+	result, err := store.Store.PotTransferTx(ctx, arg)
+	if err == nil {
+		recordTransfer(transferKindPot, result)
+	}
+	return result, err
+}
+
+func (store *Store) ApprovalDecisionTx(ctx context.Context, arg db.ApprovalDecisionTxParams) (db.ApprovalDecisionTxResult, error) {
+	result, err := store.Store.ApprovalDecisionTx(ctx, arg)
+	if err == nil && result.Transfer != nil {
+		recordTransfer(transferKindApproval, *result.Transfer)
+	}
+	return result, err
+}
+
+func recordTransfer(kind string, result db.TransferTxResult) {
+	currency := result.FromAccount.Currency
+
+	TransfersCreated.WithLabelValues(kind, currency).Inc()
+	AmountTransferred.WithLabelValues(currency).Add(float64(result.Transfer.Amount))
+
+	if result.RoundUp != nil {
+		TransfersCreated.WithLabelValues(transferKindRoundUp, currency).Inc()
+		AmountTransferred.WithLabelValues(currency).Add(float64(result.RoundUp.Amount))
+	}
+}
@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	mockdb "github.com/singhJasvinder101/go_bank/db/mock"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreRecordsTransfers(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	result := db.TransferTxResult{
+		Transfer:    db.Transfer{Amount: 25},
+		FromAccount: db.Account{Currency: "CAD"},
+		RoundUp:     &db.Transfer{Amount: 5},
+	}
+
+	mock := mockdb.NewMockStore(controller)
+	mock.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(result, nil)
+	mock.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, context.Canceled)
+
+	created := TransfersCreated.WithLabelValues(transferKindTransfer, "CAD")
+	roundUps := TransfersCreated.WithLabelValues(transferKindRoundUp, "CAD")
+	amount := AmountTransferred.WithLabelValues("CAD")
+	before := []float64{testutil.ToFloat64(created), testutil.ToFloat64(roundUps), testutil.ToFloat64(amount)}
+
+	store := NewStore(mock)
+	_, err := store.TransferTx(context.Background(), db.TransferTxParams{})
+	require.NoError(t, err)
+
+	// failed transfers are not counted
+	_, err = store.TransferTx(context.Background(), db.TransferTxParams{})
+	require.ErrorIs(t, err, context.Canceled)
+
+	require.Equal(t, before[0]+1, testutil.ToFloat64(created))
+	require.Equal(t, before[1]+1, testutil.ToFloat64(roundUps))
+	require.Equal(t, before[2]+30, testutil.ToFloat64(amount))
+}
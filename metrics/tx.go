@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"time"
+
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+)
+
+// TxObserver records the transactions of a db.SQLStore.
+type TxObserver struct{}
+
+var _ db.TxObserver = TxObserver{}
+
+func (TxObserver) ObserveTx(name string, duration time.Duration, outcome string) {
+	TxDuration.WithLabelValues(name, outcome).Observe(duration.Seconds())
+	if outcome == db.TxRolledBack {
+		TxRollbacks.WithLabelValues(name).Inc()
+	}
+}
+
+func (TxObserver) ObserveTxRetry(name string, code string) {
+	TxRetries.WithLabelValues(name, retryReason(code)).Inc()
+}
+
+// retryReason names the Postgres error code a transaction was retried for.
+func retryReason(code string) string {
+	switch code {
+	case db.DeadlockDetected:
+		return "deadlock"
+	case db.SerializationFailure:
+		return "serialization_failure"
+	}
+	return code
+}
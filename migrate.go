@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/singhJasvinder101/go_bank/db/migrations"
+	"github.com/singhJasvinder101/go_bank/health"
+	"github.com/singhJasvinder101/go_bank/utils"
+)
+
+const migrateUsage = "usage: migrate up [N] | down [N|all] | status | force VERSION"
+
+// runMigrate runs the migrate subcommand against DB_SOURCE:
+//
+//	migrate up [N]        apply all or the next N pending migrations
+//	migrate down [N|all]  roll back the last N migrations, 1 by default, or all
+//	migrate status        log the applied and the latest embedded version
+//	migrate force VERSION mark VERSION as applied and clean after a failure
+//
+// The outcome is logged to logger.
+func runMigrate(env_config utils.Config, logger *slog.Logger, args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return errors.New(migrateUsage)
+	}
+
+	var n int
+	all := len(args) == 2 && args[0] == "down" && args[1] == "all"
+	if len(args) == 2 && !all {
+		var err error
+		n, err = strconv.Atoi(args[1])
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid argument %q: %s", args[1], migrateUsage)
+		}
+	}
+
+	m, err := migrations.New(env_config.DB_SOURCE)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	switch args[0] {
+	case "up":
+		if n > 0 {
+			err = m.Steps(n)
+		} else {
+			err = m.Up()
+		}
+	case "down":
+		if all {
+			err = m.Down()
+		} else {
+			err = m.Steps(-max(n, 1))
+		}
+	case "status":
+		return logMigrationStatus(logger, m)
+	case "force":
+		if len(args) != 2 {
+			return errors.New(migrateUsage)
+		}
+		err = m.Force(n)
+	default:
+		return errors.New(migrateUsage)
+	}
+
+	if errors.Is(err, migrations.ErrNoChange) {
+		logger.Info("no migration to apply")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return logMigrationStatus(logger, m)
+}
+
+// logMigrationStatus logs the applied and the latest embedded version.
+func logMigrationStatus(logger *slog.Logger, m *migrations.Migrator) error {
+	latest, err := health.LatestMigration(migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	version, dirty, err := m.Version()
+	switch {
+	case errors.Is(err, migrations.ErrNilVersion):
+		logger.Info("no migration applied", slog.Uint64("latest", uint64(latest)))
+	case err != nil:
+		return err
+	case dirty:
+		logger.Warn("migration is dirty, fix the schema and run migrate force",
+			slog.Uint64("version", uint64(version)), slog.Uint64("latest", uint64(latest)))
+	default:
+		logger.Info("migration status", slog.Uint64("version", uint64(version)), slog.Uint64("latest", uint64(latest)))
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: service_go_bank.proto
+
+package pb
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+var File_service_go_bank_proto protoreflect.FileDescriptor
+
+const file_service_go_bank_proto_rawDesc = "" +
+	"\n" +
+	"\x15service_go_bank.proto\x12\x02pb\x1a\raccount.proto\x1a\ventry.proto\x1a\x1cgoogle/api/annotations.proto\x1a\x0etransfer.proto2\xdd\x03\n" +
+	"\x06GoBank\x12O\n" +
+	"\rCreateAccount\x12\x18.pb.CreateAccountRequest\x1a\v.pb.Account\"\x17\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/v1/accounts\x12W\n" +
+	"\n" +
+	"GetAccount\x12\x15.pb.GetAccountRequest\x1a\v.pb.Account\"%\x82\xd3\xe4\x93\x02\x1f\x12\x1d/v1/accounts/{account_number}\x12W\n" +
+	"\fListAccounts\x12\x17.pb.ListAccountsRequest\x1a\x18.pb.ListAccountsResponse\"\x14\x82\xd3\xe4\x93\x02\x0e\x12\f/v1/accounts\x12m\n" +
+	"\vListEntries\x12\x16.pb.ListEntriesRequest\x1a\x17.pb.ListEntriesResponse\"-\x82\xd3\xe4\x93\x02'\x12%/v1/accounts/{account_number}/entries\x12a\n" +
+	"\x0eCreateTransfer\x12\x19.pb.CreateTransferRequest\x1a\x1a.pb.CreateTransferResponse\"\x18\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/v1/transfersB)Z'github.com/singhJasvinder101/go_bank/pbb\x06proto3"
+
+var file_service_go_bank_proto_goTypes = []any{
+	(*CreateAccountRequest)(nil),   // 0: pb.CreateAccountRequest
+	(*GetAccountRequest)(nil),      // 1: pb.GetAccountRequest
+	(*ListAccountsRequest)(nil),    // 2: pb.ListAccountsRequest
+	(*ListEntriesRequest)(nil),     // 3: pb.ListEntriesRequest
+	(*CreateTransferRequest)(nil),  // 4: pb.CreateTransferRequest
+	(*Account)(nil),                // 5: pb.Account
+	(*ListAccountsResponse)(nil),   // 6: pb.ListAccountsResponse
+	(*ListEntriesResponse)(nil),    // 7: pb.ListEntriesResponse
+	(*CreateTransferResponse)(nil), // 8: pb.CreateTransferResponse
+}
+var file_service_go_bank_proto_depIdxs = []int32{
+	0, // 0: pb.GoBank.CreateAccount:input_type -> pb.CreateAccountRequest
+	1, // 1: pb.GoBank.GetAccount:input_type -> pb.GetAccountRequest
+	2, // 2: pb.GoBank.ListAccounts:input_type -> pb.ListAccountsRequest
+	3, // 3: pb.GoBank.ListEntries:input_type -> pb.ListEntriesRequest
+	4, // 4: pb.GoBank.CreateTransfer:input_type -> pb.CreateTransferRequest
+	5, // 5: pb.GoBank.CreateAccount:output_type -> pb.Account
+	5, // 6: pb.GoBank.GetAccount:output_type -> pb.Account
+	6, // 7: pb.GoBank.ListAccounts:output_type -> pb.ListAccountsResponse
+	7, // 8: pb.GoBank.ListEntries:output_type -> pb.ListEntriesResponse
+	8, // 9: pb.GoBank.CreateTransfer:output_type -> pb.CreateTransferResponse
+	5, // [5:10] is the sub-list for method output_type
+	0, // [0:5] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_service_go_bank_proto_init() }
+func file_service_go_bank_proto_init() {
+	if File_service_go_bank_proto != nil {
+		return
+	}
+	file_account_proto_init()
+	file_entry_proto_init()
+	file_transfer_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_service_go_bank_proto_rawDesc), len(file_service_go_bank_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_service_go_bank_proto_goTypes,
+		DependencyIndexes: file_service_go_bank_proto_depIdxs,
+	}.Build()
+	File_service_go_bank_proto = out.File
+	file_service_go_bank_proto_goTypes = nil
+	file_service_go_bank_proto_depIdxs = nil
+}
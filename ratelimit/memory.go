@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// pruneInterval is how often a MemoryStore drops buckets that have refilled.
+const pruneInterval = time.Minute
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	// full is when the bucket will have refilled and can be dropped.
+	full time.Time
+}
+
+// MemoryStore keeps buckets in memory. Its limits are per instance.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+func (store *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	now := store.now()
+	store.prune(now)
+
+	b, ok := store.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Requests), updated: now}
+		store.buckets[key] = b
+	}
+
+	b.tokens = math.Min(float64(limit.Requests), b.tokens+now.Sub(b.updated).Seconds()*limit.Rate())
+	b.updated = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+
+	result := newResult(limit, b.tokens, allowed)
+	b.full = now.Add(result.Reset)
+	return result, nil
+}
+
+// prune drops the buckets that are full again, as a new full bucket is
+// created for their next request anyway.
+func (store *MemoryStore) prune(now time.Time) {
+	if now.Sub(store.lastPrune) < pruneInterval {
+		return
+	}
+	store.lastPrune = now
+
+	for key, b := range store.buckets {
+		if !now.Before(b.full) {
+			delete(store.buckets, key)
+		}
+	}
+}
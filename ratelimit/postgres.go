@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+)
+
+// Querier runs the rate limit queries of the store.
+type Querier interface {
+	TakeRateLimitToken(ctx context.Context, arg db.TakeRateLimitTokenParams) (db.RateLimitBucket, error)
+	DeleteIdleRateLimitBuckets(ctx context.Context, maxIdleSeconds int32) error
+}
+
+// PostgresStore keeps buckets in the rate_limit_buckets table, so that every
+// instance of the service enforces the same limits.
+type PostgresStore struct {
+	querier Querier
+	logger  *slog.Logger
+	// MaxIdle is how long a bucket is kept after its last request. It must
+	// be longer than the longest limit period.
+	MaxIdle time.Duration
+	// PruneInterval is how often Run deletes idle buckets.
+	PruneInterval time.Duration
+}
+
+// NewPostgresStore returns a PostgresStore running its queries with querier.
+func NewPostgresStore(querier Querier) *PostgresStore {
+	return &PostgresStore{
+		querier:       querier,
+		logger:        slog.Default(),
+		MaxIdle:       time.Hour,
+		PruneInterval: 10 * time.Minute,
+	}
+}
+
+// SetLogger makes Run log failed prunes to logger.
+func (store *PostgresStore) SetLogger(logger *slog.Logger) {
+	store.logger = logger
+}
+
+func (store *PostgresStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	bucket, err := store.querier.TakeRateLimitToken(ctx, db.TakeRateLimitTokenParams{
+		Key:   key,
+		Burst: float64(limit.Requests),
+		Rate:  limit.Rate(),
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	return newResult(limit, bucket.Tokens, bucket.Allowed), nil
+}
+
+// Run deletes idle buckets every PruneInterval until ctx is done.
+func (store *PostgresStore) Run(ctx context.Context) {
+	ticker := time.NewTicker(store.PruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := store.querier.DeleteIdleRateLimitBuckets(ctx, int32(store.MaxIdle.Seconds())); err != nil && ctx.Err() == nil {
+			store.logger.ErrorContext(ctx, "cannot prune rate limit buckets", slog.Any("error", err))
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/singhJasvinder101/go_bank/db/mock"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresStore(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	limit := Limit{Requests: 10, Per: time.Minute}
+	arg := db.TakeRateLimitTokenParams{Key: "transfers:alice", Burst: 10, Rate: 10.0 / 60}
+
+	querier := mockdb.NewMockStore(controller)
+	querier.EXPECT().
+		TakeRateLimitToken(gomock.Any(), gomock.Eq(arg)).
+		Times(1).
+		Return(db.RateLimitBucket{Key: arg.Key, Tokens: 4.5, Allowed: true}, nil)
+	querier.EXPECT().
+		TakeRateLimitToken(gomock.Any(), gomock.Eq(arg)).
+		Times(1).
+		Return(db.RateLimitBucket{Key: arg.Key, Tokens: 0.5, Allowed: false}, nil)
+	querier.EXPECT().
+		TakeRateLimitToken(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.RateLimitBucket{}, errors.New("connection refused"))
+
+	store := NewPostgresStore(querier)
+
+	result, err := store.Take(context.Background(), arg.Key, limit)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+	require.Equal(t, 4, result.Remaining)
+	require.Equal(t, 33*time.Second, result.Reset)
+
+	result, err = store.Take(context.Background(), arg.Key, limit)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Equal(t, 3*time.Second, result.RetryAfter)
+
+	_, err = store.Take(context.Background(), arg.Key, limit)
+	require.Error(t, err)
+}
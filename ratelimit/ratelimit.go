@@ -0,0 +1,110 @@
+// Package ratelimit limits how often a client may call the API with token
+// buckets. Buckets are kept in memory for a single instance or in Postgres
+// when several instances share the limits.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/singhJasvinder101/go_bank/utils"
+)
+
+// Limit allows Requests requests per Per. Up to Requests requests may be
+// made at once, after which the bucket refills at a steady rate. The zero
+// Limit allows every request.
+type Limit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// ParseLimit parses a limit written as "<requests>/<duration>", e.g. "30/1m".
+// An empty string is the zero Limit.
+func ParseLimit(s string) (Limit, error) {
+	requests, per, err := utils.ParseRate(s)
+	if err != nil {
+		return Limit{}, err
+	}
+	return Limit{Requests: requests, Per: per}, nil
+}
+
+// Limits are the limits enforced by a server. A zero Limit is not enforced.
+type Limits struct {
+	// IP limits the requests of every client IP.
+	IP Limit
+	// User limits the requests of every authenticated user.
+	User Limit
+	// Transfers limits the transfers made by every authenticated user.
+	Transfers Limit
+	// Groups limits the requests of every authenticated user to a group of
+	// routes, by the name of the group.
+	Groups map[string]Limit
+}
+
+// ParseGroupLimits parses limits per group written as "<group>=<limit>"
+// separated by commas, e.g. "webhooks=60/1m".
+func ParseGroupLimits(s string) (map[string]Limit, error) {
+	rates, err := utils.ParseGroupRates(s)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := make(map[string]Limit, len(rates))
+	for group, rate := range rates {
+		if limits[group], err = ParseLimit(rate); err != nil {
+			return nil, err
+		}
+	}
+	return limits, nil
+}
+
+// Enabled reports whether the limit restricts requests at all.
+func (limit Limit) Enabled() bool {
+	return limit.Requests > 0 && limit.Per > 0
+}
+
+// Rate is the number of tokens added to a bucket per second.
+func (limit Limit) Rate() float64 {
+	return float64(limit.Requests) / limit.Per.Seconds()
+}
+
+func (limit Limit) String() string {
+	return fmt.Sprintf("%d/%s", limit.Requests, limit.Per)
+}
+
+// Result is the outcome of taking a token from a bucket.
+type Result struct {
+	Allowed bool
+	// Limit is the size of the bucket.
+	Limit int
+	// Remaining is the number of requests that can be made right away.
+	Remaining int
+	// Reset is how long it takes the bucket to refill completely.
+	Reset time.Duration
+	// RetryAfter is how long a refused request should wait before retrying.
+	RetryAfter time.Duration
+}
+
+// newResult describes a bucket of limit holding tokens after a request.
+func newResult(limit Limit, tokens float64, allowed bool) Result {
+	rate := limit.Rate()
+	result := Result{
+		Allowed:   allowed,
+		Limit:     limit.Requests,
+		Remaining: int(math.Floor(math.Max(tokens, 0))),
+		Reset:     time.Duration((float64(limit.Requests) - tokens) / rate * float64(time.Second)),
+	}
+	if !allowed {
+		result.RetryAfter = time.Duration((1 - tokens) / rate * float64(time.Second))
+	}
+	return result
+}
+
+// Store holds the token buckets of the limited clients.
+type Store interface {
+	// Take takes a token from the bucket of key, created full with limit if
+	// it does not exist.
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
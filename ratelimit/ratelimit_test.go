@@ -0,0 +1,94 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLimit(t *testing.T) {
+	testCases := []struct {
+		input string
+		limit Limit
+		ok    bool
+	}{
+		{"30/1m", Limit{Requests: 30, Per: time.Minute}, true},
+		{"5/1s", Limit{Requests: 5, Per: time.Second}, true},
+		{"", Limit{}, true},
+		{"30", Limit{}, false},
+		{"0/1m", Limit{}, false},
+		{"ten/1m", Limit{}, false},
+		{"30/minute", Limit{}, false},
+		{"30/-1m", Limit{}, false},
+	}
+
+	for _, testCase := range testCases {
+		limit, err := ParseLimit(testCase.input)
+		if !testCase.ok {
+			require.Error(t, err, testCase.input)
+			continue
+		}
+		require.NoError(t, err, testCase.input)
+		require.Equal(t, testCase.limit, limit)
+	}
+
+	require.False(t, Limit{}.Enabled())
+	require.True(t, Limit{Requests: 1, Per: time.Second}.Enabled())
+}
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Now()
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	limit := Limit{Requests: 3, Per: 3 * time.Second}
+
+	// the burst is allowed at once
+	for i := 2; i >= 0; i-- {
+		result, err := store.Take(ctx, "alice", limit)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+		require.Equal(t, 3, result.Limit)
+		require.Equal(t, i, result.Remaining)
+	}
+
+	result, err := store.Take(ctx, "alice", limit)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Equal(t, 0, result.Remaining)
+	require.Equal(t, time.Second, result.RetryAfter)
+	require.Equal(t, 3*time.Second, result.Reset)
+
+	// other keys have their own bucket
+	result, err = store.Take(ctx, "bob", limit)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	// a token is added every second
+	now = now.Add(time.Second)
+	result, err = store.Take(ctx, "alice", limit)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+	require.Equal(t, 0, result.Remaining)
+
+	// refilled buckets are dropped
+	now = now.Add(pruneInterval)
+	_, err = store.Take(ctx, "carol", limit)
+	require.NoError(t, err)
+	require.Len(t, store.buckets, 1)
+}
+
+func TestParseGroupLimits(t *testing.T) {
+	limits, err := ParseGroupLimits("webhooks=60/1m,transfers=5/1s")
+	require.NoError(t, err)
+	require.Equal(t, map[string]Limit{
+		"webhooks":  {Requests: 60, Per: time.Minute},
+		"transfers": {Requests: 5, Per: time.Second},
+	}, limits)
+
+	_, err = ParseGroupLimits("webhooks=often")
+	require.Error(t, err)
+}
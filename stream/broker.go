@@ -0,0 +1,149 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+)
+
+const (
+	subscriberBuffer = 64
+	reconnectDelay   = time.Second
+)
+
+// Broker fans account updates out to the subscribers of each account.
+//
+// A subscriber that falls behind, or that may have missed updates while the
+// database listener reconnected, has its channel closed. Clients are expected
+// to reconnect and catch up from the last entry they saw.
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[int64]map[chan db.AccountUpdate]struct{}
+	logger      *slog.Logger
+}
+
+func NewBroker() *Broker {
+	return &Broker{
+		subscribers: make(map[int64]map[chan db.AccountUpdate]struct{}),
+		logger:      slog.Default(),
+	}
+}
+
+// SetLogger makes the broker log listener failures to logger.
+func (broker *Broker) SetLogger(logger *slog.Logger) {
+	broker.logger = logger
+}
+
+// Subscribe returns a channel receiving the updates of an account and a
+// function that ends the subscription.
+func (broker *Broker) Subscribe(accountID int64) (<-chan db.AccountUpdate, func()) {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+
+	ch := make(chan db.AccountUpdate, subscriberBuffer)
+	if broker.subscribers[accountID] == nil {
+		broker.subscribers[accountID] = make(map[chan db.AccountUpdate]struct{})
+	}
+	broker.subscribers[accountID][ch] = struct{}{}
+
+	unsubscribe := func() {
+		broker.mu.Lock()
+		defer broker.mu.Unlock()
+		broker.remove(accountID, ch)
+	}
+	return ch, unsubscribe
+}
+
+// remove closes a subscriber channel unless it was removed already.
+// The caller must hold mu.
+func (broker *Broker) remove(accountID int64, ch chan db.AccountUpdate) {
+	subscribers := broker.subscribers[accountID]
+	if _, ok := subscribers[ch]; !ok {
+		return
+	}
+
+	delete(subscribers, ch)
+	if len(subscribers) == 0 {
+		delete(broker.subscribers, accountID)
+	}
+	close(ch)
+}
+
+// Publish hands an update to the subscribers of its account without blocking.
+func (broker *Broker) Publish(update db.AccountUpdate) {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+
+	for ch := range broker.subscribers[update.AccountID] {
+		select {
+		case ch <- update:
+		default:
+			broker.remove(update.AccountID, ch)
+		}
+	}
+}
+
+// closeAll ends every subscription.
+func (broker *Broker) closeAll() {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+
+	for accountID, subscribers := range broker.subscribers {
+		for ch := range subscribers {
+			broker.remove(accountID, ch)
+		}
+	}
+}
+
+// Listen publishes the updates notified on db.AccountUpdateChannel until ctx
+// is cancelled, reconnecting whenever the connection is lost.
+func (broker *Broker) Listen(ctx context.Context, pool *pgxpool.Pool) {
+	for {
+		err := broker.listen(ctx, pool)
+		if ctx.Err() != nil {
+			return
+		}
+
+		broker.logger.ErrorContext(ctx, "account update listener failed", slog.Any("error", err))
+		broker.closeAll()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (broker *Broker) listen(ctx context.Context, pool *pgxpool.Pool) error {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// a listening connection must not go back to the pool
+	pgConn := conn.Hijack()
+	defer pgConn.Close(context.Background())
+
+	if _, err := pgConn.Exec(ctx, "LISTEN "+db.AccountUpdateChannel); err != nil {
+		return err
+	}
+
+	for {
+		notification, err := pgConn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var update db.AccountUpdate
+		if err := json.Unmarshal([]byte(notification.Payload), &update); err != nil {
+			broker.logger.ErrorContext(ctx, "bad account update payload", slog.String("payload", notification.Payload), slog.Any("error", err))
+			continue
+		}
+		broker.Publish(update)
+	}
+}
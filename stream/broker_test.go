@@ -0,0 +1,47 @@
+package stream
+
+import (
+	"testing"
+
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrokerPublish(t *testing.T) {
+	broker := NewBroker()
+
+	updates, unsubscribe := broker.Subscribe(1)
+	others, unsubscribeOthers := broker.Subscribe(2)
+	defer unsubscribeOthers()
+
+	broker.Publish(db.AccountUpdate{AccountID: 1, Balance: 10, Entry: db.Entry{ID: 5}})
+
+	update := <-updates
+	require.Equal(t, int64(10), update.Balance)
+	require.Equal(t, int64(5), update.Entry.ID)
+	require.Empty(t, others)
+
+	unsubscribe()
+	_, ok := <-updates
+	require.False(t, ok)
+
+	// unsubscribing twice is harmless
+	unsubscribe()
+}
+
+func TestBrokerDropsSlowSubscriber(t *testing.T) {
+	broker := NewBroker()
+
+	updates, unsubscribe := broker.Subscribe(1)
+	defer unsubscribe()
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		broker.Publish(db.AccountUpdate{AccountID: 1, Entry: db.Entry{ID: int64(i + 1)}})
+	}
+
+	received := 0
+	for range updates {
+		received++
+	}
+	require.Equal(t, subscriberBuffer, received)
+}
@@ -0,0 +1,69 @@
+// Package token issues and verifies the access tokens identifying the users
+// of the HTTP and gRPC APIs.
+package token
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// KeySize is the length of the key signing tokens.
+const KeySize = 32
+
+var (
+	ErrInvalidToken = errors.New("token is invalid")
+	ErrExpiredToken = errors.New("token has expired")
+)
+
+// Maker issues tokens for a user and verifies them.
+type Maker interface {
+	// CreateToken issues a token for username valid for duration.
+	CreateToken(username string, duration time.Duration) (string, error)
+	// VerifyToken returns the username of a valid token.
+	VerifyToken(token string) (string, error)
+}
+
+// JWTMaker issues JSON Web Tokens signed with HMAC-SHA256.
+type JWTMaker struct {
+	key []byte
+}
+
+var _ Maker = (*JWTMaker)(nil)
+
+func NewJWTMaker(key string) (*JWTMaker, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("token key must be %d characters", KeySize)
+	}
+	return &JWTMaker{key: []byte(key)}, nil
+}
+
+func (maker *JWTMaker) CreateToken(username string, duration time.Duration) (string, error) {
+	now := time.Now()
+	claims := jwt.RegisteredClaims{
+		Subject:   username,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(duration)),
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(maker.key)
+}
+
+func (maker *JWTMaker) VerifyToken(token string) (string, error) {
+	var claims jwt.RegisteredClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (any, error) {
+		return maker.key, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return "", ErrExpiredToken
+		}
+		return "", ErrInvalidToken
+	}
+
+	if claims.Subject == "" {
+		return "", ErrInvalidToken
+	}
+	return claims.Subject, nil
+}
@@ -0,0 +1,56 @@
+package token_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/singhJasvinder101/go_bank/token"
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func randomKey() string {
+	return (utils.RandomString() + utils.RandomString() + utils.RandomString() + utils.RandomString())[:token.KeySize]
+}
+
+func TestJWTMaker(t *testing.T) {
+	maker, err := token.NewJWTMaker(randomKey())
+	require.NoError(t, err)
+
+	username := utils.RandomOwner()
+	signed, err := maker.CreateToken(username, time.Minute)
+	require.NoError(t, err)
+
+	got, err := maker.VerifyToken(signed)
+	require.NoError(t, err)
+	require.Equal(t, username, got)
+}
+
+func TestJWTMakerRejects(t *testing.T) {
+	maker, err := token.NewJWTMaker(randomKey())
+	require.NoError(t, err)
+	other, err := token.NewJWTMaker(randomKey())
+	require.NoError(t, err)
+
+	expired, err := maker.CreateToken(utils.RandomOwner(), -time.Minute)
+	require.NoError(t, err)
+	_, err = maker.VerifyToken(expired)
+	require.ErrorIs(t, err, token.ErrExpiredToken)
+
+	forged, err := other.CreateToken(utils.RandomOwner(), time.Minute)
+	require.NoError(t, err)
+	_, err = maker.VerifyToken(forged)
+	require.ErrorIs(t, err, token.ErrInvalidToken)
+
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.RegisteredClaims{
+		Subject:   utils.RandomOwner(),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+	_, err = maker.VerifyToken(unsigned)
+	require.ErrorIs(t, err, token.ErrInvalidToken)
+
+	_, err = token.NewJWTMaker("short")
+	require.Error(t, err)
+}
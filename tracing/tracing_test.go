@@ -0,0 +1,70 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestQueryName(t *testing.T) {
+	testCases := []struct {
+		sql  string
+		name string
+	}{
+		{"-- name: CreateTransfer :one\nINSERT INTO transfers", "CreateTransfer"},
+		{"  select 1", "SELECT"},
+		{"", "query"},
+	}
+
+	for _, testCase := range testCases {
+		require.Equal(t, testCase.name, QueryName(testCase.sql))
+	}
+}
+
+func TestQueryTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(sdktrace.NewTracerProvider())
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "SQLStore.TransferTx")
+	tracer := NewQueryTracer()
+
+	queryCtx := tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "-- name: GetAccount :one\nSELECT 1"})
+	require.Equal(t, TraceID(ctx), TraceID(queryCtx))
+	tracer.TraceQueryEnd(queryCtx, nil, pgx.TraceQueryEndData{})
+
+	queryCtx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "UPDATE accounts"})
+	tracer.TraceQueryEnd(queryCtx, nil, pgx.TraceQueryEndData{Err: errors.New("deadlock detected")})
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	require.Equal(t, "pgx GetAccount", spans[0].Name())
+	require.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	require.Equal(t, codes.Unset, spans[0].Status().Code)
+
+	require.Equal(t, "pgx UPDATE", spans[1].Name())
+	require.Equal(t, codes.Error, spans[1].Status().Code)
+}
+
+func TestSetup(t *testing.T) {
+	shutdown, err := Setup(context.Background(), ExporterNone, "")
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+
+	_, err = Setup(context.Background(), "jaeger", "")
+	require.ErrorContains(t, err, "unknown trace exporter")
+}
+
+func TestTraceID(t *testing.T) {
+	require.Empty(t, TraceID(context.Background()))
+}
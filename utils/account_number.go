@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// accountDigits is the number of random digits after the bank code.
+const accountDigits = 12
+
+var ErrInvalidAccountNumber = errors.New("invalid account number")
+
+// NewAccountNumber generates an IBAN-style account number: the country code,
+// ISO 7064 mod-97 check digits, the bank code and random account digits.
+func NewAccountNumber(countryCode, bankCode string) string {
+	return newAccountNumber(rand.Intn, countryCode, bankCode)
+}
+
+// newAccountNumber draws the account digits from intn.
+func newAccountNumber(intn func(int) int, countryCode, bankCode string) string {
+	var bban strings.Builder
+	bban.WriteString(strings.ToUpper(bankCode))
+	for i := 0; i < accountDigits; i++ {
+		bban.WriteByte(byte('0' + intn(10)))
+	}
+
+	countryCode = strings.ToUpper(countryCode)
+	check := 98 - mod97(bban.String()+countryCode+"00")
+	return fmt.Sprintf("%s%02d%s", countryCode, check, bban.String())
+}
+
+// NormalizeAccountNumber removes spaces and upper-cases an account number as
+// it may be typed by a user.
+func NormalizeAccountNumber(number string) string {
+	return strings.ToUpper(strings.ReplaceAll(number, " ", ""))
+}
+
+// ValidateAccountNumber checks the format and the check digits of a
+// normalized account number.
+func ValidateAccountNumber(number string) error {
+	if len(number) < 5 || len(number) > 34 {
+		return fmt.Errorf("%w: must be between 5 and 34 characters", ErrInvalidAccountNumber)
+	}
+
+	for i, c := range number {
+		switch {
+		case i < 2 && (c < 'A' || c > 'Z'):
+			return fmt.Errorf("%w: must start with a country code", ErrInvalidAccountNumber)
+		case i >= 2 && i < 4 && (c < '0' || c > '9'):
+			return fmt.Errorf("%w: check digits must be numeric", ErrInvalidAccountNumber)
+		case (c < '0' || c > '9') && (c < 'A' || c > 'Z'):
+			return fmt.Errorf("%w: must only contain letters and digits", ErrInvalidAccountNumber)
+		}
+	}
+
+	if mod97(number[4:]+number[:4]) != 1 {
+		return fmt.Errorf("%w: check digits do not match", ErrInvalidAccountNumber)
+	}
+
+	return nil
+}
+
+// mod97 computes the ISO 7064 mod-97 remainder of s, with letters counting as
+// two digit numbers from A=10 to Z=35.
+func mod97(s string) int {
+	remainder := 0
+	for _, c := range s {
+		if c >= 'A' && c <= 'Z' {
+			remainder = (remainder*100 + int(c-'A'+10)) % 97
+		} else {
+			remainder = (remainder*10 + int(c-'0')) % 97
+		}
+	}
+	return remainder
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAccountNumber(t *testing.T) {
+	// well known example numbers
+	require.NoError(t, ValidateAccountNumber("GB82WEST12345698765432"))
+	require.NoError(t, ValidateAccountNumber("DE89370400440532013000"))
+
+	require.ErrorIs(t, ValidateAccountNumber("GB83WEST12345698765432"), ErrInvalidAccountNumber)
+	require.ErrorIs(t, ValidateAccountNumber("1282WEST12345698765432"), ErrInvalidAccountNumber)
+	require.ErrorIs(t, ValidateAccountNumber("GB82-WEST"), ErrInvalidAccountNumber)
+	require.ErrorIs(t, ValidateAccountNumber("GB"), ErrInvalidAccountNumber)
+	require.ErrorIs(t, ValidateAccountNumber("12345"), ErrInvalidAccountNumber)
+}
+
+func TestNewAccountNumber(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		number := NewAccountNumber("gb", "gobk")
+		require.Len(t, number, 4+4+accountDigits)
+		require.Equal(t, "GB", number[:2])
+		require.Equal(t, "GOBK", number[4:8])
+		require.NoError(t, ValidateAccountNumber(number))
+	}
+
+	require.Equal(t, "GB82WEST12345698765432", NormalizeAccountNumber("gb82 west 1234 5698 7654 32"))
+}
@@ -1,33 +1,495 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/singhJasvinder101/go_bank/token"
 	"github.com/spf13/viper"
 )
 
+// Profiles select a set of defaults suited to where the service runs.
+const (
+	ProfileDev  = "dev"
+	ProfileTest = "test"
+	ProfileProd = "prod"
+)
+
 type Config struct {
+	// profile the config was loaded for: dev, test or prod
+	APP_ENV   string `mapstructure:"APP_ENV"`
 	DB_SOURCE string `mapstructure:"DB_SOURCE"`
-	ADDRESS  string `mapstructure:"ADDRESS"`
+	ADDRESS   string `mapstructure:"ADDRESS"`
+	// address the gRPC server listens on
+	GRPC_ADDRESS string `mapstructure:"GRPC_ADDRESS"`
+	// timeouts of the HTTP server, streaming responses are exempt from the write timeout
+	HTTP_READ_TIMEOUT  time.Duration `mapstructure:"HTTP_READ_TIMEOUT"`
+	HTTP_WRITE_TIMEOUT time.Duration `mapstructure:"HTTP_WRITE_TIMEOUT"`
+	HTTP_IDLE_TIMEOUT  time.Duration `mapstructure:"HTTP_IDLE_TIMEOUT"`
+	// how long in-flight requests may take to finish once shutdown starts
+	SHUTDOWN_TIMEOUT time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
+	// apply pending migrations on start, one instance at a time
+	AUTO_MIGRATE bool `mapstructure:"AUTO_MIGRATE"`
+	// optional read replica that serves reads tolerating replication lag
+	DB_REPLICA_SOURCE string `mapstructure:"DB_REPLICA_SOURCE"`
+	// size of the database connection pools
+	DB_MAX_CONNS int32 `mapstructure:"DB_MAX_CONNS"`
+	DB_MIN_CONNS int32 `mapstructure:"DB_MIN_CONNS"`
+	// how long pooled connections live and may sit idle, and how often idle
+	// connections are checked
+	DB_MAX_CONN_LIFETIME   time.Duration `mapstructure:"DB_MAX_CONN_LIFETIME"`
+	DB_MAX_CONN_IDLE_TIME  time.Duration `mapstructure:"DB_MAX_CONN_IDLE_TIME"`
+	DB_HEALTH_CHECK_PERIOD time.Duration `mapstructure:"DB_HEALTH_CHECK_PERIOD"`
+	// key signing access tokens and how long the tokens are valid, the key is
+	// required in prod
+	TOKEN_SYMMETRIC_KEY   string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	ACCESS_TOKEN_DURATION time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	// addresses of authenticating proxies, as IPs or CIDR ranges separated by
+	// commas, whose X-Username header identifies the user without a token;
+	// the header is ignored from every other client
+	AUTH_PROXIES string `mapstructure:"AUTH_PROXIES"`
+	// reverse proxies, as IPs or CIDR ranges separated by commas, whose
+	// X-Forwarded-For header names the client IP; the peer address is the
+	// client IP when empty
+	TRUSTED_PROXIES string `mapstructure:"TRUSTED_PROXIES"`
+	// feature toggles: the gRPC server and its gateway, webhook delivery and
+	// the OpenAPI document with Swagger UI
+	ENABLE_GRPC     bool `mapstructure:"ENABLE_GRPC"`
+	ENABLE_WEBHOOKS bool `mapstructure:"ENABLE_WEBHOOKS"`
+	ENABLE_DOCS     bool `mapstructure:"ENABLE_DOCS"`
+	// networks, as IPs or CIDR ranges separated by commas, that webhooks may
+	// be delivered to although they are loopback, private or link-local
+	// addresses; webhooks are only delivered to public addresses otherwise
+	WEBHOOK_ALLOWED_NETWORKS string `mapstructure:"WEBHOOK_ALLOWED_NETWORKS"`
+	// country and bank code used to generate IBAN-style account numbers
+	ACCOUNT_COUNTRY_CODE string `mapstructure:"ACCOUNT_COUNTRY_CODE"`
+	ACCOUNT_BANK_CODE    string `mapstructure:"ACCOUNT_BANK_CODE"`
+	// how long a newly saved beneficiary waits before it can receive large
+	// transfers, and the amount above which a transfer is large, per currency
+	// as <currency>=<amount> separated by commas
+	BENEFICIARY_COOLING_OFF    time.Duration `mapstructure:"BENEFICIARY_COOLING_OFF"`
+	BENEFICIARY_LARGE_TRANSFER string        `mapstructure:"BENEFICIARY_LARGE_TRANSFER"`
+	// file domain events are appended to, empty to keep no event log
+	EVENT_LOG_PATH string `mapstructure:"EVENT_LOG_PATH"`
+	// minimum level of logged records: debug, info, warn or error
+	LOG_LEVEL string `mapstructure:"LOG_LEVEL"`
+	// log record format: json or text
+	LOG_FORMAT string `mapstructure:"LOG_FORMAT"`
+	// where rate limit buckets are kept: memory (per instance) or postgres
+	// (shared by all instances)
+	RATE_LIMIT_BACKEND string `mapstructure:"RATE_LIMIT_BACKEND"`
+	// rate limits as <requests>/<duration>, per client IP, per user and for
+	// the transfers of a user; empty disables a limit
+	RATE_LIMIT_IP        string `mapstructure:"RATE_LIMIT_IP"`
+	RATE_LIMIT_USER      string `mapstructure:"RATE_LIMIT_USER"`
+	RATE_LIMIT_TRANSFERS string `mapstructure:"RATE_LIMIT_TRANSFERS"`
+	// rate limits of a user per route group as <group>=<requests>/<duration>
+	// separated by commas, e.g. "webhooks=60/1m"
+	RATE_LIMIT_GROUPS string `mapstructure:"RATE_LIMIT_GROUPS"`
+	// where spans are exported: none, stdout or otlp
+	TRACE_EXPORTER string `mapstructure:"TRACE_EXPORTER"`
+	// host:port of the OTLP gRPC collector used by the otlp exporter
+	OTLP_ENDPOINT string `mapstructure:"OTLP_ENDPOINT"`
 }
 
-func LoadConfig(path []string) (config Config, err error) {
-	for _, p := range path {
-		viper.AddConfigPath(p)
-	}
-	viper.SetConfigName("app")
-	viper.SetConfigType("env")
+// defaults apply to every profile.
+var defaults = map[string]any{
+	"APP_ENV":                    ProfileProd,
+	"ADDRESS":                    "0.0.0.0:3000",
+	"GRPC_ADDRESS":               "0.0.0.0:9090",
+	"HTTP_READ_TIMEOUT":          10 * time.Second,
+	"HTTP_WRITE_TIMEOUT":         30 * time.Second,
+	"HTTP_IDLE_TIMEOUT":          2 * time.Minute,
+	"SHUTDOWN_TIMEOUT":           30 * time.Second,
+	"AUTO_MIGRATE":               false,
+	"DB_MAX_CONNS":               10,
+	"DB_MIN_CONNS":               0,
+	"DB_MAX_CONN_LIFETIME":       time.Hour,
+	"DB_MAX_CONN_IDLE_TIME":      30 * time.Minute,
+	"DB_HEALTH_CHECK_PERIOD":     time.Minute,
+	"ACCESS_TOKEN_DURATION":      15 * time.Minute,
+	"ENABLE_GRPC":                true,
+	"ENABLE_WEBHOOKS":            true,
+	"ENABLE_DOCS":                true,
+	"ACCOUNT_COUNTRY_CODE":       "GB",
+	"ACCOUNT_BANK_CODE":          "GOBK",
+	"BENEFICIARY_COOLING_OFF":    24 * time.Hour,
+	"BENEFICIARY_LARGE_TRANSFER": "USD=1000,EUR=1000,BTC=1",
+	"EVENT_LOG_PATH":             "events.log",
+	"LOG_LEVEL":                  "info",
+	"LOG_FORMAT":                 "json",
+	"RATE_LIMIT_BACKEND":         "memory",
+	"RATE_LIMIT_IP":              "600/1m",
+	"RATE_LIMIT_USER":            "300/1m",
+	"RATE_LIMIT_TRANSFERS":       "30/1m",
+	"TRACE_EXPORTER":             "none",
+	"OTLP_ENDPOINT":              "localhost:4317",
+}
 
-	viper.AutomaticEnv()
+// profileDefaults override defaults for a profile.
+var profileDefaults = map[string]map[string]any{
+	ProfileDev: {
+		"LOG_LEVEL":    "debug",
+		"LOG_FORMAT":   "text",
+		"AUTO_MIGRATE": true,
+		"AUTH_PROXIES": "127.0.0.1,::1",
+		// local webhook receivers
+		"WEBHOOK_ALLOWED_NETWORKS": "127.0.0.0/8,::1",
+	},
+	ProfileTest: {
+		"LOG_LEVEL":            "warn",
+		"LOG_FORMAT":           "text",
+		"ENABLE_GRPC":          false,
+		"ENABLE_WEBHOOKS":      false,
+		"RATE_LIMIT_IP":        "",
+		"RATE_LIMIT_USER":      "",
+		"RATE_LIMIT_TRANSFERS": "",
+		"AUTH_PROXIES":         "127.0.0.1,::1",
+	},
+	ProfileProd: {
+		"ENABLE_DOCS": false,
+	},
+}
+
+// LoadConfig loads the config of the profile named by APP_ENV and validates
+// it for the server. Each setting is taken from the first of these that sets it:
+//
+//  1. the environment variable of the same name
+//  2. app.<profile>.env
+//  3. app.env
+//  4. the defaults of the profile
+//  5. the defaults of every profile
+//
+// Config files are looked up in path and are optional, so the service can be
+// configured from the environment alone. The profile is prod unless APP_ENV
+// names another, so that a deployment missing it fails safe. An environment
+// variable set to the empty string counts as set, e.g. RATE_LIMIT_IP= turns
+// the IP limit off.
+func LoadConfig(path []string) (Config, error) {
+	config, err := loadConfig(path)
+	if err != nil {
+		return config, err
+	}
+	return config, config.Validate()
+}
 
-	err = viper.ReadInConfig()
+// LoadToolConfig loads the config like LoadConfig for tools that work on the
+// database, such as migrate and the gobank CLI, and skips the checks of how
+// the server authenticates users. Tools that issue tokens check the key
+// themselves.
+func LoadToolConfig(path []string) (Config, error) {
+	config, err := loadConfig(path)
 	if err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-			panic(fmt.Errorf("Config file not found %w", err))
+		return config, err
+	}
+	return config, config.validate(false)
+}
+
+// loadConfig loads the config of the profile named by APP_ENV.
+func loadConfig(path []string) (config Config, err error) {
+	v := viper.New()
+	v.SetConfigType("env")
+	for key, value := range defaults {
+		v.SetDefault(key, value)
+	}
+
+	// environment variables only reach Unmarshal for keys viper knows about
+	v.AutomaticEnv()
+	v.AllowEmptyEnv(true)
+	for _, key := range configKeys() {
+		if err = v.BindEnv(key); err != nil {
+			return
 		}
+	}
+
+	if err = mergeConfigFile(v, path, "app.env"); err != nil {
 		return
 	}
 
-	err = viper.Unmarshal(&config)
+	profile := v.GetString("APP_ENV")
+	overrides, ok := profileDefaults[profile]
+	if !ok {
+		err = fmt.Errorf("unknown APP_ENV %q: want %s, %s or %s", profile, ProfileDev, ProfileTest, ProfileProd)
+		return
+	}
+	for key, value := range overrides {
+		v.SetDefault(key, value)
+	}
+
+	if err = mergeConfigFile(v, path, "app."+profile+".env"); err != nil {
+		return
+	}
+
+	err = v.Unmarshal(&config)
 	return
 }
+
+// mergeConfigFile merges the first file called name found in path into v.
+func mergeConfigFile(v *viper.Viper, path []string, name string) error {
+	for _, dir := range path {
+		file := filepath.Join(dir, name)
+		if _, err := os.Stat(file); err != nil {
+			continue
+		}
+
+		v.SetConfigFile(file)
+		if err := v.MergeInConfig(); err != nil {
+			return fmt.Errorf("cannot read config file %s: %w", file, err)
+		}
+		return nil
+	}
+	return nil
+}
+
+// configKeys returns the keys of every setting of Config.
+func configKeys() []string {
+	fields := reflect.VisibleFields(reflect.TypeOf(Config{}))
+	keys := make([]string, 0, len(fields))
+	for _, field := range fields {
+		keys = append(keys, field.Tag.Get("mapstructure"))
+	}
+	return keys
+}
+
+// Validate checks every setting the server uses and reports all the invalid
+// ones together.
+func (config Config) Validate() error {
+	return config.validate(true)
+}
+
+// validate checks the settings, including how users are authenticated if
+// server is set.
+func (config Config) validate(server bool) error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	oneOf := func(key string, value string, allowed ...string) {
+		for _, a := range allowed {
+			if value == a {
+				return
+			}
+		}
+		errs = append(errs, fmt.Errorf("%s must be one of %s, got %q", key, strings.Join(allowed, ", "), value))
+	}
+
+	oneOf("APP_ENV", config.APP_ENV, ProfileDev, ProfileTest, ProfileProd)
+	check(config.DB_SOURCE != "", "DB_SOURCE is required")
+	check(config.ADDRESS != "", "ADDRESS is required")
+	check(!config.ENABLE_GRPC || config.GRPC_ADDRESS != "", "GRPC_ADDRESS is required when ENABLE_GRPC is set")
+
+	check(config.HTTP_READ_TIMEOUT > 0, "HTTP_READ_TIMEOUT must be positive")
+	check(config.HTTP_WRITE_TIMEOUT > 0, "HTTP_WRITE_TIMEOUT must be positive")
+	check(config.HTTP_IDLE_TIMEOUT > 0, "HTTP_IDLE_TIMEOUT must be positive")
+	check(config.SHUTDOWN_TIMEOUT > 0, "SHUTDOWN_TIMEOUT must be positive")
+
+	check(config.DB_MAX_CONNS > 0, "DB_MAX_CONNS must be positive")
+	check(config.DB_MIN_CONNS >= 0 && config.DB_MIN_CONNS <= config.DB_MAX_CONNS,
+		"DB_MIN_CONNS must be between 0 and DB_MAX_CONNS (%d)", config.DB_MAX_CONNS)
+	check(config.DB_MAX_CONN_LIFETIME > 0, "DB_MAX_CONN_LIFETIME must be positive")
+	check(config.DB_MAX_CONN_IDLE_TIME > 0, "DB_MAX_CONN_IDLE_TIME must be positive")
+	check(config.DB_HEALTH_CHECK_PERIOD > 0, "DB_HEALTH_CHECK_PERIOD must be positive")
+	check(config.DB_REPLICA_SOURCE == "" || config.DB_REPLICA_SOURCE != config.DB_SOURCE,
+		"DB_REPLICA_SOURCE must differ from DB_SOURCE")
+
+	check(config.TOKEN_SYMMETRIC_KEY == "" || len(config.TOKEN_SYMMETRIC_KEY) == token.KeySize,
+		"TOKEN_SYMMETRIC_KEY must be %d characters", token.KeySize)
+	check(config.ACCESS_TOKEN_DURATION > 0, "ACCESS_TOKEN_DURATION must be positive")
+	if _, err := ParseNetworks(config.AUTH_PROXIES); err != nil {
+		errs = append(errs, fmt.Errorf("AUTH_PROXIES: %w", err))
+	}
+	if _, err := ParseNetworks(config.TRUSTED_PROXIES); err != nil {
+		errs = append(errs, fmt.Errorf("TRUSTED_PROXIES: %w", err))
+	}
+	if server {
+		check(config.TOKEN_SYMMETRIC_KEY != "" || config.AUTH_PROXIES != "",
+			"TOKEN_SYMMETRIC_KEY or AUTH_PROXIES is required to authenticate users")
+	}
+
+	if _, err := ParseNetworks(config.WEBHOOK_ALLOWED_NETWORKS); err != nil {
+		errs = append(errs, fmt.Errorf("WEBHOOK_ALLOWED_NETWORKS: %w", err))
+	}
+
+	check(len(config.ACCOUNT_COUNTRY_CODE) == 2, "ACCOUNT_COUNTRY_CODE must be a two letter country code")
+	check(config.ACCOUNT_BANK_CODE != "", "ACCOUNT_BANK_CODE is required")
+
+	check(config.BENEFICIARY_COOLING_OFF >= 0, "BENEFICIARY_COOLING_OFF must not be negative")
+	if amounts, err := ParseCurrencyAmounts(config.BENEFICIARY_LARGE_TRANSFER); err != nil {
+		errs = append(errs, fmt.Errorf("BENEFICIARY_LARGE_TRANSFER: %w", err))
+	} else {
+		for _, currency := range SupportedCurrencies {
+			_, ok := amounts[currency]
+			check(ok, "BENEFICIARY_LARGE_TRANSFER must set an amount for %s", currency)
+		}
+	}
+
+	oneOf("LOG_LEVEL", strings.ToLower(config.LOG_LEVEL), "debug", "info", "warn", "error")
+	oneOf("LOG_FORMAT", strings.ToLower(config.LOG_FORMAT), "json", "text")
+
+	oneOf("RATE_LIMIT_BACKEND", config.RATE_LIMIT_BACKEND, "memory", "postgres")
+	for key, value := range map[string]string{
+		"RATE_LIMIT_IP":        config.RATE_LIMIT_IP,
+		"RATE_LIMIT_USER":      config.RATE_LIMIT_USER,
+		"RATE_LIMIT_TRANSFERS": config.RATE_LIMIT_TRANSFERS,
+	} {
+		if _, _, err := ParseRate(value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+	if _, err := ParseGroupRates(config.RATE_LIMIT_GROUPS); err != nil {
+		errs = append(errs, fmt.Errorf("RATE_LIMIT_GROUPS: %w", err))
+	}
+
+	oneOf("TRACE_EXPORTER", config.TRACE_EXPORTER, "none", "stdout", "otlp")
+	check(config.TRACE_EXPORTER != "otlp" || config.OTLP_ENDPOINT != "", "OTLP_ENDPOINT is required when TRACE_EXPORTER is otlp")
+
+	if config.APP_ENV == ProfileProd {
+		check(!server || config.TOKEN_SYMMETRIC_KEY != "", "TOKEN_SYMMETRIC_KEY is required in prod")
+		check(config.TRACE_EXPORTER != "stdout", "TRACE_EXPORTER stdout is not allowed in prod")
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// ParseRate parses a rate written as "<requests>/<duration>", e.g. "30/1m".
+// An empty string is no rate at all and parses to zeros.
+func ParseRate(s string) (requests int, per time.Duration, err error) {
+	if s == "" {
+		return 0, 0, nil
+	}
+
+	n, d, ok := strings.Cut(s, "/")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid rate %q: want <requests>/<duration>", s)
+	}
+
+	requests, err = strconv.Atoi(n)
+	if err != nil || requests < 1 {
+		return 0, 0, fmt.Errorf("invalid rate %q: requests must be a positive integer", s)
+	}
+	per, err = time.ParseDuration(d)
+	if err != nil || per <= 0 {
+		return 0, 0, fmt.Errorf("invalid rate %q: duration must be positive", s)
+	}
+
+	return requests, per, nil
+}
+
+// ParseGroupRates parses rates per group written as "<group>=<rate>"
+// separated by commas, e.g. "webhooks=60/1m,beneficiaries=10/1m", and returns
+// the rates by group. Every rate is checked with ParseRate.
+func ParseGroupRates(s string) (map[string]string, error) {
+	rates := make(map[string]string)
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		group, rate, ok := strings.Cut(field, "=")
+		if !ok || group == "" || rate == "" {
+			return nil, fmt.Errorf("invalid group rate %q: want <group>=<requests>/<duration>", field)
+		}
+		if _, _, err := ParseRate(rate); err != nil {
+			return nil, err
+		}
+		rates[group] = rate
+	}
+	return rates, nil
+}
+
+// ParseCurrencyAmounts parses amounts per currency written as
+// "<currency>=<amount>" separated by commas, e.g. "USD=1000,EUR=900".
+func ParseCurrencyAmounts(s string) (map[string]int64, error) {
+	amounts := make(map[string]int64)
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		currency, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid amount %q: want <currency>=<amount>", field)
+		}
+		amount, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || amount < 0 {
+			return nil, fmt.Errorf("invalid amount %q: must be a non-negative integer", field)
+		}
+		amounts[strings.ToUpper(currency)] = amount
+	}
+	return amounts, nil
+}
+
+// LargeTransfer returns the amount in currency above which a transfer to a
+// beneficiary is large. Currencies without an amount have every transfer
+// count as large.
+func (config Config) LargeTransfer(currency string) int64 {
+	amounts, err := ParseCurrencyAmounts(config.BENEFICIARY_LARGE_TRANSFER)
+	if err != nil {
+		return 0
+	}
+	return amounts[currency]
+}
+
+// ParseNetworks parses a list of IP addresses and CIDR ranges separated by
+// commas, e.g. "10.0.0.0/8,127.0.0.1". An empty string is no networks at all.
+func ParseNetworks(s string) ([]netip.Prefix, error) {
+	var networks []netip.Prefix
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		if strings.Contains(field, "/") {
+			network, err := netip.ParsePrefix(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid network %q", field)
+			}
+			networks = append(networks, network.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %q", field)
+		}
+		networks = append(networks, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return networks, nil
+}
+
+// InNetworks reports whether the IP of address, given as host:port or as an
+// IP alone, lies in one of networks.
+func InNetworks(networks []netip.Prefix, address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+
+	addr = addr.Unmap()
+	for _, network := range networks {
+		if network.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -43,3 +43,87 @@ func RandomCurrency() string {
 	return currencies[rand.Intn(n)]
 }
 
+
+func RandomAccountNumber() string {
+	return NewAccountNumber("GB", "GOBK")
+}
+
+// SupportedCurrencies are the currencies accounts and transfers may use.
+var SupportedCurrencies = []string{"USD", "EUR", "BTC"}
+
+var firstNames = []string{
+	"Aarav", "Amelia", "Ana", "Ben", "Chloe", "Daniel", "Diego", "Elena",
+	"Emma", "Fatima", "Grace", "Hana", "Isaac", "Jasmine", "Jonas", "Kai",
+	"Laura", "Leo", "Lucas", "Maya", "Mohammed", "Nina", "Noah", "Olivia",
+	"Omar", "Priya", "Rahul", "Sara", "Sofia", "Thomas", "Yuki", "Zoe",
+}
+
+var lastNames = []string{
+	"Anderson", "Bauer", "Chen", "Costa", "Dubois", "Evans", "Fischer",
+	"Garcia", "Gupta", "Hughes", "Ivanova", "Jensen", "Kim", "Kowalski",
+	"Lopez", "Martin", "Meyer", "Nakamura", "Novak", "Okafor", "Patel",
+	"Rossi", "Santos", "Schmidt", "Singh", "Smith", "Tanaka", "Walker",
+}
+
+// Random generates values like the Random* functions, and realistic names,
+// from its own source so that a seed reproduces them. Unlike
+// RandomCurrency, Currency only returns SupportedCurrencies.
+type Random struct {
+	rand *rand.Rand
+}
+
+// NewRandom returns a generator seeded with seed.
+func NewRandom(seed int64) *Random {
+	return &Random{rand: rand.New(rand.NewSource(seed))}
+}
+
+// Int returns a number in [min, max).
+func (r *Random) Int(min, max int) int {
+	return min + r.rand.Intn(max-min)
+}
+
+// Int64 returns a number in [min, max).
+func (r *Random) Int64(min, max int64) int64 {
+	return min + r.rand.Int63n(max-min)
+}
+
+// Float64 returns a number in [0, 1).
+func (r *Random) Float64() float64 {
+	return r.rand.Float64()
+}
+
+// Shuffle shuffles n elements with swap.
+func (r *Random) Shuffle(n int, swap func(i, j int)) {
+	r.rand.Shuffle(n, swap)
+}
+
+func (r *Random) String() string {
+	var s strings.Builder
+	for i := 0; i < 10; i++ {
+		s.WriteByte(alphabet[r.rand.Intn(len(alphabet))])
+	}
+	return s.String()
+}
+
+func (r *Random) Owner() string {
+	return r.String()
+}
+
+func (r *Random) Money() int64 {
+	return int64(r.Int(0, 1000))
+}
+
+// Currency returns one of SupportedCurrencies.
+func (r *Random) Currency() string {
+	return SupportedCurrencies[r.rand.Intn(len(SupportedCurrencies))]
+}
+
+// Name returns a first and a last name.
+func (r *Random) Name() (first, last string) {
+	return firstNames[r.rand.Intn(len(firstNames))], lastNames[r.rand.Intn(len(lastNames))]
+}
+
+// AccountNumber generates an account number like NewAccountNumber.
+func (r *Random) AccountNumber(countryCode, bankCode string) string {
+	return newAccountNumber(r.rand.Intn, countryCode, bankCode)
+}
@@ -0,0 +1,71 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+)
+
+// ErrForbiddenAddress is returned for webhook URLs that resolve to an address
+// of the service's own host or network, which webhooks could otherwise be
+// used to reach.
+var ErrForbiddenAddress = errors.New("webhook address is not public")
+
+// forbiddenNetworks are the non-public ranges that netip.Addr has no
+// predicate for.
+var forbiddenNetworks = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	// carrier-grade NAT
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// CheckAddr rejects loopback, private, link-local, multicast and unspecified
+// addresses, unless they fall within allowed.
+func CheckAddr(addr netip.Addr, allowed []netip.Prefix) error {
+	addr = addr.Unmap()
+	for _, network := range allowed {
+		if network.Contains(addr) {
+			return nil
+		}
+	}
+
+	forbidden := addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast()
+	for _, network := range forbiddenNetworks {
+		forbidden = forbidden || network.Contains(addr)
+	}
+	if forbidden {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, addr)
+	}
+	return nil
+}
+
+// CheckURL resolves the host of a webhook URL and checks every address it
+// resolves to. The host may resolve differently when a delivery is sent, so
+// the dispatcher checks the address it connects to again.
+func CheckURL(ctx context.Context, rawURL string, allowed []netip.Prefix) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	host := u.Hostname()
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return CheckAddr(addr, allowed)
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("cannot resolve webhook host %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if err := CheckAddr(addr, allowed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
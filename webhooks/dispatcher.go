@@ -0,0 +1,262 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+)
+
+// Delivery statuses.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+const (
+	// MaxAttempts is how often a delivery is attempted before it is given up.
+	MaxAttempts = 10
+	// DisableAfterFailures is how many consecutive failed attempts disable a webhook.
+	DisableAfterFailures = 20
+
+	baseBackoff = 30 * time.Second
+	maxBackoff  = 6 * time.Hour
+
+	defaultBatchSize    = 50
+	defaultPollInterval = 5 * time.Second
+	defaultConcurrency  = 10
+	deliveryTimeout     = 10 * time.Second
+	// claimLease is how long claimed deliveries are skipped by other
+	// dispatchers, well beyond the time an attempt takes. Deliveries of a
+	// dispatcher that stopped half way are attempted again once it ends.
+	claimLease = time.Minute
+)
+
+// Backoff returns how long to wait before the next attempt of a delivery that
+// has failed attempts times.
+func Backoff(attempts int32) time.Duration {
+	backoff := baseBackoff
+	for i := int32(1); i < attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxBackoff)
+}
+
+// deliveryBody is the JSON body POSTed to webhook URLs.
+type deliveryBody struct {
+	ID   int64           `json:"id"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Dispatcher sends queued deliveries to their webhooks, retrying failed ones
+// with exponential backoff. Several dispatchers may run at once, each claims
+// the deliveries it attempts.
+type Dispatcher struct {
+	store  db.Store
+	client *http.Client
+	logger *slog.Logger
+
+	BatchSize    int32
+	PollInterval time.Duration
+	// Concurrency bounds how many deliveries of a batch are sent at once.
+	Concurrency int
+	// AllowedNetworks may be delivered to although they are not public, see
+	// CheckAddr.
+	AllowedNetworks []netip.Prefix
+}
+
+func NewDispatcher(store db.Store) *Dispatcher {
+	dispatcher := &Dispatcher{
+		store:        store,
+		logger:       slog.Default(),
+		BatchSize:    defaultBatchSize,
+		PollInterval: defaultPollInterval,
+		Concurrency:  defaultConcurrency,
+	}
+
+	// every connection, including those of redirects, is checked once the
+	// host is resolved, so that a webhook cannot be pointed at a private
+	// address after it was created; proxies would hide that address
+	dialer := &net.Dialer{Timeout: deliveryTimeout, Control: dispatcher.checkDial}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	dispatcher.client = &http.Client{Timeout: deliveryTimeout, Transport: transport}
+
+	return dispatcher
+}
+
+// SetLogger makes the dispatcher log failed batches and disabled webhooks to
+// logger.
+func (dispatcher *Dispatcher) SetLogger(logger *slog.Logger) {
+	dispatcher.logger = logger
+}
+
+// checkDial is the net.Dialer Control function rejecting connections to
+// addresses that are not public.
+func (dispatcher *Dispatcher) checkDial(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	return CheckAddr(addrPort.Addr(), dispatcher.AllowedNetworks)
+}
+
+// Run dispatches due deliveries until ctx is cancelled.
+func (dispatcher *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(dispatcher.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		n, err := dispatcher.DispatchBatch(ctx)
+		if err != nil && ctx.Err() == nil {
+			dispatcher.logger.ErrorContext(ctx, "cannot dispatch webhook deliveries", slog.Any("error", err))
+		}
+		if err == nil && n == int(dispatcher.BatchSize) {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchBatch claims the next batch of due deliveries, attempts them
+// concurrently and returns how many were claimed.
+func (dispatcher *Dispatcher) DispatchBatch(ctx context.Context) (int, error) {
+	deliveries, err := dispatcher.store.ClaimDueWebhookDeliveries(ctx, db.ClaimDueWebhookDeliveriesParams{
+		BatchSize:    dispatcher.BatchSize,
+		LeaseSeconds: int32(claimLease / time.Second),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	slots := make(chan struct{}, max(dispatcher.Concurrency, 1))
+	for _, delivery := range deliveries {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			if err := dispatcher.dispatch(ctx, delivery); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return len(deliveries), errors.Join(errs...)
+}
+
+// dispatch attempts a single delivery and records the outcome. Only errors
+// of the store are returned; a failed attempt is recorded for retry.
+func (dispatcher *Dispatcher) dispatch(ctx context.Context, delivery db.WebhookDelivery) error {
+	webhook, err := dispatcher.store.GetWebhook(ctx, delivery.WebhookID)
+	if err != nil {
+		return err
+	}
+
+	sendErr := dispatcher.send(ctx, webhook, delivery)
+	if sendErr == nil {
+		if err := dispatcher.store.MarkWebhookDeliveryDelivered(ctx, delivery.ID); err != nil {
+			return err
+		}
+		return dispatcher.store.RecordWebhookSuccess(ctx, webhook.ID)
+	}
+
+	attempts := delivery.Attempts + 1
+	status := DeliveryPending
+	if attempts >= MaxAttempts {
+		status = DeliveryFailed
+	}
+
+	err = dispatcher.store.MarkWebhookDeliveryFailed(ctx, db.MarkWebhookDeliveryFailedParams{
+		Status:         status,
+		LastError:      pgtype.Text{String: sendErr.Error(), Valid: true},
+		BackoffSeconds: int32(Backoff(attempts) / time.Second),
+		ID:             delivery.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	webhook, err = dispatcher.store.RecordWebhookFailure(ctx, db.RecordWebhookFailureParams{
+		MaxFailures: DisableAfterFailures,
+		ID:          webhook.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if webhook.Disabled {
+		dispatcher.logger.WarnContext(ctx, "webhook disabled",
+			slog.Int64("webhook_id", webhook.ID),
+			slog.Int64("consecutive_failures", int64(webhook.ConsecutiveFailures)),
+		)
+	}
+	return nil
+}
+
+// send POSTs a signed delivery to the webhook URL. Any response outside the
+// 2xx range counts as a failure.
+func (dispatcher *Dispatcher) send(ctx context.Context, webhook db.Webhook, delivery db.WebhookDelivery) error {
+	body, err := json.Marshal(deliveryBody{
+		ID:   delivery.EventID,
+		Type: delivery.EventType,
+		Data: delivery.Payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.Url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	timestamp := time.Now().Unix()
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(HeaderEvent, delivery.EventType)
+	request.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	request.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	request.Header.Set(HeaderSignature, Sign(webhook.Secret, timestamp, body))
+
+	response, err := dispatcher.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, 1<<16))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", response.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,309 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/singhJasvinder101/go_bank/db/mock"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/events"
+	"github.com/stretchr/testify/require"
+)
+
+func randomWebhook(t *testing.T, url string) db.Webhook {
+	secret, err := NewSecret()
+	require.NoError(t, err)
+
+	return db.Webhook{
+		ID:         7,
+		Owner:      "alice",
+		Url:        url,
+		EventTypes: []string{db.EventTransferCompleted},
+		Secret:     secret,
+	}
+}
+
+func randomDelivery(webhook db.Webhook, attempts int32) db.WebhookDelivery {
+	return db.WebhookDelivery{
+		ID:        11,
+		WebhookID: webhook.ID,
+		EventID:   42,
+		EventType: db.EventTransferCompleted,
+		Payload:   []byte(`{"amount":10}`),
+		Status:    DeliveryPending,
+		Attempts:  attempts,
+	}
+}
+
+// newTestDispatcher returns a dispatcher allowed to deliver to the loopback
+// receivers of the tests.
+func newTestDispatcher(store db.Store) *Dispatcher {
+	dispatcher := NewDispatcher(store)
+	dispatcher.AllowedNetworks = []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8"), netip.MustParsePrefix("::1/128")}
+	return dispatcher
+}
+
+func TestDispatchSignedDelivery(t *testing.T) {
+	var webhook db.Webhook
+	received := make(chan deliveryBody, 1)
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		err = Verify(webhook.Secret, r.Header.Get(HeaderTimestamp), r.Header.Get(HeaderSignature), body, time.Minute)
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		require.Equal(t, db.EventTransferCompleted, r.Header.Get(HeaderEvent))
+
+		var delivery deliveryBody
+		require.NoError(t, json.Unmarshal(body, &delivery))
+		received <- delivery
+	}))
+	defer receiver.Close()
+
+	webhook = randomWebhook(t, receiver.URL)
+	delivery := randomDelivery(webhook, 0)
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	store := mockdb.NewMockStore(controller)
+
+	store.EXPECT().ClaimDueWebhookDeliveries(gomock.Any(), gomock.Any()).Times(1).Return([]db.WebhookDelivery{delivery}, nil)
+	store.EXPECT().GetWebhook(gomock.Any(), gomock.Eq(webhook.ID)).Times(1).Return(webhook, nil)
+	store.EXPECT().MarkWebhookDeliveryDelivered(gomock.Any(), gomock.Eq(delivery.ID)).Times(1).Return(nil)
+	store.EXPECT().RecordWebhookSuccess(gomock.Any(), gomock.Eq(webhook.ID)).Times(1).Return(nil)
+
+	n, err := newTestDispatcher(store).DispatchBatch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	body := <-received
+	require.Equal(t, delivery.EventID, body.ID)
+	require.JSONEq(t, string(delivery.Payload), string(body.Data))
+}
+
+func TestDispatchFailedDelivery(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	webhook := randomWebhook(t, receiver.URL)
+
+	testCases := []struct {
+		name     string
+		attempts int32
+		status   string
+	}{
+		{name: "Retry", attempts: 2, status: DeliveryPending},
+		{name: "GiveUp", attempts: MaxAttempts - 1, status: DeliveryFailed},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+
+		t.Run(testCase.name, func(t *testing.T) {
+			delivery := randomDelivery(webhook, testCase.attempts)
+
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+			store := mockdb.NewMockStore(controller)
+
+			store.EXPECT().ClaimDueWebhookDeliveries(gomock.Any(), gomock.Any()).Times(1).Return([]db.WebhookDelivery{delivery}, nil)
+			store.EXPECT().GetWebhook(gomock.Any(), gomock.Eq(webhook.ID)).Times(1).Return(webhook, nil)
+			store.EXPECT().MarkWebhookDeliveryDelivered(gomock.Any(), gomock.Any()).Times(0)
+			store.EXPECT().
+				MarkWebhookDeliveryFailed(gomock.Any(), gomock.Any()).
+				Times(1).
+				DoAndReturn(func(_ context.Context, arg db.MarkWebhookDeliveryFailedParams) error {
+					require.Equal(t, delivery.ID, arg.ID)
+					require.Equal(t, testCase.status, arg.Status)
+					require.Equal(t, int32(Backoff(testCase.attempts+1)/time.Second), arg.BackoffSeconds)
+					require.Contains(t, arg.LastError.String, "500")
+					return nil
+				})
+			store.EXPECT().
+				RecordWebhookFailure(gomock.Any(), gomock.Eq(db.RecordWebhookFailureParams{MaxFailures: DisableAfterFailures, ID: webhook.ID})).
+				Times(1).
+				Return(webhook, nil)
+
+			n, err := newTestDispatcher(store).DispatchBatch(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 1, n)
+		})
+	}
+}
+
+func TestDispatchForbiddenAddress(t *testing.T) {
+	requests := make(chan struct{}, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- struct{}{}
+	}))
+	defer receiver.Close()
+
+	webhook := randomWebhook(t, receiver.URL)
+	delivery := randomDelivery(webhook, 0)
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	store := mockdb.NewMockStore(controller)
+
+	store.EXPECT().ClaimDueWebhookDeliveries(gomock.Any(), gomock.Any()).Times(1).Return([]db.WebhookDelivery{delivery}, nil)
+	store.EXPECT().GetWebhook(gomock.Any(), gomock.Eq(webhook.ID)).Times(1).Return(webhook, nil)
+	store.EXPECT().MarkWebhookDeliveryDelivered(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().
+		MarkWebhookDeliveryFailed(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.MarkWebhookDeliveryFailedParams) error {
+			require.Contains(t, arg.LastError.String, ErrForbiddenAddress.Error())
+			return nil
+		})
+	store.EXPECT().RecordWebhookFailure(gomock.Any(), gomock.Any()).Times(1).Return(webhook, nil)
+
+	// the receiver listens on loopback, which is not allowed by default
+	n, err := NewDispatcher(store).DispatchBatch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Empty(t, requests)
+}
+
+func TestDispatchConcurrently(t *testing.T) {
+	const deliveries = 3
+
+	// the receiver only answers once every delivery is in flight
+	var arrived sync.WaitGroup
+	arrived.Add(deliveries)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrived.Done()
+		arrived.Wait()
+	}))
+	defer receiver.Close()
+
+	webhook := randomWebhook(t, receiver.URL)
+	var batch []db.WebhookDelivery
+	for i := range deliveries {
+		delivery := randomDelivery(webhook, 0)
+		delivery.ID = int64(i + 1)
+		batch = append(batch, delivery)
+	}
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	store := mockdb.NewMockStore(controller)
+
+	store.EXPECT().
+		ClaimDueWebhookDeliveries(gomock.Any(), gomock.Eq(db.ClaimDueWebhookDeliveriesParams{
+			BatchSize:    defaultBatchSize,
+			LeaseSeconds: int32(claimLease / time.Second),
+		})).
+		Times(1).
+		Return(batch, nil)
+	store.EXPECT().GetWebhook(gomock.Any(), gomock.Eq(webhook.ID)).Times(deliveries).Return(webhook, nil)
+	store.EXPECT().MarkWebhookDeliveryDelivered(gomock.Any(), gomock.Any()).Times(deliveries).Return(nil)
+	store.EXPECT().RecordWebhookSuccess(gomock.Any(), gomock.Eq(webhook.ID)).Times(deliveries).Return(nil)
+
+	n, err := newTestDispatcher(store).DispatchBatch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, deliveries, n)
+}
+
+func TestCheckURL(t *testing.T) {
+	for _, rawURL := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://[::1]/hook",
+		"http://10.1.2.3/hook",
+		"http://192.168.0.1/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[fe80::1]/hook",
+		"http://0.0.0.0/hook",
+		"http://100.64.0.1/hook",
+		"http://[::ffff:127.0.0.1]/hook",
+		"http://localhost/hook",
+	} {
+		err := CheckURL(context.Background(), rawURL, nil)
+		require.ErrorIs(t, err, ErrForbiddenAddress, rawURL)
+	}
+
+	require.NoError(t, CheckURL(context.Background(), "https://93.184.215.14/hook", nil))
+
+	allowed := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+	require.NoError(t, CheckURL(context.Background(), "http://10.1.2.3/hook", allowed))
+}
+
+func TestBackoff(t *testing.T) {
+	require.Equal(t, baseBackoff, Backoff(1))
+	require.Equal(t, 2*baseBackoff, Backoff(2))
+	require.Equal(t, 8*baseBackoff, Backoff(4))
+	require.Equal(t, maxBackoff, Backoff(100))
+}
+
+func TestVerify(t *testing.T) {
+	secret, err := NewSecret()
+	require.NoError(t, err)
+	body := []byte(`{"id":1}`)
+
+	now := time.Now().Unix()
+	signature := Sign(secret, now, body)
+	require.NoError(t, Verify(secret, itoa(now), signature, body, time.Minute))
+
+	require.ErrorIs(t, Verify(secret, itoa(now), signature, []byte(`{"id":2}`), time.Minute), ErrInvalidSignature)
+	require.ErrorIs(t, Verify("whsec_other", itoa(now), signature, body, time.Minute), ErrInvalidSignature)
+
+	old := now - 3600
+	require.ErrorIs(t, Verify(secret, itoa(old), Sign(secret, old, body), body, time.Minute), ErrInvalidSignature)
+}
+
+func TestPublisherFanOut(t *testing.T) {
+	transfer := db.Transfer{ID: 3, FromAccountID: 1, ToAccountID: 2, Amount: 10}
+	payload, err := json.Marshal(db.TransferCompletedEvent{Transfer: transfer, FromAccountBalance: 90, ToAccountBalance: 110})
+	require.NoError(t, err)
+
+	event := events.Event{
+		ID:            42,
+		AggregateType: db.AggregateTransfer,
+		AggregateID:   transfer.ID,
+		Type:          db.EventTransferCompleted,
+		Payload:       payload,
+	}
+	webhooks := []db.Webhook{{ID: 1}, {ID: 2}}
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	store := mockdb.NewMockStore(controller)
+
+	store.EXPECT().
+		ListAccountNumbers(gomock.Any(), gomock.Eq([]int64{1, 2})).
+		Times(1).
+		Return([]db.ListAccountNumbersRow{{ID: 1, AccountNumber: "GB0001"}, {ID: 2, AccountNumber: "GB0002"}}, nil)
+	store.EXPECT().
+		ListWebhooksForEvent(gomock.Any(), gomock.Eq(db.ListWebhooksForEventParams{AccountIds: []int64{1, 2}, EventType: event.Type})).
+		Times(1).
+		Return(webhooks, nil)
+	store.EXPECT().
+		CreateWebhookDelivery(gomock.Any(), gomock.Any()).
+		Times(len(webhooks)).
+		DoAndReturn(func(_ context.Context, arg db.CreateWebhookDeliveryParams) error {
+			require.Equal(t, event.ID, arg.EventID)
+			// balances are not sent to webhooks
+			require.NotContains(t, string(arg.Payload), "balance")
+			return nil
+		})
+
+	require.NoError(t, NewPublisher(store).Publish(context.Background(), event))
+}
+
+func itoa(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
@@ -0,0 +1,153 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/events"
+)
+
+// Publisher fans domain events out to the webhooks subscribed to them by
+// queueing a delivery per webhook. It is meant to be driven by an
+// events.Relay; deliveries are unique per webhook and event, so events
+// published twice are only delivered once.
+type Publisher struct {
+	store db.Store
+}
+
+func NewPublisher(store db.Store) *Publisher {
+	return &Publisher{store: store}
+}
+
+func (publisher *Publisher) Publish(ctx context.Context, event events.Event) error {
+	accountIDs, payload, err := publisher.webhookPayload(ctx, event)
+	if err != nil || len(accountIDs) == 0 {
+		return err
+	}
+
+	webhooks, err := publisher.store.ListWebhooksForEvent(ctx, db.ListWebhooksForEventParams{
+		AccountIds: accountIDs,
+		EventType:  event.Type,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, webhook := range webhooks {
+		err := publisher.store.CreateWebhookDelivery(ctx, db.CreateWebhookDeliveryParams{
+			WebhookID: webhook.ID,
+			EventID:   event.ID,
+			EventType: event.Type,
+			Payload:   payload,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// accountPayload is the payload of account.created. Like the responses of
+// the API, payloads refer to accounts by their account numbers only.
+type accountPayload struct {
+	AccountNumber string `json:"account_number"`
+	Owner         string `json:"owner"`
+	Balance       int64  `json:"balance"`
+	Currency      string `json:"currency"`
+	// ParentAccountNumber is set for savings pots.
+	ParentAccountNumber string    `json:"parent_account_number,omitempty"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+// transferPayload is the payload of transfer.completed.
+type transferPayload struct {
+	ID                int64     `json:"id"`
+	FromAccountNumber string    `json:"from_account_number"`
+	ToAccountNumber   string    `json:"to_account_number"`
+	Amount            int64     `json:"amount"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// accountEvent and transferEvent decode the payloads of the events. They
+// read times as time.Time, since pgtype.Timestamp cannot read back the JSON
+// it writes.
+type accountEvent struct {
+	ID            int64       `json:"id"`
+	Owner         string      `json:"owner"`
+	Balance       int64       `json:"balance"`
+	Currency      string      `json:"currency"`
+	ParentID      pgtype.Int8 `json:"parent_id"`
+	AccountNumber string      `json:"account_number"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+type transferEvent struct {
+	Transfer struct {
+		ID            int64     `json:"id"`
+		FromAccountID int64     `json:"from_account_id"`
+		ToAccountID   int64     `json:"to_account_id"`
+		Amount        int64     `json:"amount"`
+		CreatedAt     time.Time `json:"created_at"`
+	} `json:"transfer"`
+}
+
+// webhookPayload returns the accounts an event is about and the payload sent
+// to their members. Balances of the counterparty are never sent.
+func (publisher *Publisher) webhookPayload(ctx context.Context, event events.Event) ([]int64, []byte, error) {
+	switch event.Type {
+	case db.EventAccountCreated:
+		var account accountEvent
+		if err := json.Unmarshal(event.Payload, &account); err != nil {
+			return nil, nil, err
+		}
+		var parentNumber string
+		if account.ParentID.Valid {
+			numbers, err := db.AccountNumbers(ctx, publisher.store, nil, account.ParentID.Int64)
+			if err != nil {
+				return nil, nil, err
+			}
+			parentNumber = numbers[account.ParentID.Int64]
+		}
+
+		payload, err := json.Marshal(accountPayload{
+			AccountNumber:       account.AccountNumber,
+			Owner:               account.Owner,
+			Balance:             account.Balance,
+			Currency:            account.Currency,
+			ParentAccountNumber: parentNumber,
+			CreatedAt:           account.CreatedAt,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		return []int64{account.ID}, payload, nil
+	case db.EventTransferCompleted:
+		var completed transferEvent
+		if err := json.Unmarshal(event.Payload, &completed); err != nil {
+			return nil, nil, err
+		}
+		transfer := completed.Transfer
+		numbers, err := db.AccountNumbers(ctx, publisher.store, nil, transfer.FromAccountID, transfer.ToAccountID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		payload, err := json.Marshal(transferPayload{
+			ID:                transfer.ID,
+			FromAccountNumber: numbers[transfer.FromAccountID],
+			ToAccountNumber:   numbers[transfer.ToAccountID],
+			Amount:            transfer.Amount,
+			CreatedAt:         transfer.CreatedAt,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		return []int64{transfer.FromAccountID, transfer.ToAccountID}, payload, nil
+	}
+
+	return nil, nil, nil
+}
@@ -0,0 +1,80 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/singhJasvinder101/go_bank/db/sqlc"
+	"github.com/singhJasvinder101/go_bank/events"
+	"github.com/singhJasvinder101/go_bank/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishRefersToAccountNumbers(t *testing.T) {
+	ctx := context.Background()
+	store := db.NewMemoryStore()
+
+	createAccount := func(owner string, balance int64, parent *db.Account) db.Account {
+		arg := db.CreateAccountParams{
+			Owner:         owner,
+			Balance:       balance,
+			Currency:      "USD",
+			AccountNumber: utils.RandomAccountNumber(),
+		}
+		if parent != nil {
+			arg.ParentID = pgtype.Int8{Int64: parent.ID, Valid: true}
+		}
+		result, err := store.CreateAccountTx(ctx, arg)
+		require.NoError(t, err)
+		return result.Account
+	}
+	alice := createAccount("alice", 100, nil)
+	bob := createAccount("bob", 0, nil)
+	pot := createAccount("alice", 0, &alice)
+
+	webhook, err := store.CreateWebhook(ctx, db.CreateWebhookParams{
+		Owner:      "alice",
+		Url:        "https://example.com/hook",
+		EventTypes: []string{db.EventAccountCreated, db.EventTransferCompleted},
+		Secret:     "secret",
+	})
+	require.NoError(t, err)
+
+	transfer, err := store.TransferTx(ctx, db.TransferTxParams{FromAccountID: alice.ID, ToAccountID: bob.ID, Amount: 10})
+	require.NoError(t, err)
+
+	accountPayload, err := json.Marshal(pot)
+	require.NoError(t, err)
+	transferPayload, err := json.Marshal(db.TransferCompletedEvent{Transfer: transfer.Transfer, FromAccountBalance: 90, ToAccountBalance: 10})
+	require.NoError(t, err)
+
+	publisher := NewPublisher(store)
+	require.NoError(t, publisher.Publish(ctx, events.Event{ID: 1, AggregateID: pot.ID, Type: db.EventAccountCreated, Payload: accountPayload}))
+	require.NoError(t, publisher.Publish(ctx, events.Event{ID: 2, AggregateID: transfer.Transfer.ID, Type: db.EventTransferCompleted, Payload: transferPayload}))
+
+	deliveries, err := store.ListWebhookDeliveries(ctx, db.ListWebhookDeliveriesParams{WebhookID: webhook.ID, Limit: 5})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+
+	payloads := make(map[string]map[string]any)
+	for _, delivery := range deliveries {
+		var payload map[string]any
+		require.NoError(t, json.Unmarshal(delivery.Payload, &payload))
+		payloads[delivery.EventType] = payload
+	}
+
+	account := payloads[db.EventAccountCreated]
+	require.Equal(t, pot.AccountNumber, account["account_number"])
+	require.Equal(t, alice.AccountNumber, account["parent_account_number"])
+	require.NotContains(t, account, "id")
+	require.NotContains(t, account, "parent_id")
+
+	completed := payloads[db.EventTransferCompleted]
+	require.Equal(t, alice.AccountNumber, completed["from_account_number"])
+	require.Equal(t, bob.AccountNumber, completed["to_account_number"])
+	require.Equal(t, 10.0, completed["amount"])
+	require.NotContains(t, completed, "from_account_id")
+	require.NotContains(t, completed, "to_account_id")
+}
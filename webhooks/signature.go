@@ -0,0 +1,62 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Headers sent with every delivery.
+const (
+	HeaderEvent     = "X-GoBank-Event"
+	HeaderDelivery  = "X-GoBank-Delivery"
+	HeaderTimestamp = "X-GoBank-Timestamp"
+	HeaderSignature = "X-GoBank-Signature"
+)
+
+const signaturePrefix = "sha256="
+
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// NewSecret returns a random secret for signing the deliveries of a webhook.
+func NewSecret() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(key), nil
+}
+
+// Sign returns the signature header value for body sent at timestamp. The
+// timestamp is signed along with the body so that old deliveries cannot be
+// replayed by a third party.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the timestamp and signature headers of a delivery, rejecting
+// deliveries signed more than tolerance ago. Receivers can use it as is.
+func Verify(secret string, timestamp string, signature string, body []byte, tolerance time.Duration) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: bad timestamp", ErrInvalidSignature)
+	}
+
+	if age := time.Since(time.Unix(ts, 0)); age > tolerance || age < -tolerance {
+		return fmt.Errorf("%w: timestamp outside tolerance", ErrInvalidSignature)
+	}
+
+	if !hmac.Equal([]byte(Sign(secret, ts, body)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}